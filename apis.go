@@ -2,6 +2,9 @@ package k2
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -9,6 +12,11 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
 )
 
 const (
@@ -19,12 +27,68 @@ const (
 	pathRegister               = "/eth/v1/register"
 	pathGetDelegatedValidators = "/eth/v1/delegated-validators"
 	pathUpdateK2Payout         = "/eth/v1/update-k2-payout-recipient"
+	pathMetrics                = "/metrics"
+	pathStream                 = "/eth/v1/stream"
+	pathHealth                 = "/eth/v1/health"
+	pathLiveness               = "/eth/v1/health/live"
+	pathReadiness              = "/eth/v1/health/ready"
+	pathAudit                  = "/eth/v1/audit"
+	pathExplain                = "/eth/v1/explain/{pubkey}"
+	pathList                   = "/eth/v1/lists/{list}"
+	pathListEntry              = "/eth/v1/lists/{list}/{key}"
+	pathValidateLists          = "/eth/v1/validate-lists"
+	pathDeferredDelegations    = "/eth/v1/deferred-delegations"
+	pathClaimRecipients        = "/eth/v1/claim-recipients"
+	pathClaimRecipient         = "/eth/v1/claim-recipients/{representative}"
+	pathRewardsWallets         = "/eth/v1/rewards-wallets"
+	pathClaimKETH              = "/eth/v1/claim-keth"
+	pathNodeOperators          = "/eth/v1/node-operators"
 )
 
 func (k2 *K2Service) handleRoot(w http.ResponseWriter, _ *http.Request) {
 	k2.respondOK(w, "K2 module is running")
 }
 
+func (k2 *K2Service) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	// Get call.
+	// Reports the state of each dependency, representative wallet balances and pending jobs.
+	// Responds with a service unavailable status if the module is not ready so it can be used as a probe.
+
+	status := k2.cachedHealth()
+	if !status.Ready {
+		k2.respond(w, http.StatusServiceUnavailable, status)
+		return
+	}
+
+	k2.respondOK(w, status)
+}
+
+func (k2 *K2Service) handleLiveness(w http.ResponseWriter, _ *http.Request) {
+	// Get call.
+	// The module is live as long as it can serve requests, regardless of the state of its dependencies,
+	// so orchestrators do not restart it when an external dependency is down.
+
+	k2.respondOK(w, "K2 module is live")
+}
+
+func (k2 *K2Service) handleReadiness(w http.ResponseWriter, _ *http.Request) {
+	// Get call.
+	// The module is ready when all required dependencies are reachable, synced and on the expected chain.
+
+	if !k2.configured {
+		k2.respondError(w, http.StatusServiceUnavailable, "module not configured")
+		return
+	}
+
+	err := k2.readinessError(k2.cachedHealth(), k2.beacon.ConnectedChainId())
+	if err != nil {
+		k2.respondError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	k2.respondOK(w, "K2 module is ready")
+}
+
 func (k2 *K2Service) handleExit(w http.ResponseWriter, r *http.Request) {
 	// Post call.
 	// Handles the removal of the validators delegated balance from the K2 contract,
@@ -69,7 +133,7 @@ func (k2 *K2Service) handleClaim(w http.ResponseWriter, r *http.Request) {
 
 	// If no node operators are provided, it will claim rewards for all the configured node operators.
 	if len(payload.NodeOperators) == 0 {
-		for _, wallet := range k2.cfg.ValidatorWallets {
+		for _, wallet := range k2.validatorWallets() {
 			payload.NodeOperators = append(payload.NodeOperators, wallet.Address)
 		}
 	}
@@ -124,6 +188,188 @@ func (k2 *K2Service) handleUpdateK2Payout(w http.ResponseWriter, r *http.Request
 
 }
 
+func (k2 *K2Service) handleGetClaimRecipients(w http.ResponseWriter, r *http.Request) {
+	// Get call.
+	// Returns the recipient each representative delegated its KETH claims to.
+	// If no representative addresses are provided, it will return the claim recipients
+	// for all the configured representative addresses.
+
+	representativeAddresses := []common.Address{}
+	if representativeAddressesStr := r.URL.Query().Get("representativeAddresses"); representativeAddressesStr != "" {
+		for _, address := range strings.Split(representativeAddressesStr, ",") {
+			if !common.IsHexAddress(address) {
+				k2.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid representative address %q", address))
+				return
+			}
+			representativeAddresses = append(representativeAddresses, common.HexToAddress(address))
+		}
+	} else {
+		for _, wallet := range k2.validatorWallets() {
+			representativeAddresses = append(representativeAddresses, wallet.Address)
+		}
+	}
+
+	result, err := k2.getClaimRecipients(representativeAddresses)
+	if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleSetClaimRecipient(w http.ResponseWriter, r *http.Request) {
+	// Post call.
+	// Handles the delegation of a representative's KETH claims to a claim recipient in the K2 contract.
+
+	type setClaimRecipientPayload struct {
+		RepresentativeAddress common.Address `json:"representativeAddress"`
+		ClaimRecipient        common.Address `json:"claimRecipient"`
+	}
+
+	payload := setClaimRecipientPayload{}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&payload)
+	if err != nil {
+		k2.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if (payload.RepresentativeAddress == common.Address{} || payload.ClaimRecipient == common.Address{}) {
+		k2.respondError(w, http.StatusBadRequest, "representativeAddress and claimRecipient are required")
+		return
+	}
+
+	result, err := k2.changeClaimRecipient(payload.RepresentativeAddress, payload.ClaimRecipient)
+	if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleClearClaimRecipient(w http.ResponseWriter, r *http.Request) {
+	// Delete call.
+	// Handles the removal of the claim recipient of the representative in the path,
+	// so its KETH claims are paid to the representative again.
+
+	representative := mux.Vars(r)["representative"]
+	if !common.IsHexAddress(representative) {
+		k2.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid representative address %q", representative))
+		return
+	}
+
+	result, err := k2.changeClaimRecipient(common.HexToAddress(representative), common.Address{})
+	if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleGetRewardsWallets(w http.ResponseWriter, r *http.Request) {
+	// Get call.
+	// Returns the claimable, claimed and held KETH of each representative.
+	// If no representative addresses are provided, it will return the rewards wallets
+	// of all the configured representative addresses.
+
+	representativeAddresses := []common.Address{}
+	if representativeAddressesStr := r.URL.Query().Get("representativeAddresses"); representativeAddressesStr != "" {
+		for _, address := range strings.Split(representativeAddressesStr, ",") {
+			if !common.IsHexAddress(address) {
+				k2.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid representative address %q", address))
+				return
+			}
+			representativeAddresses = append(representativeAddresses, common.HexToAddress(address))
+		}
+	} else {
+		for _, wallet := range k2.validatorWallets() {
+			representativeAddresses = append(representativeAddresses, wallet.Address)
+		}
+	}
+
+	result, err := k2.getRewardsWallets(representativeAddresses)
+	if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleClaimKETH(w http.ResponseWriter, r *http.Request) {
+	// Post call.
+	// Handles the claim of the KETH claimable by the representatives from the K2 lending contract,
+	// and its transfer to the treasury if one is configured.
+	// If no representative addresses are provided, it will claim the KETH
+	// of all the configured representative addresses.
+
+	type claimKETHPayload struct {
+		RepresentativeAddresses []common.Address `json:"representativeAddresses"`
+	}
+
+	payload := claimKETHPayload{}
+
+	if r.ContentLength != 0 {
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&payload)
+		if err != nil {
+			k2.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if len(payload.RepresentativeAddresses) == 0 {
+		for _, wallet := range k2.validatorWallets() {
+			payload.RepresentativeAddresses = append(payload.RepresentativeAddresses, wallet.Address)
+		}
+	}
+
+	result, err := k2.claimKETH(payload.RepresentativeAddresses)
+	if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleGetNodeOperators(w http.ResponseWriter, r *http.Request) {
+	// Get call.
+	// Returns the lend position, key counts, kicked keys, ban status and inclusion list membership
+	// of each representative in the K2 contract.
+	// If no representative addresses are provided, it will return the node operators
+	// of all the configured representative addresses.
+
+	representativeAddresses := []common.Address{}
+	if representativeAddressesStr := r.URL.Query().Get("representativeAddresses"); representativeAddressesStr != "" {
+		for _, address := range strings.Split(representativeAddressesStr, ",") {
+			if !common.IsHexAddress(address) {
+				k2.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid representative address %q", address))
+				return
+			}
+			representativeAddresses = append(representativeAddresses, common.HexToAddress(address))
+		}
+	} else {
+		for _, wallet := range k2.validatorWallets() {
+			representativeAddresses = append(representativeAddresses, wallet.Address)
+		}
+	}
+
+	result, err := k2.getNodeOperators(representativeAddresses)
+	if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
 func (k2 *K2Service) handleRegister(w http.ResponseWriter, r *http.Request) {
 	// Post call.
 	// Handles the native delegation of validators in the K2 contract if configured to do so.
@@ -142,12 +388,34 @@ func (k2 *K2Service) handleRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// exclude registrations with an invalid BLS signature, rejecting the request if none are valid
+	payload, invalid, err := k2.verifyValidatorRegistrations(payload)
+	if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(payload) == 0 && len(invalid) > 0 {
+		k2.respondError(w, http.StatusBadRequest, fmt.Sprintf("no registrations with a valid signature: %v", invalid))
+		return
+	}
+
+	k2.recordRecentRegistrations(payload)
+
 	result, err := k2.batchProcessValidatorRegistrations(payload)
 	if err != nil {
 		k2.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	k2.respondOK(w, result)
+
+	// the response stays a list of the registrations processed unless the excluded registrations are asked for
+	if r.URL.Query().Get("includeInvalid") != "true" {
+		k2.respondOK(w, result)
+		return
+	}
+	k2.respondOK(w, k2common.RegisterResult{
+		Registrations: result,
+		Invalid:       invalid,
+	})
 }
 
 func (k2 *K2Service) handleGetValidators(w http.ResponseWriter, r *http.Request) {
@@ -165,7 +433,7 @@ func (k2 *K2Service) handleGetValidators(w http.ResponseWriter, r *http.Request)
 			representativeAddresses = append(representativeAddresses, common.HexToAddress(address))
 		}
 	} else {
-		for _, wallet := range k2.cfg.ValidatorWallets {
+		for _, wallet := range k2.validatorWallets() {
 			representativeAddresses = append(representativeAddresses, wallet.Address)
 		}
 	}
@@ -189,3 +457,139 @@ func (k2 *K2Service) handleGetValidators(w http.ResponseWriter, r *http.Request)
 
 	k2.respondOK(w, result)
 }
+
+func (k2 *K2Service) handleAudit(w http.ResponseWriter, r *http.Request) {
+	// Get call.
+	// Returns the decisions recorded in the audit log, optionally filtered by validator pubkey
+	// and a time range given as RFC3339 timestamps or unix seconds.
+
+	if !k2.auditLog.Enabled() {
+		k2.respondError(w, http.StatusNotFound, "audit log not configured")
+		return
+	}
+
+	queryParams := r.URL.Query()
+
+	filter := audit.Filter{
+		ValidatorPubKey: queryParams.Get("pubkey"),
+	}
+
+	var err error
+	if from := queryParams.Get("from"); from != "" {
+		filter.From, err = parseAuditTime(from)
+		if err != nil {
+			k2.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid from time %q", from))
+			return
+		}
+	}
+	if to := queryParams.Get("to"); to != "" {
+		filter.To, err = parseAuditTime(to)
+		if err != nil {
+			k2.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid to time %q", to))
+			return
+		}
+	}
+
+	result, err := k2.auditLog.Query(filter)
+	if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleExplain(w http.ResponseWriter, r *http.Request) {
+	// Get call.
+	// Runs the registration decision logic read-only against the last registration message seen
+	// for the validator and returns every check made, without sending any transactions.
+
+	if !k2.configured {
+		k2.respondError(w, http.StatusServiceUnavailable, "module not configured")
+		return
+	}
+
+	pubkeyStr := mux.Vars(r)["pubkey"]
+	pubkeyBytes, err := hexutil.Decode(pubkeyStr)
+	if err != nil || len(pubkeyBytes) != len(phase0.BLSPubKey{}) {
+		k2.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid validator pubkey %q", pubkeyStr))
+		return
+	}
+
+	result, err := k2.explainRegistration(phase0.BLSPubKey(pubkeyBytes))
+	if errors.Is(err, errRegistrationNotSeen) {
+		k2.respondError(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		k2.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleGetList(w http.ResponseWriter, r *http.Request) {
+	// Get call.
+	// Returns the entries of the exclusion list, strict inclusion list or representative mapping
+	// as persisted in the configured file.
+
+	result, err := k2.getListEntries(mux.Vars(r)["list"])
+	if err != nil {
+		k2.respondError(w, listErrorStatus(err), err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleValidateLists(w http.ResponseWriter, _ *http.Request) {
+	// Get call.
+	// Validates the exclusion list, strict inclusion list and representative mapping against each other and the recently seen
+	// validators, returning the precedence rules applied and any contradictions, unreachable entries and unknown representatives.
+
+	result := k2.validateLists(k2.listSnapshot())
+
+	k2.respondOK(w, result)
+}
+
+func (k2 *K2Service) handleDeferredDelegations(w http.ResponseWriter, _ *http.Request) {
+	// Get call.
+	// Returns the native delegations queued by the delegation policies, longest deferred first.
+
+	k2.respondOK(w, k2.getDeferredDelegations())
+}
+
+func (k2 *K2Service) handleChangeList(w http.ResponseWriter, r *http.Request) {
+	// Post, Put and Delete call.
+	// Adds an entry to a list (POST), or updates (PUT) or removes (DELETE) the entry for the validator BLS key
+	// or fee recipient in the path. The change is validated and persisted to the configured file before being applied,
+	// and the recently seen validators the entry applies to are returned.
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		k2.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	result, err := k2.changeList(vars["list"], r.Method, vars["key"], body)
+	if err != nil {
+		k2.respondError(w, listErrorStatus(err), err.Error())
+		return
+	}
+
+	k2.respondOK(w, result)
+}
+
+func listErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errUnknownList), errors.Is(err, errListNotConfigured), errors.Is(err, errListEntryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errListEntryExists), errors.Is(err, errRemoteList):
+		return http.StatusConflict
+	case errors.Is(err, errInvalidListChange), errors.Is(err, errListEntryKeyChange):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,139 @@
+package k2
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	auditConfig "github.com/restaking-cloud/native-delegation-for-plus/audit/config"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+func (k2 *K2Service) configureAudit() error {
+
+	if k2.cfg.AuditLogFile == "" {
+		return nil
+	}
+
+	return k2.auditLog.Configure(auditConfig.AuditConfig{
+		File: k2.cfg.AuditLogFile,
+	}, k2.log)
+}
+
+// auditValidators records the same decision for each of the validators
+func (k2 *K2Service) auditValidators(validators []string, representative ethcommon.Address, decision string, outcome string, reason string, data map[string]any) {
+
+	entries := make([]audit.Entry, 0, len(validators))
+	for _, validator := range validators {
+		entry := audit.Entry{
+			ValidatorPubKey: validator,
+			Decision:        decision,
+			Outcome:         outcome,
+			Reason:          reason,
+			Data:            data,
+		}
+		if representative != (ethcommon.Address{}) {
+			entry.RepresentativeAddress = representative.String()
+		}
+		entries = append(entries, entry)
+	}
+
+	k2.auditLog.Record(entries...)
+}
+
+// auditListCheck records the outcome of checking a validator against the exclusion and strict inclusion lists
+// for an operation, along with the list entry that was matched on if any
+func (k2 *K2Service) auditListCheck(validator string, operation string, outcome string, list string, match string, reason string) {
+
+	entry := audit.Entry{
+		ValidatorPubKey: validator,
+		Decision:        audit.DecisionListCheck,
+		Outcome:         outcome,
+		Reason:          reason,
+		Operation:       operation,
+	}
+	if list != "" {
+		entry.Data = map[string]any{
+			"list":  list,
+			"match": match,
+		}
+	}
+
+	k2.auditLog.Record(entry)
+}
+
+// auditTransaction records the transaction sent for the operation on behalf of the validators,
+// or the reason it could not be sent
+func (k2 *K2Service) auditTransaction(operation string, representative ethcommon.Address, validators []string, tx *types.Transaction, txErr error) {
+
+	entry := audit.Entry{
+		RepresentativeAddress: representative.String(),
+		Decision:              audit.DecisionTransaction,
+		Outcome:               audit.OutcomeExecuted,
+		Operation:             operation,
+	}
+	if txErr != nil {
+		entry.Outcome = audit.OutcomeFailed
+		entry.Reason = txErr.Error()
+	} else if tx != nil {
+		nonce := tx.Nonce()
+		entry.TxHash = tx.Hash().String()
+		entry.Nonce = &nonce
+	}
+
+	if len(validators) == 0 {
+		k2.auditLog.Record(entry)
+		return
+	}
+
+	entries := make([]audit.Entry, 0, len(validators))
+	for _, validator := range validators {
+		e := entry
+		e.ValidatorPubKey = validator
+		entries = append(entries, e)
+	}
+
+	k2.auditLog.Record(entries...)
+}
+
+// capacityData reports the native delegation capacity a capacity check was made against
+func capacityData(globalMax *big.Int, globalCurrent *big.Int, individualMax *big.Int, individualCurrent *big.Int) map[string]any {
+	data := make(map[string]any)
+	if globalMax != nil && globalCurrent != nil {
+		data["globalMax"] = globalMax.String()
+		data["globalConsumed"] = globalCurrent.String()
+	}
+	if individualMax != nil && individualCurrent != nil {
+		data["individualMax"] = individualMax.String()
+		data["individualConsumed"] = individualCurrent.String()
+	}
+	return data
+}
+
+func resignAuditData(payloadFeeRecipient string, payoutRecipient ethcommon.Address) map[string]any {
+	return map[string]any{
+		"payloadFeeRecipient":     payloadFeeRecipient,
+		"selectedPayoutRecipient": payoutRecipient.String(),
+	}
+}
+
+func registrationKeys(registrations map[string]k2common.K2ValidatorRegistration) []string {
+	keys := make([]string, 0, len(registrations))
+	for key := range registrations {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// parseAuditTime accepts either an RFC3339 timestamp or unix seconds
+func parseAuditTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,5 @@
+package config
+
+type AuditConfig struct {
+	File string // the JSON lines file decisions are appended to
+}
@@ -0,0 +1,37 @@
+package audit
+
+const (
+	// Decisions recorded for each validator
+	DecisionListCheck        = "list_check"
+	DecisionCapacityCheck    = "capacity_check"
+	DecisionRepresentative   = "representative_check"
+	DecisionWeb3SignerResign = "web3signer_resign"
+	DecisionSignatureSwapper = "signature_swapper"
+	DecisionTransaction      = "transaction"
+	DecisionDelegationPolicy = "delegation_policy"
+	DecisionNodeOperator     = "node_operator_check"
+	DecisionBLSSignature     = "bls_signature_check"
+)
+
+const (
+	OutcomeAllowed  = "allowed"
+	OutcomeSkipped  = "skipped"
+	OutcomeSigned   = "signed"
+	OutcomeFailed   = "failed"
+	OutcomeExecuted = "executed"
+	OutcomeDeferred = "deferred"
+)
+
+const (
+	// Lists a validator can be matched against
+	ListExclusion = "exclusion"
+	ListInclusion = "strict_inclusion"
+
+	// What a list entry was matched on
+	MatchPubKey       = "pubkey"
+	MatchFeeRecipient = "fee_recipient"
+	MatchRule         = "rule"
+)
+
+// maxEntrySize is the largest audit entry that can be read back from the log
+const maxEntrySize = 1024 * 1024
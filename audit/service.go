@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit/config"
+)
+
+// AuditService appends every decision taken for a validator to a JSON lines file
+// so that it can be queried after the fact
+type AuditService struct {
+	cfg  config.AuditConfig
+	log  *logrus.Entry
+	file *os.File
+	lock sync.Mutex
+}
+
+func NewAuditService() *AuditService {
+	return &AuditService{}
+}
+
+func (s *AuditService) Configure(cfg config.AuditConfig, logger *logrus.Entry) error {
+
+	if cfg.File == "" {
+		return fmt.Errorf("auditservice: no file set, cannot configure service")
+	}
+
+	file, err := os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("auditservice: failed to open audit log: %w", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.cfg = cfg
+	s.log = logger.WithField("service", "audit")
+	s.file = file
+
+	return nil
+}
+
+func (s *AuditService) Enabled() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.file != nil
+}
+
+// Record appends the entries to the audit log, entries are only ever appended and never rewritten
+func (s *AuditService) Record(entries ...Entry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, entry := range entries {
+		if entry.Timestamp.IsZero() {
+			entry.Timestamp = now
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			s.log.WithError(err).WithField("decision", entry.Decision).Error("Failed to encode audit entry")
+			continue
+		}
+		if _, err := s.file.Write(append(line, '\n')); err != nil {
+			s.log.WithError(err).WithField("decision", entry.Decision).Error("Failed to write audit entry")
+		}
+	}
+}
+
+// Query returns the entries in the audit log matching the filter, in the order they were recorded. Lines that cannot
+// be decoded are logged and skipped
+func (s *AuditService) Query(filter Filter) ([]Entry, error) {
+
+	s.lock.Lock()
+	if s.file == nil {
+		s.lock.Unlock()
+		return nil, fmt.Errorf("audit log not configured")
+	}
+	// only read what had been written when the query started, so a line still being written is never read
+	info, err := s.file.Stat()
+	s.lock.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	file, err := os.Open(s.cfg.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	filter.ValidatorPubKey = strings.ToLower(filter.ValidatorPubKey)
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(io.LimitReader(file, info.Size()))
+	scanner.Buffer(make([]byte, 64*1024), maxEntrySize)
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// a corrupted line, such as one torn by a crash while it was written, does not hide the rest of the log
+			s.log.WithError(err).WithField("line", line).Warn("Skipping audit log line that cannot be decoded")
+			continue
+		}
+		entry.ValidatorPubKey = strings.ToLower(entry.ValidatorPubKey)
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *AuditService) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
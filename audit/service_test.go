@@ -0,0 +1,95 @@
+package audit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	"github.com/restaking-cloud/native-delegation-for-plus/audit/config"
+)
+
+func TestAuditService_Query(t *testing.T) {
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorded := []audit.Entry{
+		{Timestamp: start, ValidatorPubKey: "0xAA", Decision: audit.DecisionListCheck, Outcome: audit.OutcomeAllowed},
+		{Timestamp: start.Add(time.Hour), ValidatorPubKey: "0xbb", Decision: audit.DecisionListCheck, Outcome: audit.OutcomeSkipped},
+		{Timestamp: start.Add(2 * time.Hour), ValidatorPubKey: "0xaa", Decision: audit.DecisionTransaction, Outcome: audit.OutcomeExecuted},
+	}
+
+	tests := []struct {
+		name          string
+		filter        audit.Filter
+		malformed     string // written to the log between the recorded entries
+		wantDecisions []string
+	}{
+		{
+			name:          "all entries in the order recorded",
+			wantDecisions: []string{audit.DecisionListCheck, audit.DecisionListCheck, audit.DecisionTransaction},
+		},
+		{
+			name:          "by validator ignoring case",
+			filter:        audit.Filter{ValidatorPubKey: "0xaA"},
+			wantDecisions: []string{audit.DecisionListCheck, audit.DecisionTransaction},
+		},
+		{
+			name:          "by time range",
+			filter:        audit.Filter{From: start.Add(time.Minute), To: start.Add(time.Hour)},
+			wantDecisions: []string{audit.DecisionListCheck},
+		},
+		{
+			name:          "skips lines that cannot be decoded",
+			malformed:     "{\"timestamp\":\"2024-01-01T00:3",
+			wantDecisions: []string{audit.DecisionListCheck, audit.DecisionListCheck, audit.DecisionTransaction},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "audit.jsonl")
+
+			s := audit.NewAuditService()
+			if err := s.Configure(config.AuditConfig{File: file}, logrus.NewEntry(logrus.New())); err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			s.Record(recorded[0])
+			if tt.malformed != "" {
+				f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := f.WriteString(tt.malformed + "\n"); err != nil {
+					t.Fatal(err)
+				}
+				f.Close()
+			}
+			s.Record(recorded[1:]...)
+
+			entries, err := s.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if len(entries) != len(tt.wantDecisions) {
+				t.Fatalf("Query() returned %d entries, want %d", len(entries), len(tt.wantDecisions))
+			}
+			for i, entry := range entries {
+				if entry.Decision != tt.wantDecisions[i] {
+					t.Errorf("entry %d decision = %s, want %s", i, entry.Decision, tt.wantDecisions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAuditService_QueryNotConfigured(t *testing.T) {
+
+	if _, err := audit.NewAuditService().Query(audit.Filter{}); err == nil {
+		t.Error("Query() without an audit log returned no error")
+	}
+}
@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"time"
+)
+
+type Entry struct {
+	Timestamp             time.Time      `json:"timestamp"`
+	ValidatorPubKey       string         `json:"validatorPubKey,omitempty"`
+	RepresentativeAddress string         `json:"representativeAddress,omitempty"`
+	Decision              string         `json:"decision"`
+	Outcome               string         `json:"outcome"`
+	Reason                string         `json:"reason,omitempty"`
+	Operation             string         `json:"operation,omitempty"`
+	TxHash                string         `json:"txHash,omitempty"`
+	Nonce                 *uint64        `json:"nonce,omitempty"`
+	Data                  map[string]any `json:"data,omitempty"`
+}
+
+// Filter selects the entries returned from the audit log, unset fields match every entry
+type Filter struct {
+	ValidatorPubKey string
+	From            time.Time
+	To              time.Time
+}
+
+func (f Filter) matches(entry Entry) bool {
+	if f.ValidatorPubKey != "" && f.ValidatorPubKey != entry.ValidatorPubKey {
+		return false
+	}
+	if !f.From.IsZero() && entry.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && entry.Timestamp.After(f.To) {
+		return false
+	}
+	return true
+}
@@ -6,6 +6,6 @@ import (
 )
 
 type BalanceVerifierConfig struct {
-	Url *url.URL
+	Urls []*url.URL
 	ChainID *big.Int
 }
\ No newline at end of file
@@ -15,43 +15,96 @@ import (
 
 	"github.com/restaking-cloud/native-delegation-for-plus/balanceverifier/config"
 	k2Common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
 )
 
 type BalanceVerifierService struct {
-	client *http.Client
-	cfg    config.BalanceVerifierConfig
+	client    *http.Client
+	cfg       config.BalanceVerifierConfig
+	endpoints *k2Common.EndpointPool
 }
 
 func NewBalanceVerifierService() *BalanceVerifierService {
 	return &BalanceVerifierService{
-		client: &http.Client{Timeout: 192 * time.Second},
+		client: &http.Client{
+			Timeout:   192 * time.Second,
+			Transport: metrics.NewTransport(metrics.DependencyBalanceVerifier),
+		},
 	}
 }
 
-func (s *BalanceVerifierService) Configure(url *url.URL) error {
+// Configure sets the balance verifier endpoints in order of preference. Every reachable endpoint must report the
+// same chain id, endpoints that cannot be reached are checked before they are first used
+func (s *BalanceVerifierService) Configure(urls []*url.URL) error {
 
-	if url == nil {
+	if len(urls) == 0 {
 		return fmt.Errorf("balanceverifierservice: url not set, cannot configure service")
 	}
 
-	s.cfg.Url = url
+	s.cfg.Urls = urls
+	s.cfg.ChainID = nil
 
-	info, err := s.GetInfo()
-	if err != nil {
-		return fmt.Errorf("balanceverifierservice: failed to get info: %w", err)
+	verified := make(map[*url.URL]bool)
+	var lastErr error
+	for _, u := range urls {
+		info, err := s.getInfo(u)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if s.cfg.ChainID == nil {
+			s.cfg.ChainID = big.NewInt(int64(info.ChainID))
+		} else if err := s.checkInfo(info); err != nil {
+			return fmt.Errorf("balanceverifierservice: %s %w", u.Redacted(), err)
+		}
+		verified[u] = true
 	}
 
-	s.cfg.ChainID = big.NewInt(int64(info.ChainID))
+	if len(verified) == 0 {
+		return fmt.Errorf("balanceverifierservice: failed to get info: %w", lastErr)
+	}
+
+	s.endpoints = k2Common.NewEndpointPool(urls, verified, s.verifyEndpoint)
 
 	return nil
 }
 
+// verifyEndpoint checks an endpoint that could not be reached at configuration serves the configured chain
+func (s *BalanceVerifierService) verifyEndpoint(u *url.URL) error {
+	info, err := s.getInfo(u)
+	if err != nil {
+		return err
+	}
+	return s.checkInfo(info)
+}
+
+func (s *BalanceVerifierService) checkInfo(info Info) error {
+	if info.ChainID != s.cfg.ChainID.Uint64() {
+		return fmt.Errorf("reports chain id %v, expected chain id %v", info.ChainID, s.cfg.ChainID)
+	}
+	return nil
+}
+
+// Endpoints returns the circuit state of each configured endpoint
+func (s *BalanceVerifierService) Endpoints() []k2Common.EndpointHealth {
+	return s.endpoints.Health()
+}
+
 func (s *BalanceVerifierService) ConnectedChainId() *big.Int {
 	return s.cfg.ChainID
 }
 
-func (s *BalanceVerifierService) GetInfo() (Info, error) {
-	req, err := http.NewRequestWithContext(context.Background(), "GET", s.cfg.Url.String()+InfoPath, nil)
+// GetInfo returns the info of the first available endpoint
+func (s *BalanceVerifierService) GetInfo() (info Info, err error) {
+	err = s.endpoints.Do(func(u *url.URL) error {
+		info, err = s.getInfo(u)
+		return err
+	})
+	return info, err
+}
+
+func (s *BalanceVerifierService) getInfo(u *url.URL) (Info, error) {
+	req, err := http.NewRequestWithContext(context.Background(), "GET", u.String()+InfoPath, nil)
 	if err != nil {
 		return Info{}, err
 	}
@@ -60,9 +113,15 @@ func (s *BalanceVerifierService) GetInfo() (Info, error) {
 	if err != nil {
 		return Info{}, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return Info{}, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, resp)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return Info{}, fmt.Errorf("error reading invalid response (%d) body: %w", resp.StatusCode, err)
+		}
+
+		return Info{}, &k2Common.StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var response Info
@@ -94,7 +153,26 @@ func (s *BalanceVerifierService) ReportEffectiveBalance(
 		return res, err
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), "POST", s.cfg.Url.String()+VerifyEffectiveBalancePath, bytes.NewReader(payloadBytes))
+	err = s.endpoints.Do(func(u *url.URL) error {
+		signatures, err := s.reportEffectiveBalance(u, len(effectiveBalances), payloadBytes)
+		if err != nil {
+			return err
+		}
+		res = signatures
+		return nil
+	})
+	return res, err
+}
+
+func (s *BalanceVerifierService) reportEffectiveBalance(
+	u *url.URL,
+	reportCount int,
+	payloadBytes []byte,
+) (res map[phase0.BLSPubKey]k2Common.EcdsaSignature, err error) {
+
+	res = make(map[phase0.BLSPubKey]k2Common.EcdsaSignature)
+
+	req, err := http.NewRequestWithContext(context.Background(), "POST", u.String()+VerifyEffectiveBalancePath, bytes.NewReader(payloadBytes))
 	if err != nil {
 		return res, err
 	}
@@ -103,14 +181,15 @@ func (s *BalanceVerifierService) ReportEffectiveBalance(
 	if err != nil {
 		return res, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return res, fmt.Errorf("error reading invalid response (%d) body: %v", resp.StatusCode, err)
+			return res, fmt.Errorf("error reading invalid response (%d) body: %w", resp.StatusCode, err)
 		}
 
-		return res, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, string(body))
+		return res, &k2Common.StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var response ReportEffectiveBalanceResponse
@@ -119,7 +198,7 @@ func (s *BalanceVerifierService) ReportEffectiveBalance(
 	}
 
 	// ensure the length of the response is the same as the length of the request
-	if len(response.Responses) != len(effectiveBalances) {
+	if len(response.Responses) != reportCount {
 		return res, fmt.Errorf("invalid response length: %d", len(response.Responses))
 	}
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -13,7 +14,11 @@ import (
 )
 
 func (b *BeaconService) Status() (res *SyncStatusData, err error) {
-	return b.syncProgress(context.Background())
+	return b.syncProgress(context.Background(), b.nodeUrl())
+}
+
+func (b *BeaconService) NetworkChainId() (*big.Int, error) {
+	return b.networkID(context.Background(), b.nodeUrl())
 }
 
 func (b *BeaconService) FinalizedValidatorEffectiveBalance(blsKeys []phase0.BLSPubKey) (res map[phase0.BLSPubKey]uint64, err error) {
@@ -37,19 +42,47 @@ func (b *BeaconService) FinalizedValidatorEffectiveBalance(blsKeys []phase0.BLSP
 
 }
 
+func (b *BeaconService) FinalizedValidatorIndices(blsKeys []phase0.BLSPubKey) (res map[phase0.BLSPubKey]uint64, err error) {
+
+	res = make(map[phase0.BLSPubKey]uint64)
+
+	if len(blsKeys) == 0 {
+		return res, nil
+	}
+
+	validatorInfo, err := b.getValidatorsFinalizedInfo(context.Background(), blsKeys)
+	if err != nil {
+		return res, err
+	}
+
+	for _, v := range validatorInfo {
+		res[v.Validator.Pubkey] = v.Index
+	}
+
+	return res, nil
+
+}
+
 func (b *BeaconService) SubscribeToHeadEvents(ctx context.Context, headEvent chan<- HeadEventData) error {
 	/*
 		Subscribe to head events from the beacon chain
 		Events are sent to the headChannel
 	*/
 	logger := logrus.WithField("moduleExecution", "k2")
-	logger.Debugf("Starting head events subscription to node:%s", b.cfg.BeaconNodeUrl.String())
 	defer logger.Debugf("Head events subscription ended")
 
 	for {
-		client := sse.NewClient(fmt.Sprintf("%s/eth/v1/events?topics=head", b.cfg.BeaconNodeUrl.String()))
+		// the subscription is cancelled to reconnect when the beacon node in use fails over
+		subscriptionCtx, cancel := context.WithCancel(ctx)
+		b.nodeLock.Lock()
+		b.cancelHeadEvents = cancel
+		nodeUrl := b.activeNode.url
+		b.nodeLock.Unlock()
+
+		logger.Debugf("Starting head events subscription to node:%s", nodeUrl.Redacted())
+		client := sse.NewClient(fmt.Sprintf("%s/eth/v1/events?topics=head", nodeUrl.String()))
 		// Use sse client to subscribe to events
-		err := client.SubscribeRawWithContext(ctx, func(msg *sse.Event) {
+		err := client.SubscribeRawWithContext(subscriptionCtx, func(msg *sse.Event) {
 			var event HeadEventData
 			if err := json.Unmarshal(msg.Data, &event); err != nil {
 				logger.Warn("Head event subscription failed", "error", err)
@@ -63,10 +96,17 @@ func (b *BeaconService) SubscribeToHeadEvents(ctx context.Context, headEvent cha
 			headEvent <- event
 		})
 
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		cancel()
+
+		if ctx.Err() != nil {
 			return nil
 		}
 
+		if errors.Is(err, context.Canceled) {
+			logger.Info("Beacon node failed over, reconnecting head events subscription")
+			continue
+		}
+
 		if err != nil {
 			logger.Error("Failed to subscribe to head events")
 			time.Sleep(1 * time.Second)
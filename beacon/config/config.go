@@ -3,9 +3,13 @@ package config
 import (
 	"net/url"
 	"math/big"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
 type BeaconConfig struct {
 	BeaconNodeUrl *url.URL
+	FallbackBeaconNodeUrls []*url.URL // failed over to in order of preference when the beacon node is unhealthy
 	ChainID *big.Int
+	GenesisForkVersion phase0.Version
 }
\ No newline at end of file
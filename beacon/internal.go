@@ -2,48 +2,27 @@ package beacon
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
-func (b *BeaconService) connect(url *url.URL) error {
-	b.cfg.BeaconNodeUrl = url
-
-	ctx := context.Background()
-
-	id, err := b.networkID(ctx)
-	if err != nil {
-		return err
-	}
-	b.cfg.ChainID = id
-
-	synced, err := b.syncProgress(ctx)
+func (b *BeaconService) networkID(ctx context.Context, node *url.URL) (chainId *big.Int, err error) {
+	spec, err := b.getSpec(ctx, node)
 	if err != nil {
-		return err
-	}
-
-	if synced == nil || synced.IsSyncing {
-		return fmt.Errorf("beacon node not synced")
-	}
-
-	if synced.ElOffline {
-		return fmt.Errorf("beacon node not synced, execution layer offline")
+		return chainId, err
 	}
 
-	return nil
+	return specChainID(spec)
 }
 
-func (b *BeaconService) networkID(ctx context.Context) (chainId *big.Int, err error) {
-	spec, err := b.getSpec(ctx)
-	if err != nil {
-		return chainId, err
-	}
-
+func specChainID(spec map[string]interface{}) (chainId *big.Int, err error) {
 	id, ok := spec["DEPOSIT_CHAIN_ID"].(string)
 	if !ok {
 		return chainId, fmt.Errorf("invalid chain id")
@@ -57,8 +36,23 @@ func (b *BeaconService) networkID(ctx context.Context) (chainId *big.Int, err er
 	return chainId, nil
 }
 
-func (b *BeaconService) getSpec(ctx context.Context) (res map[string]interface{}, err error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", b.cfg.BeaconNodeUrl.String()+SpecPath, nil)
+func specGenesisForkVersion(spec map[string]interface{}) (version phase0.Version, err error) {
+	versionStr, ok := spec["GENESIS_FORK_VERSION"].(string)
+	if !ok {
+		return version, fmt.Errorf("invalid genesis fork version")
+	}
+
+	versionBytes, err := hex.DecodeString(strings.TrimPrefix(versionStr, "0x"))
+	if err != nil || len(versionBytes) != len(version) {
+		return version, fmt.Errorf("invalid genesis fork version")
+	}
+	copy(version[:], versionBytes)
+
+	return version, nil
+}
+
+func (b *BeaconService) getSpec(ctx context.Context, node *url.URL) (res map[string]interface{}, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", node.String()+SpecPath, nil)
 	if err != nil {
 		return res, err
 	}
@@ -67,6 +61,7 @@ func (b *BeaconService) getSpec(ctx context.Context) (res map[string]interface{}
 	if err != nil {
 		return res, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		return res, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, resp)
@@ -82,8 +77,8 @@ func (b *BeaconService) getSpec(ctx context.Context) (res map[string]interface{}
 	return response.Data, nil
 }
 
-func (b *BeaconService) syncProgress(ctx context.Context) (res *SyncStatusData, err error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", b.cfg.BeaconNodeUrl.String()+SyncPath, nil)
+func (b *BeaconService) syncProgress(ctx context.Context, node *url.URL) (res *SyncStatusData, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", node.String()+SyncPath, nil)
 	if err != nil {
 		return res, err
 	}
@@ -92,6 +87,7 @@ func (b *BeaconService) syncProgress(ctx context.Context) (res *SyncStatusData,
 	if err != nil {
 		return res, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		return res, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, resp)
@@ -116,7 +112,7 @@ func (b *BeaconService) getValidatorsFinalizedInfo(ctx context.Context, blsKeys
 		}
 	}
 
-	url := b.cfg.BeaconNodeUrl.String() + FinalizedValidatorsPath + queryKeys
+	url := b.nodeUrl().String() + FinalizedValidatorsPath + queryKeys
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return res, err
@@ -126,6 +122,7 @@ func (b *BeaconService) getValidatorsFinalizedInfo(ctx context.Context, blsKeys
 	if err != nil {
 		return res, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode == 414 {
 		// list of keys too long so split batch
@@ -0,0 +1,206 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+// MaxSyncDistance is the number of slots a beacon node can be behind the head and still be used
+const MaxSyncDistance = 2
+
+var errChainMismatch = errors.New("chain mismatch")
+
+type beaconNode struct {
+	url           *url.URL
+	health        k2common.EndpointHealth
+	chainMismatch bool
+}
+
+// nodeUrl returns the url of the beacon node in use
+func (b *BeaconService) nodeUrl() *url.URL {
+	b.nodeLock.RLock()
+	defer b.nodeLock.RUnlock()
+	return b.activeNode.url
+}
+
+// checkNode checks the node is reachable, on the configured chain and synced to within MaxSyncDistance slots.
+// If no chain is configured yet the node sets it
+func (b *BeaconService) checkNode(node *beaconNode) (health k2common.EndpointHealth, err error) {
+
+	health = node.health
+	health.Healthy = false
+
+	defer func() {
+		if err != nil {
+			health.ConsecutiveFailures++
+			health.LastError = err.Error()
+		} else {
+			health.ConsecutiveFailures = 0
+			health.LastError = ""
+		}
+	}()
+
+	ctx := context.Background()
+
+	spec, err := b.getSpec(ctx, node.url)
+	if err != nil {
+		return health, err
+	}
+	id, err := specChainID(spec)
+	if err != nil {
+		return health, err
+	}
+	forkVersion, err := specGenesisForkVersion(spec)
+	if err != nil {
+		return health, err
+	}
+	if err := b.checkChain(id, forkVersion); err != nil {
+		return health, err
+	}
+	health.Verified = true
+
+	synced, err := b.syncProgress(ctx, node.url)
+	if err != nil {
+		return health, err
+	}
+	if synced == nil {
+		return health, fmt.Errorf("beacon node not synced")
+	}
+	health.SyncDistance = synced.SyncDistance
+	if synced.IsSyncing {
+		return health, fmt.Errorf("beacon node not synced, syncing %d slots behind", synced.SyncDistance)
+	}
+	if synced.SyncDistance > MaxSyncDistance {
+		return health, fmt.Errorf("beacon node not synced, %d slots behind", synced.SyncDistance)
+	}
+	if synced.ElOffline {
+		return health, fmt.Errorf("beacon node not synced, execution layer offline")
+	}
+
+	health.Healthy = true
+	return health, nil
+}
+
+// checkChain checks a node reports the configured chain, setting the chain from the node if none is configured yet
+func (b *BeaconService) checkChain(id *big.Int, forkVersion phase0.Version) error {
+	b.nodeLock.Lock()
+	defer b.nodeLock.Unlock()
+
+	if b.cfg.ChainID == nil {
+		b.cfg.ChainID = id
+		b.cfg.GenesisForkVersion = forkVersion
+	} else if id.Cmp(b.cfg.ChainID) != 0 || forkVersion != b.cfg.GenesisForkVersion {
+		return fmt.Errorf("%w: beacon node reports chain id %v and genesis fork version %#x, expected %v and %#x", errChainMismatch, id, forkVersion, b.cfg.ChainID, b.cfg.GenesisForkVersion)
+	}
+	return nil
+}
+
+// CheckNodes checks the health of every configured beacon node and switches to the first healthy node in order
+// of preference, reconnecting the head events subscription to it. The node in use is kept if none are healthy
+func (b *BeaconService) CheckNodes() error {
+
+	b.checkLock.Lock()
+	defer b.checkLock.Unlock()
+
+	checked := make([]k2common.EndpointHealth, len(b.nodes))
+	var selected *beaconNode
+	for i, node := range b.nodes {
+		var err error
+		checked[i], err = b.checkNode(node)
+		node.chainMismatch = errors.Is(err, errChainMismatch)
+		if selected == nil && checked[i].Healthy {
+			selected = node
+		}
+	}
+
+	b.nodeLock.Lock()
+	defer b.nodeLock.Unlock()
+
+	for i, node := range b.nodes {
+		node.health = checked[i]
+	}
+
+	if selected == nil {
+		if b.activeNode == nil {
+			return fmt.Errorf("no beacon node synced and on the expected chain: %s", nodeErrors(b.nodes))
+		}
+		return fmt.Errorf("no beacon node healthy, keeping %s: %s", b.activeNode.url.Redacted(), nodeErrors(b.nodes))
+	}
+
+	if selected != b.activeNode {
+		if b.activeNode != nil {
+			b.log.WithFields(logrus.Fields{
+				"from": b.activeNode.url.Redacted(),
+				"to":   selected.url.Redacted(),
+			}).Warn("Beacon node failover")
+		}
+		b.activeNode = selected
+		b.cfg.BeaconNodeUrl = selected.url
+
+		// the head events subscription reconnects to the node now in use
+		if b.cancelHeadEvents != nil {
+			b.cancelHeadEvents()
+		}
+	}
+
+	return nil
+}
+
+// Nodes returns the health of each configured beacon node as last checked, in order of preference
+func (b *BeaconService) Nodes() []k2common.EndpointHealth {
+	b.nodeLock.RLock()
+	defer b.nodeLock.RUnlock()
+
+	nodes := make([]k2common.EndpointHealth, len(b.nodes))
+	for i, node := range b.nodes {
+		nodes[i] = node.health
+		nodes[i].Active = node == b.activeNode
+	}
+	return nodes
+}
+
+func nodeErrors(nodes []*beaconNode) string {
+	var errs string
+	for i, node := range nodes {
+		if i > 0 {
+			errs += "; "
+		}
+		errs += node.url.Redacted() + ": " + node.health.LastError
+	}
+	return errs
+}
+
+// connect sets the beacon nodes in order of preference and selects the first healthy node. Every node that can be
+// reached must report the same chain, nodes that cannot be reached are checked again with CheckNodes
+func (b *BeaconService) connect(urls []*url.URL) error {
+
+	if len(urls) == 0 {
+		return fmt.Errorf("beacon node url not set")
+	}
+
+	b.nodes = make([]*beaconNode, 0, len(urls))
+	for _, u := range urls {
+		b.nodes = append(b.nodes, &beaconNode{
+			url:    u,
+			health: k2common.EndpointHealth{Url: u.Redacted()},
+		})
+	}
+
+	err := b.CheckNodes()
+	for _, node := range b.nodes {
+		// a reachable node that does not agree on the chain is misconfigured rather than unavailable
+		if node.chainMismatch {
+			return fmt.Errorf("%s: %s", node.url.Redacted(), node.health.LastError)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,210 @@
+package beacon_test
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/beacon"
+	"github.com/restaking-cloud/native-delegation-for-plus/beacon/config"
+	"github.com/restaking-cloud/native-delegation-for-plus/internal/testserver"
+)
+
+func TestBeaconService_Configure(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		chainID       *big.Int // configured chain, set from the nodes if nil
+		primary       func(*testserver.Beacon)
+		fallback      func(*testserver.Beacon)
+		wantErr       string
+		wantActive    int // index of the node in use
+		wantLastError string
+	}{
+		{
+			name:       "primary healthy",
+			wantActive: 0,
+		},
+		{
+			name:          "primary syncing",
+			primary:       func(n *testserver.Beacon) { n.IsSyncing, n.SyncDistance = true, 1 },
+			wantActive:    1,
+			wantLastError: "beacon node not synced, syncing 1 slots behind",
+		},
+		{
+			name:          "primary behind",
+			primary:       func(n *testserver.Beacon) { n.SyncDistance = beacon.MaxSyncDistance + 1 },
+			wantActive:    1,
+			wantLastError: fmt.Sprintf("beacon node not synced, %d slots behind", beacon.MaxSyncDistance+1),
+		},
+		{
+			name:       "primary within sync distance",
+			primary:    func(n *testserver.Beacon) { n.SyncDistance = beacon.MaxSyncDistance },
+			wantActive: 0,
+		},
+		{
+			name:          "primary execution layer offline",
+			primary:       func(n *testserver.Beacon) { n.ELOffline = true },
+			wantActive:    1,
+			wantLastError: "execution layer offline",
+		},
+		{
+			name:          "primary unreachable",
+			primary:       func(n *testserver.Beacon) { n.Down = true },
+			wantActive:    1,
+			wantLastError: "invalid response (503)",
+		},
+		{
+			name:     "fallback on another chain",
+			fallback: func(n *testserver.Beacon) { n.ChainID = "5" },
+			wantErr:  "chain mismatch",
+		},
+		{
+			name:     "fallback on another fork",
+			fallback: func(n *testserver.Beacon) { n.ForkVersion = "0x00001020" },
+			wantErr:  "chain mismatch",
+		},
+		{
+			name:    "nodes on another chain than configured",
+			chainID: big.NewInt(5),
+			wantErr: "chain mismatch",
+		},
+		{
+			name:     "no node healthy",
+			primary:  func(n *testserver.Beacon) { n.IsSyncing = true },
+			fallback: func(n *testserver.Beacon) { n.Down = true },
+			wantErr:  "no beacon node synced and on the expected chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := testserver.NewBeacon(t)
+			fallback := testserver.NewBeacon(t)
+			if tt.primary != nil {
+				primary.Set(tt.primary)
+			}
+			if tt.fallback != nil {
+				fallback.Set(tt.fallback)
+			}
+
+			b := beacon.NewBeaconService()
+			cfg := config.BeaconConfig{
+				BeaconNodeUrl:          primary.URL,
+				FallbackBeaconNodeUrls: []*url.URL{fallback.URL},
+				ChainID:                tt.chainID,
+			}
+			err := b.Configure(cfg, logrus.NewEntry(logrus.New()))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Configure() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Configure() error = %v", err)
+			}
+
+			if id := b.ConnectedChainId(); id == nil || id.Cmp(big.NewInt(1)) != 0 {
+				t.Errorf("connected chain id = %v, want 1", id)
+			}
+			if version := b.GenesisForkVersion(); version != (phase0.Version{}) {
+				t.Errorf("genesis fork version = %#x, want 0x00000000", version)
+			}
+
+			nodes := b.Nodes()
+			for i, node := range nodes {
+				if node.Active != (i == tt.wantActive) {
+					t.Errorf("node %d active = %v, want %v", i, node.Active, i == tt.wantActive)
+				}
+			}
+			if !strings.Contains(nodes[0].LastError, tt.wantLastError) {
+				t.Errorf("primary last error = %q, want %q", nodes[0].LastError, tt.wantLastError)
+			}
+			if (nodes[0].LastError == "") != nodes[0].Healthy {
+				t.Errorf("primary healthy = %v with last error %q", nodes[0].Healthy, nodes[0].LastError)
+			}
+		})
+	}
+}
+
+func TestBeaconService_CheckNodes(t *testing.T) {
+
+	primary := testserver.NewBeacon(t)
+	fallback := testserver.NewBeacon(t)
+
+	b := beacon.NewBeaconService()
+	err := b.Configure(config.BeaconConfig{
+		BeaconNodeUrl:          primary.URL,
+		FallbackBeaconNodeUrls: []*url.URL{fallback.URL},
+	}, logrus.NewEntry(logrus.New()))
+	if err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	steps := []struct {
+		name         string
+		update       func()
+		wantErr      string
+		wantActive   int
+		wantFailures []int // consecutive failures of each node
+	}{
+		{
+			name:         "fails over when the primary falls behind",
+			update:       func() { primary.Set(func(n *testserver.Beacon) { n.IsSyncing, n.SyncDistance = true, 64 }) },
+			wantActive:   1,
+			wantFailures: []int{1, 0},
+		},
+		{
+			name:         "keeps the fallback while the primary is unhealthy",
+			update:       func() {},
+			wantActive:   1,
+			wantFailures: []int{2, 0},
+		},
+		{
+			name:         "keeps the node in use when none are healthy",
+			update:       func() { fallback.Set(func(n *testserver.Beacon) { n.Down = true }) },
+			wantErr:      "no beacon node healthy, keeping " + fallback.URL.Redacted(),
+			wantActive:   1,
+			wantFailures: []int{3, 1},
+		},
+		{
+			name:         "switches back to the primary once synced",
+			update:       func() { primary.Set(func(n *testserver.Beacon) { n.IsSyncing, n.SyncDistance = false, 0 }) },
+			wantActive:   0,
+			wantFailures: []int{0, 2},
+		},
+		{
+			name:         "does not use a node that moved to another chain",
+			update:       func() { primary.Set(func(n *testserver.Beacon) { n.ChainID = "5" }) },
+			wantErr:      "chain mismatch",
+			wantActive:   0,
+			wantFailures: []int{1, 3},
+		},
+	}
+
+	for _, step := range steps {
+		step.update()
+
+		err := b.CheckNodes()
+		if step.wantErr == "" && err != nil {
+			t.Fatalf("%s: CheckNodes() error = %v", step.name, err)
+		} else if step.wantErr != "" && (err == nil || !strings.Contains(err.Error(), step.wantErr)) {
+			t.Fatalf("%s: CheckNodes() error = %v, want %q", step.name, err, step.wantErr)
+		}
+
+		for i, node := range b.Nodes() {
+			if node.Active != (i == step.wantActive) {
+				t.Errorf("%s: node %d active = %v, want %v", step.name, i, node.Active, i == step.wantActive)
+			}
+			if node.ConsecutiveFailures != step.wantFailures[i] {
+				t.Errorf("%s: node %d consecutive failures = %d, want %d", step.name, i, node.ConsecutiveFailures, step.wantFailures[i])
+			}
+		}
+	}
+}
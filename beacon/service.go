@@ -1,34 +1,51 @@
 package beacon
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+
 	"github.com/restaking-cloud/native-delegation-for-plus/beacon/config"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
 )
 
 type BeaconService struct {
 	cfg    config.BeaconConfig
 	client *http.Client
+	log    *logrus.Entry
 
 	mu sync.Mutex
 
 	currentSlot uint64
+
+	nodeLock         sync.RWMutex // guards the node in use, the health of the nodes and the chain they report
+	checkLock        sync.Mutex   // serializes the node health checks
+	nodes            []*beaconNode
+	activeNode       *beaconNode
+	cancelHeadEvents context.CancelFunc
 }
 
 func NewBeaconService() *BeaconService {
 	return &BeaconService{
-		client: &http.Client{Timeout: 192 * time.Second},
+		client: &http.Client{
+			Timeout:   192 * time.Second,
+			Transport: metrics.NewTransport(metrics.DependencyBeaconNode),
+		},
 	}
 }
 
-func (b *BeaconService) Configure(cfg config.BeaconConfig) error {
+func (b *BeaconService) Configure(cfg config.BeaconConfig, logger *logrus.Entry) error {
 	b.cfg = cfg
+	b.log = logger
 
-	err := b.connect(cfg.BeaconNodeUrl)
+	err := b.connect(append([]*url.URL{cfg.BeaconNodeUrl}, cfg.FallbackBeaconNodeUrls...))
 	if err != nil {
 		return fmt.Errorf("failed to connect to beacon node: %w", err)
 	}
@@ -37,5 +54,21 @@ func (b *BeaconService) Configure(cfg config.BeaconConfig) error {
 }
 
 func (b *BeaconService) ConnectedChainId() *big.Int {
+	b.nodeLock.RLock()
+	defer b.nodeLock.RUnlock()
 	return b.cfg.ChainID
 }
+
+// GenesisForkVersion returns the genesis fork version of the chain the beacon node is connected to
+func (b *BeaconService) GenesisForkVersion() phase0.Version {
+	b.nodeLock.RLock()
+	defer b.nodeLock.RUnlock()
+	return b.cfg.GenesisForkVersion
+}
+
+// CurrentSlot returns the most recent head slot seen through the head events subscription, zero if none seen yet
+func (b *BeaconService) CurrentSlot() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentSlot
+}
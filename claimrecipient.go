@@ -0,0 +1,124 @@
+package k2
+
+import (
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+)
+
+// getClaimRecipients returns the recipient each representative delegated its KETH claims to, the zero address if none
+func (k2 *K2Service) getClaimRecipients(representatives []ethcommon.Address) ([]k2common.ClaimRecipient, error) {
+
+	if !k2.k2Enabled() {
+		// module not configured to run
+		return nil, fmt.Errorf("module not configured to run K2 contract operations")
+	}
+
+	claimRecipients, err := k2.eth1.K2DelegatedClaimRecipients(representatives)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the delegated claim recipients: %w", err)
+	}
+
+	result := make([]k2common.ClaimRecipient, 0, len(representatives))
+	for _, representative := range representatives {
+		result = append(result, k2common.ClaimRecipient{
+			RepresentativeAddress: representative,
+			ClaimRecipient:        claimRecipients[representative],
+		})
+	}
+
+	return result, nil
+}
+
+// changeClaimRecipient sets the recipient the KETH claims of a configured representative are sent to,
+// or clears it with the zero address so claims are paid to the representative again
+func (k2 *K2Service) changeClaimRecipient(representative ethcommon.Address, claimRecipient ethcommon.Address) (k2common.ChangedClaimRecipient, error) {
+
+	defer k2.trackPendingJobs(jobPayoutUpdates, 1)()
+
+	k2.lock.Lock()
+	defer k2.lock.Unlock()
+
+	if !k2.k2Enabled() {
+		// module not configured to run
+		return k2common.ChangedClaimRecipient{}, fmt.Errorf("module not configured to run K2 contract operations")
+	}
+
+	// check if the representative is a configured wallet
+	var walletFound bool
+	for _, wallet := range k2.validatorWallets() {
+		if wallet.Address == representative {
+			walletFound = true
+			break
+		}
+	}
+	if !walletFound {
+		k2.log.WithField("representative", representative.String()).Error("representative is not a configured wallet")
+		return k2common.ChangedClaimRecipient{}, fmt.Errorf("representative [%v] is not a configured wallet; cannot change the claim recipient on behalf", representative.String())
+	}
+
+	claimRecipients, err := k2.eth1.K2DelegatedClaimRecipients([]ethcommon.Address{representative})
+	if err != nil {
+		k2.log.WithError(err).Error("failed to get the delegated claim recipient")
+		return k2common.ChangedClaimRecipient{}, fmt.Errorf("failed to get the delegated claim recipient: %w", err)
+	}
+
+	previousClaimRecipient := claimRecipients[representative]
+
+	if previousClaimRecipient == claimRecipient {
+		k2.log.WithFields(logrus.Fields{
+			"representative": representative.String(),
+			"claimRecipient": claimRecipient.String(),
+		}).Info("Delegated claim recipient is already set to the new claim recipient")
+		// the intended outcome is already met, return a success
+		return k2common.ChangedClaimRecipient{
+			RepresentativeAddress:  representative,
+			PreviousClaimRecipient: previousClaimRecipient,
+			NewClaimRecipient:      claimRecipient,
+			Success:                true,
+		}, nil
+	}
+
+	k2.log.WithFields(logrus.Fields{
+		"representative":         representative.String(),
+		"previousClaimRecipient": previousClaimRecipient.String(),
+		"newClaimRecipient":      claimRecipient.String(),
+	}).Info("Changing delegated claim recipient")
+
+	tx, err := k2.eth1.K2SetDelegatedClaimRecipient(representative, claimRecipient)
+	if err != nil {
+		k2.log.WithError(err).Error("failed to change the delegated claim recipient")
+		k2.auditTransaction(metrics.OperationClaimRecipientUpdate, representative, nil, nil, err)
+		k2.notify(notifier.EventTransactionFailed, []ethcommon.Address{representative}, map[string]any{
+			"operation":      metrics.OperationClaimRecipientUpdate,
+			"claimRecipient": claimRecipient.String(),
+			"error":          err.Error(),
+		})
+		return k2common.ChangedClaimRecipient{}, fmt.Errorf("failed to change the delegated claim recipient: %w", err)
+	}
+	k2.log.WithFields(logrus.Fields{
+		"representative":    representative.String(),
+		"newClaimRecipient": claimRecipient.String(),
+		"txHash":            tx.Hash().String(),
+	}).Info("Delegated claim recipient change transaction completed")
+	k2.auditTransaction(metrics.OperationClaimRecipientUpdate, representative, nil, tx, nil)
+	metrics.BatchesSent.WithLabelValues(representative.String(), metrics.OperationClaimRecipientUpdate).Inc()
+	k2.notify(notifier.EventClaimRecipientChange, []ethcommon.Address{representative}, map[string]any{
+		"previousClaimRecipient": previousClaimRecipient.String(),
+		"newClaimRecipient":      claimRecipient.String(),
+		"txHash":                 tx.Hash().String(),
+	})
+
+	return k2common.ChangedClaimRecipient{
+		RepresentativeAddress:  representative,
+		PreviousClaimRecipient: previousClaimRecipient,
+		NewClaimRecipient:      claimRecipient,
+		TxHash:                 tx.Hash(),
+		Success:                true,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package k2
+
+import (
+	"path/filepath"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	auditconfig "github.com/restaking-cloud/native-delegation-for-plus/audit/config"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+func TestClaimDelegated_Failures(t *testing.T) {
+
+	k2 := NewK2Service()
+	if err := k2.auditLog.Configure(auditconfig.AuditConfig{File: filepath.Join(t.TempDir(), "audit.jsonl")}, logrus.NewEntry(logrus.New())); err != nil {
+		t.Fatal(err)
+	}
+	defer k2.auditLog.Close()
+
+	otherRepresentative := ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+	claims := []k2common.K2Claim{
+		{RepresentativeAddress: testRepresentative, ClaimAmount: 1},
+		{RepresentativeAddress: otherRepresentative, ClaimAmount: 2, ClaimRecipient: &ethcommon.Address{}},
+	}
+
+	claimed, failed := k2.claimDelegated(claims)
+
+	if len(claimed) != 0 {
+		t.Errorf("claimDelegated() claimed %d claims, want none", len(claimed))
+	}
+	// every claim is tried and returned with the reason it failed
+	if len(failed) != len(claims) {
+		t.Fatalf("claimDelegated() returned %d failed claims, want %d", len(failed), len(claims))
+	}
+	for i, claim := range failed {
+		if claim.RepresentativeAddress != claims[i].RepresentativeAddress {
+			t.Errorf("failed claim %d representative = %s, want %s", i, claim.RepresentativeAddress, claims[i].RepresentativeAddress)
+		}
+		if claim.Error == "" {
+			t.Errorf("failed claim %d has no error", i)
+		}
+	}
+
+	entries, err := k2.auditLog.Query(audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(claims) {
+		t.Fatalf("audited %d entries, want %d", len(entries), len(claims))
+	}
+	for _, entry := range entries {
+		if entry.Outcome != audit.OutcomeFailed {
+			t.Errorf("audited outcome = %s, want %s", entry.Outcome, audit.OutcomeFailed)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/config"
+)
+
+func main() {
+	app := &cli.App{
+		Name:  "k2-lists",
+		Usage: "Manage the list files of the K2 native delegation module",
+		Commands: []*cli.Command{
+			config.NewConvertListCommand(),
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"fmt"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+var (
+	// DomainTypeAppBuilder is the domain type builder API messages such as validator registrations are signed with
+	DomainTypeAppBuilder = phase0.DomainType{0x00, 0x00, 0x00, 0x01}
+
+	blsSignatureDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+	_, _, g1One, _  = bls12381.Generators()
+)
+
+// ComputeBuilderDomain returns the application builder domain for the chain with the genesis fork version,
+// builder messages are signed with a zero genesis validators root so that they are valid across forks
+func ComputeBuilderDomain(genesisForkVersion phase0.Version) (phase0.Domain, error) {
+
+	forkData := phase0.ForkData{
+		CurrentVersion:        genesisForkVersion,
+		GenesisValidatorsRoot: phase0.Root{},
+	}
+	forkDataRoot, err := forkData.HashTreeRoot()
+	if err != nil {
+		return phase0.Domain{}, err
+	}
+
+	var domain phase0.Domain
+	copy(domain[:], DomainTypeAppBuilder[:])
+	copy(domain[len(DomainTypeAppBuilder):], forkDataRoot[:])
+
+	return domain, nil
+}
+
+// VerifyRegistrationSignature checks the BLS signature of the registration was made by the validator over the
+// registration message in the domain
+func VerifyRegistrationSignature(registration apiv1.SignedValidatorRegistration, domain phase0.Domain) (bool, error) {
+
+	if registration.Message == nil {
+		return false, fmt.Errorf("registration has no message")
+	}
+
+	messageRoot, err := registration.Message.HashTreeRoot()
+	if err != nil {
+		return false, err
+	}
+	signingData := phase0.SigningData{
+		ObjectRoot: messageRoot,
+		Domain:     domain,
+	}
+	signingRoot, err := signingData.HashTreeRoot()
+	if err != nil {
+		return false, err
+	}
+
+	return VerifyBLSSignature(signingRoot[:], registration.Signature, registration.Message.Pubkey)
+}
+
+// VerifyBLSSignature checks the BLS signature of the message against the public key
+func VerifyBLSSignature(message []byte, signature phase0.BLSSignature, pubkey phase0.BLSPubKey) (bool, error) {
+
+	// decoding checks the points are on the curve and in the correct subgroup
+	var pk bls12381.G1Affine
+	if _, err := pk.SetBytes(pubkey[:]); err != nil {
+		return false, fmt.Errorf("invalid public key: %w", err)
+	}
+	if pk.IsInfinity() {
+		return false, fmt.Errorf("invalid public key: point at infinity")
+	}
+
+	var sig bls12381.G2Affine
+	if _, err := sig.SetBytes(signature[:]); err != nil {
+		return false, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	hashedMessage, err := bls12381.HashToG2(message, blsSignatureDST)
+	if err != nil {
+		return false, err
+	}
+
+	var negG1 bls12381.G1Affine
+	negG1.Neg(&g1One)
+
+	return bls12381.PairingCheck(
+		[]bls12381.G1Affine{pk, negG1},
+		[]bls12381.G2Affine{hashedMessage, sig},
+	)
+}
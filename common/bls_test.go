@@ -0,0 +1,305 @@
+package common_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/common"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+var testBLSDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+// testBLSKey returns the public key of the secret key and a function signing messages with it
+func testBLSKey(t *testing.T, secret int64) (phase0.BLSPubKey, func([]byte) phase0.BLSSignature) {
+	t.Helper()
+
+	sk := big.NewInt(secret)
+	_, _, g1, _ := bls12381.Generators()
+
+	var pk bls12381.G1Affine
+	pk.ScalarMultiplication(&g1, sk)
+	var pubkey phase0.BLSPubKey
+	pkBytes := pk.Bytes()
+	copy(pubkey[:], pkBytes[:])
+
+	return pubkey, func(message []byte) phase0.BLSSignature {
+		hashedMessage, err := bls12381.HashToG2(message, testBLSDST)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sig bls12381.G2Affine
+		sig.ScalarMultiplication(&hashedMessage, sk)
+		var signature phase0.BLSSignature
+		sigBytes := sig.Bytes()
+		copy(signature[:], sigBytes[:])
+		return signature
+	}
+}
+
+func TestComputeBuilderDomain(t *testing.T) {
+
+	tests := []struct {
+		name               string
+		genesisForkVersion phase0.Version
+		want               string
+	}{
+		{
+			name:               "mainnet",
+			genesisForkVersion: phase0.Version{0x00, 0x00, 0x00, 0x00},
+			want:               "0x00000001f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9",
+		},
+		{
+			name:               "goerli",
+			genesisForkVersion: phase0.Version{0x00, 0x00, 0x10, 0x20},
+			want:               "0x00000001e4be9393b074ca1f3e4aabd585ca4bea101170ccfaf71b89ce5c5c38",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain, err := k2common.ComputeBuilderDomain(tt.genesisForkVersion)
+			if err != nil {
+				t.Fatalf("ComputeBuilderDomain() error = %v", err)
+			}
+			if got := common.Bytes2Hex(domain[:]); "0x"+got != tt.want {
+				t.Errorf("ComputeBuilderDomain() = 0x%s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistrationSignature(t *testing.T) {
+
+	mainnetDomain, err := k2common.ComputeBuilderDomain(phase0.Version{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	goerliDomain, err := k2common.ComputeBuilderDomain(phase0.Version{0x00, 0x00, 0x10, 0x20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubkey, sign := testBLSKey(t, 12345)
+	otherPubkey, _ := testBLSKey(t, 54321)
+
+	newRegistration := func(pubkey phase0.BLSPubKey) *apiv1.ValidatorRegistration {
+		return &apiv1.ValidatorRegistration{
+			FeeRecipient: bellatrix.ExecutionAddress(common.HexToAddress("0x1111111111111111111111111111111111111111")),
+			GasLimit:     30000000,
+			Timestamp:    time.Unix(1700000000, 0),
+			Pubkey:       pubkey,
+		}
+	}
+	signRegistration := func(message *apiv1.ValidatorRegistration, domain phase0.Domain) phase0.BLSSignature {
+		messageRoot, err := message.HashTreeRoot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signingRoot, err := (&phase0.SigningData{ObjectRoot: messageRoot, Domain: domain}).HashTreeRoot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sign(signingRoot[:])
+	}
+
+	tests := []struct {
+		name         string
+		registration func() apiv1.SignedValidatorRegistration
+		want         bool
+		wantErr      bool
+	}{
+		{
+			name: "valid signature",
+			registration: func() apiv1.SignedValidatorRegistration {
+				message := newRegistration(pubkey)
+				return apiv1.SignedValidatorRegistration{Message: message, Signature: signRegistration(message, mainnetDomain)}
+			},
+			want: true,
+		},
+		{
+			name: "signed for another chain",
+			registration: func() apiv1.SignedValidatorRegistration {
+				message := newRegistration(pubkey)
+				return apiv1.SignedValidatorRegistration{Message: message, Signature: signRegistration(message, goerliDomain)}
+			},
+			want: false,
+		},
+		{
+			name: "message changed after signing",
+			registration: func() apiv1.SignedValidatorRegistration {
+				message := newRegistration(pubkey)
+				signature := signRegistration(message, mainnetDomain)
+				message.GasLimit++
+				return apiv1.SignedValidatorRegistration{Message: message, Signature: signature}
+			},
+			want: false,
+		},
+		{
+			name: "signed by another validator",
+			registration: func() apiv1.SignedValidatorRegistration {
+				message := newRegistration(otherPubkey)
+				return apiv1.SignedValidatorRegistration{Message: message, Signature: signRegistration(newRegistration(pubkey), mainnetDomain)}
+			},
+			want: false,
+		},
+		{
+			name: "no message",
+			registration: func() apiv1.SignedValidatorRegistration {
+				return apiv1.SignedValidatorRegistration{}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid public key",
+			registration: func() apiv1.SignedValidatorRegistration {
+				message := newRegistration(pubkey)
+				signature := signRegistration(message, mainnetDomain)
+				message.Pubkey = phase0.BLSPubKey{0x01}
+				return apiv1.SignedValidatorRegistration{Message: message, Signature: signature}
+			},
+			wantErr: true,
+		},
+		{
+			name: "public key at infinity",
+			registration: func() apiv1.SignedValidatorRegistration {
+				message := newRegistration(phase0.BLSPubKey{0xc0})
+				return apiv1.SignedValidatorRegistration{Message: message, Signature: signRegistration(message, mainnetDomain)}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid signature",
+			registration: func() apiv1.SignedValidatorRegistration {
+				return apiv1.SignedValidatorRegistration{Message: newRegistration(pubkey), Signature: phase0.BLSSignature{0x01}}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := k2common.VerifyRegistrationSignature(tt.registration(), mainnetDomain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyRegistrationSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyRegistrationSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyBLSSignatureKnownAnswers(t *testing.T) {
+
+	// sign vectors from the consensus spec BLS tests, signed by the test secret key
+	// 0x263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3
+	var pubkey phase0.BLSPubKey
+	copy(pubkey[:], common.FromHex("0xa491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"))
+
+	tests := []struct {
+		name      string
+		message   string
+		signature string
+		want      bool
+	}{
+		{
+			name:      "zero message",
+			message:   "0x0000000000000000000000000000000000000000000000000000000000000000",
+			signature: "0xb6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55",
+			want:      true,
+		},
+		{
+			name:      "0x56 message",
+			message:   "0x5656565656565656565656565656565656565656565656565656565656565656",
+			signature: "0x882730e5d03f6b42c3abc26d3372625034e1d871b65a8a6b900a56dae22da98abbe1b68f85e49fe7652a55ec3d0591c20767677e33e5cbb1207315c41a9ac03be39c2e7668edc043d6cb1d9fd93033caa8a1c5b0e84bedaeb6c64972503a43eb",
+			want:      true,
+		},
+		{
+			name:      "0xab message",
+			message:   "0xabababababababababababababababababababababababababababababababab",
+			signature: "0x91347bccf740d859038fcdcaf233eeceb2a436bcaaee9b2aa3bfb70efe29dfb2677562ccbea1c8e061fb9971b0753c240622fab78489ce96768259fc01360346da5b9f579e5da0d941e4c6ba18a0e64906082375394f337fa1af2b7127b0d121",
+			want:      true,
+		},
+		{
+			name:      "signature of another message",
+			message:   "0x5656565656565656565656565656565656565656565656565656565656565656",
+			signature: "0x91347bccf740d859038fcdcaf233eeceb2a436bcaaee9b2aa3bfb70efe29dfb2677562ccbea1c8e061fb9971b0753c240622fab78489ce96768259fc01360346da5b9f579e5da0d941e4c6ba18a0e64906082375394f337fa1af2b7127b0d121",
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signature phase0.BLSSignature
+			copy(signature[:], common.FromHex(tt.signature))
+			got, err := k2common.VerifyBLSSignature(common.FromHex(tt.message), signature, pubkey)
+			if err != nil {
+				t.Fatalf("VerifyBLSSignature() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyBLSSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistrationSignatureKnownAnswers(t *testing.T) {
+
+	// registrations as sent by a validator client, signed by the consensus spec BLS test key for each chain
+	message := `{"fee_recipient":"0x1111111111111111111111111111111111111111","gas_limit":"30000000","timestamp":"1700000000","pubkey":"0xa491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"}`
+	mainnetSignature := "0x902307fe4319b4555417a6bd1d5eed2343eb0f46c2e1d980bd8372564c2266283eeab654bf487c78bf65852be50f18cb0396662c70cd2129cffb48d9525abbc491c6a87458e44d1e7cc9ec943269d121c4581d892afa8807ab109decc73849ca"
+	goerliSignature := "0xa4bbadce2f4263afcfb6f0843cc9d3eecec7bd71ef3e1f3ec3092b70152ccf01125bf82873de9647c86bf55b26ff31bd08247860946831f8ecb013de21aad6d6272c076f3585c31496623ce09bf6b748fae81383a74e5a4fe006646bbb4aefda"
+
+	tests := []struct {
+		name               string
+		genesisForkVersion phase0.Version
+		signature          string
+		want               bool
+	}{
+		{
+			name:               "mainnet",
+			genesisForkVersion: phase0.Version{0x00, 0x00, 0x00, 0x00},
+			signature:          mainnetSignature,
+			want:               true,
+		},
+		{
+			name:               "goerli",
+			genesisForkVersion: phase0.Version{0x00, 0x00, 0x10, 0x20},
+			signature:          goerliSignature,
+			want:               true,
+		},
+		{
+			name:               "goerli registration on mainnet",
+			genesisForkVersion: phase0.Version{0x00, 0x00, 0x00, 0x00},
+			signature:          goerliSignature,
+			want:               false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var registration apiv1.SignedValidatorRegistration
+			if err := json.Unmarshal([]byte(`{"message":`+message+`,"signature":"`+tt.signature+`"}`), &registration); err != nil {
+				t.Fatal(err)
+			}
+			domain, err := k2common.ComputeBuilderDomain(tt.genesisForkVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := k2common.VerifyRegistrationSignature(registration, domain)
+			if err != nil {
+				t.Fatalf("VerifyRegistrationSignature() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyRegistrationSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EndpointFailureThreshold is the number of consecutive failures after which an endpoint's circuit is opened
+	EndpointFailureThreshold = 3
+	// EndpointCircuitCooldown is how long an endpoint is skipped for once its circuit is opened
+	EndpointCircuitCooldown = 30 * time.Second
+
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// ErrNoEndpointAvailable is returned when every endpoint of a pool has its circuit open
+var ErrNoEndpointAvailable = errors.New("no endpoint available, all circuits are open")
+
+// StatusError is returned when an endpoint responds with a status code other than the one expected
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("invalid response (%d): %s", e.StatusCode, e.Body)
+}
+
+// IsEndpointFailure reports whether the error is a failure of the endpoint itself, a transport error, a timeout or a
+// server error response, rather than the endpoint answering the request with an error
+func IsEndpointFailure(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+type poolEndpoint struct {
+	url                 *url.URL
+	verified            bool
+	consecutiveFailures int
+	openUntil           time.Time
+	lastError           string
+}
+
+// EndpointPool calls an ordered list of endpoints of the same service, failing over to the next endpoint on error.
+// An endpoint that fails EndpointFailureThreshold times in a row has its circuit opened and is skipped until the
+// cooldown passes, after which calls are let through to test it again (half open) and a single failure opens it again.
+// Endpoints that were not verified when the pool was created are verified before their first use
+type EndpointPool struct {
+	mu        sync.Mutex
+	endpoints []*poolEndpoint
+	verify    func(*url.URL) error
+}
+
+// NewEndpointPool creates a pool of the endpoints in order of preference. Endpoints in verified have already been
+// checked to serve the expected chain, the others are checked with verify before they are first used
+func NewEndpointPool(urls []*url.URL, verified map[*url.URL]bool, verify func(*url.URL) error) *EndpointPool {
+	pool := &EndpointPool{
+		verify: verify,
+	}
+	for _, u := range urls {
+		pool.endpoints = append(pool.endpoints, &poolEndpoint{
+			url:      u,
+			verified: verified[u],
+		})
+	}
+	return pool
+}
+
+// Do calls fn with each available endpoint in order until one succeeds, returning the errors of every endpoint tried
+// if none succeed. Only endpoint failures (see IsEndpointFailure) fail over and count towards the circuit, any other
+// error is the endpoint's answer to the request and is returned as is
+func (p *EndpointPool) Do(fn func(*url.URL) error) error {
+
+	if p == nil || len(p.endpoints) == 0 {
+		return fmt.Errorf("no endpoints configured")
+	}
+
+	var failures []string
+	for _, endpoint := range p.endpoints {
+
+		p.mu.Lock()
+		available := endpoint.openUntil.IsZero() || !time.Now().Before(endpoint.openUntil)
+		verified := endpoint.verified
+		p.mu.Unlock()
+		if !available {
+			continue
+		}
+
+		if !verified && p.verify != nil {
+			if err := p.verify(endpoint.url); err != nil {
+				p.recordFailure(endpoint, fmt.Errorf("failed verification: %w", err))
+				failures = append(failures, fmt.Sprintf("%s: failed verification: %v", endpoint.url.Redacted(), err))
+				continue
+			}
+			p.mu.Lock()
+			endpoint.verified = true
+			p.mu.Unlock()
+		}
+
+		err := fn(endpoint.url)
+		if err == nil || !IsEndpointFailure(err) {
+			p.recordSuccess(endpoint)
+			return err
+		}
+		p.recordFailure(endpoint, err)
+		failures = append(failures, fmt.Sprintf("%s: %v", endpoint.url.Redacted(), err))
+	}
+
+	if len(failures) == 0 {
+		return ErrNoEndpointAvailable
+	}
+
+	return fmt.Errorf("all endpoints failed: %s", strings.Join(failures, "; "))
+}
+
+func (p *EndpointPool) recordSuccess(endpoint *poolEndpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	endpoint.consecutiveFailures = 0
+	endpoint.openUntil = time.Time{}
+	endpoint.lastError = ""
+}
+
+func (p *EndpointPool) recordFailure(endpoint *poolEndpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	endpoint.consecutiveFailures++
+	endpoint.lastError = err.Error()
+	if endpoint.consecutiveFailures >= EndpointFailureThreshold {
+		// opening again from half open restarts the cooldown
+		endpoint.openUntil = time.Now().Add(EndpointCircuitCooldown)
+	}
+}
+
+// Urls returns the endpoints of the pool in order of preference
+func (p *EndpointPool) Urls() []*url.URL {
+	if p == nil {
+		return nil
+	}
+	urls := make([]*url.URL, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		urls = append(urls, endpoint.url)
+	}
+	return urls
+}
+
+// Health returns the circuit state of each endpoint of the pool in order of preference
+func (p *EndpointPool) Health() []EndpointHealth {
+
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	health := make([]EndpointHealth, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		h := EndpointHealth{
+			Url:                 endpoint.url.Redacted(),
+			Verified:            endpoint.verified,
+			Circuit:             CircuitClosed,
+			ConsecutiveFailures: endpoint.consecutiveFailures,
+			LastError:           endpoint.lastError,
+		}
+		if !endpoint.openUntil.IsZero() {
+			if now.Before(endpoint.openUntil) {
+				openUntil := endpoint.openUntil
+				h.Circuit = CircuitOpen
+				h.CircuitOpenUntil = &openUntil
+			} else {
+				h.Circuit = CircuitHalfOpen
+			}
+		}
+		health = append(health, h)
+	}
+
+	return health
+}
@@ -0,0 +1,298 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func testEndpointUrls(t *testing.T, rawUrls ...string) []*url.URL {
+	t.Helper()
+	urls := make([]*url.URL, 0, len(rawUrls))
+	for _, rawUrl := range rawUrls {
+		u, err := url.Parse(rawUrl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+func TestEndpointPool_Circuit(t *testing.T) {
+
+	errDown := &StatusError{StatusCode: 503, Body: "endpoint down"}
+
+	tests := []struct {
+		name         string
+		failures     int  // consecutive calls failing on the primary endpoint before the checked call
+		cooledDown   bool // whether the cooldown of an opened circuit has passed before the checked call
+		primaryUp    bool // whether the primary endpoint succeeds on the checked call
+		wantCircuit  string
+		wantFailures int
+		wantCalled   string // endpoint that serves the checked call
+	}{
+		{
+			name:         "closed below the failure threshold",
+			failures:     EndpointFailureThreshold - 2,
+			primaryUp:    false,
+			wantCircuit:  CircuitClosed,
+			wantFailures: EndpointFailureThreshold - 1,
+			wantCalled:   "https://fallback.example.com",
+		},
+		{
+			name:         "opens after the failure threshold",
+			failures:     EndpointFailureThreshold,
+			primaryUp:    true,
+			wantCircuit:  CircuitOpen,
+			wantFailures: EndpointFailureThreshold,
+			wantCalled:   "https://fallback.example.com",
+		},
+		{
+			name:         "half open lets a call through and recovers on success",
+			failures:     EndpointFailureThreshold,
+			cooledDown:   true,
+			primaryUp:    true,
+			wantCircuit:  CircuitClosed,
+			wantFailures: 0,
+			wantCalled:   "https://primary.example.com",
+		},
+		{
+			name:         "half open opens again on a single failure",
+			failures:     EndpointFailureThreshold,
+			cooledDown:   true,
+			primaryUp:    false,
+			wantCircuit:  CircuitOpen,
+			wantFailures: EndpointFailureThreshold + 1,
+			wantCalled:   "https://fallback.example.com",
+		},
+		{
+			name:         "success resets the failures",
+			failures:     EndpointFailureThreshold - 1,
+			primaryUp:    true,
+			wantCircuit:  CircuitClosed,
+			wantFailures: 0,
+			wantCalled:   "https://primary.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := testEndpointUrls(t, "https://primary.example.com", "https://fallback.example.com")
+			pool := NewEndpointPool(urls, map[*url.URL]bool{urls[0]: true, urls[1]: true}, nil)
+
+			for i := 0; i < tt.failures; i++ {
+				_ = pool.Do(func(u *url.URL) error {
+					if u == urls[0] {
+						return errDown
+					}
+					return nil
+				})
+			}
+
+			if tt.cooledDown {
+				pool.endpoints[0].openUntil = time.Now().Add(-time.Second)
+				if circuit := pool.Health()[0].Circuit; circuit != CircuitHalfOpen {
+					t.Fatalf("circuit after the cooldown = %s, want %s", circuit, CircuitHalfOpen)
+				}
+			}
+
+			var called string
+			err := pool.Do(func(u *url.URL) error {
+				if u == urls[0] && !tt.primaryUp {
+					return errDown
+				}
+				called = u.String()
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("call served by %s, want %s", called, tt.wantCalled)
+			}
+
+			health := pool.Health()[0]
+			if health.Circuit != tt.wantCircuit {
+				t.Errorf("circuit = %s, want %s", health.Circuit, tt.wantCircuit)
+			}
+			if health.ConsecutiveFailures != tt.wantFailures {
+				t.Errorf("consecutive failures = %d, want %d", health.ConsecutiveFailures, tt.wantFailures)
+			}
+			if (health.CircuitOpenUntil != nil) != (tt.wantCircuit == CircuitOpen) {
+				t.Errorf("circuit open until = %v, want set only while open", health.CircuitOpenUntil)
+			}
+		})
+	}
+}
+
+func TestEndpointPool_AllOpen(t *testing.T) {
+
+	urls := testEndpointUrls(t, "https://primary.example.com")
+	pool := NewEndpointPool(urls, map[*url.URL]bool{urls[0]: true}, nil)
+
+	for i := 0; i < EndpointFailureThreshold; i++ {
+		if err := pool.Do(func(*url.URL) error {
+			return &url.Error{Op: "Get", URL: "https://primary.example.com", Err: errors.New("connection refused")}
+		}); err == nil {
+			t.Fatal("Do() with a failing endpoint returned no error")
+		}
+	}
+
+	called := false
+	err := pool.Do(func(*url.URL) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrNoEndpointAvailable) {
+		t.Errorf("Do() error = %v, want %v", err, ErrNoEndpointAvailable)
+	}
+	if called {
+		t.Error("Do() called an endpoint with its circuit open")
+	}
+}
+
+func TestEndpointPool_ApplicationErrors(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		err          error
+		wantFailover bool
+	}{
+		{
+			name:         "transport error",
+			err:          &url.Error{Op: "Post", URL: "https://primary.example.com", Err: errors.New("connection refused")},
+			wantFailover: true,
+		},
+		{
+			name:         "timeout",
+			err:          &url.Error{Op: "Post", URL: "https://primary.example.com", Err: context.DeadlineExceeded},
+			wantFailover: true,
+		},
+		{
+			name:         "server error",
+			err:          &StatusError{StatusCode: 502, Body: "bad gateway"},
+			wantFailover: true,
+		},
+		{
+			name:         "truncated response",
+			err:          fmt.Errorf("decoding response: %w", io.ErrUnexpectedEOF),
+			wantFailover: true,
+		},
+		{
+			name:         "client error",
+			err:          &StatusError{StatusCode: 400, Body: "invalid registration"},
+			wantFailover: false,
+		},
+		{
+			name:         "invalid response",
+			err:          errors.New("invalid response: original data differs from the registration sent"),
+			wantFailover: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := testEndpointUrls(t, "https://primary.example.com", "https://fallback.example.com")
+			pool := NewEndpointPool(urls, map[*url.URL]bool{urls[0]: true, urls[1]: true}, nil)
+
+			var called []string
+			err := pool.Do(func(u *url.URL) error {
+				called = append(called, u.String())
+				if u == urls[0] {
+					return tt.err
+				}
+				return nil
+			})
+
+			if tt.wantFailover {
+				if err != nil {
+					t.Fatalf("Do() error = %v, want the fallback to serve the call", err)
+				}
+				if len(called) != 2 {
+					t.Errorf("endpoints called = %v, want both", called)
+				}
+				if failures := pool.Health()[0].ConsecutiveFailures; failures != 1 {
+					t.Errorf("consecutive failures = %d, want 1", failures)
+				}
+				return
+			}
+
+			if err != tt.err {
+				t.Fatalf("Do() error = %v, want %v", err, tt.err)
+			}
+			if len(called) != 1 {
+				t.Errorf("endpoints called = %v, want only the primary", called)
+			}
+			if failures := pool.Health()[0].ConsecutiveFailures; failures != 0 {
+				t.Errorf("consecutive failures = %d, want 0", failures)
+			}
+		})
+	}
+}
+
+func TestEndpointPool_Verification(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		verifyErr    error
+		wantVerified bool
+		wantCalled   string
+	}{
+		{
+			name:         "verified before first use",
+			wantVerified: true,
+			wantCalled:   "https://unverified.example.com",
+		},
+		{
+			name:         "failed verification fails over",
+			verifyErr:    errors.New("wrong chain"),
+			wantVerified: false,
+			wantCalled:   "https://fallback.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := testEndpointUrls(t, "https://unverified.example.com", "https://fallback.example.com")
+
+			verifications := 0
+			pool := NewEndpointPool(urls, map[*url.URL]bool{urls[1]: true}, func(u *url.URL) error {
+				if u != urls[0] {
+					t.Errorf("verified %s, which was already verified", u)
+				}
+				verifications++
+				return tt.verifyErr
+			})
+
+			for i := 0; i < 2; i++ {
+				var called string
+				if err := pool.Do(func(u *url.URL) error {
+					called = u.String()
+					return nil
+				}); err != nil {
+					t.Fatalf("Do() error = %v", err)
+				}
+				if called != tt.wantCalled {
+					t.Errorf("call served by %s, want %s", called, tt.wantCalled)
+				}
+			}
+
+			if health := pool.Health()[0]; health.Verified != tt.wantVerified {
+				t.Errorf("verified = %v, want %v", health.Verified, tt.wantVerified)
+			}
+			wantVerifications := 1
+			if tt.verifyErr != nil {
+				// verified again on each call until it passes
+				wantVerifications = 2
+			}
+			if verifications != wantVerifications {
+				t.Errorf("verifications = %d, want %d", verifications, wantVerifications)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultDerivationPath is the BIP-44 path of Ethereum accounts, the wallet index is appended to it
+const DefaultDerivationPath = "m/44'/60'/0'/0"
+
+// HDWallet derives representative wallets from a BIP-39 mnemonic, following BIP-32 along a BIP-44 base path
+type HDWallet struct {
+	key       []byte // private key at the base path
+	chainCode []byte // chain code at the base path
+}
+
+func NewHDWallet(mnemonic string, basePath string) (*HDWallet, error) {
+
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+
+	path, err := accounts.ParseDerivationPath(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %q: %w", basePath, err)
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	wallet := &HDWallet{
+		key:       sum[:32],
+		chainCode: sum[32:],
+	}
+	for _, index := range path {
+		wallet.key, wallet.chainCode, err = deriveChildKey(wallet.key, wallet.chainCode, index)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q: %w", basePath, err)
+		}
+	}
+
+	return wallet, nil
+}
+
+// Derive returns the wallet at the index below the base path
+func (w *HDWallet) Derive(index uint32) (ValidatorWallet, error) {
+
+	if index >= 0x80000000 {
+		return ValidatorWallet{}, fmt.Errorf("derivation index %d out of range", index)
+	}
+
+	key, _, err := deriveChildKey(w.key, w.chainCode, index)
+	if err != nil {
+		return ValidatorWallet{}, fmt.Errorf("failed to derive wallet at index %d: %w", index, err)
+	}
+
+	privateKey, err := crypto.ToECDSA(key)
+	if err != nil {
+		return ValidatorWallet{}, fmt.Errorf("failed to derive wallet at index %d: %w", index, err)
+	}
+
+	return ValidatorWallet{
+		PrivateKey: privateKey,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}, nil
+}
+
+// deriveChildKey derives the BIP-32 child private key and chain code, hardened for indices from 2^31
+func deriveChildKey(key []byte, chainCode []byte, index uint32) ([]byte, []byte, error) {
+
+	var data []byte
+	if index >= 0x80000000 {
+		data = append([]byte{0}, key...)
+	} else {
+		privateKey, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&privateKey.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return nil, nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+	childKey := tweak.Add(tweak, new(big.Int).SetBytes(key))
+	childKey.Mod(childKey, n)
+	if childKey.Sign() == 0 {
+		return nil, nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+
+	return math.PaddedBigBytes(childKey, 32), sum[32:], nil
+}
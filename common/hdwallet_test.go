@@ -0,0 +1,102 @@
+package common_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+// the well known development mnemonic, whose accounts are funded by default in local test networks
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestHDWallet_Derive(t *testing.T) {
+
+	wallet, err := k2common.NewHDWallet(testMnemonic, k2common.DefaultDerivationPath)
+	if err != nil {
+		t.Fatalf("NewHDWallet() error = %v", err)
+	}
+
+	tests := []struct {
+		index          uint32
+		wantAddress    common.Address
+		wantPrivateKey string // checked if set
+	}{
+		{
+			index:          0,
+			wantAddress:    common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+			wantPrivateKey: "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+		},
+		{
+			index:       1,
+			wantAddress: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		},
+		{
+			index:       2,
+			wantAddress: common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"),
+		},
+	}
+
+	for _, tt := range tests {
+		derived, err := wallet.Derive(tt.index)
+		if err != nil {
+			t.Fatalf("Derive(%d) error = %v", tt.index, err)
+		}
+		if derived.Address != tt.wantAddress {
+			t.Errorf("Derive(%d) address = %s, want %s", tt.index, derived.Address, tt.wantAddress)
+		}
+		if tt.wantPrivateKey != "" && common.Bytes2Hex(crypto.FromECDSA(derived.PrivateKey)) != tt.wantPrivateKey {
+			t.Errorf("Derive(%d) private key does not match", tt.index)
+		}
+	}
+
+	if _, err := wallet.Derive(0x80000000); err == nil {
+		t.Errorf("Derive() accepted a hardened index")
+	}
+}
+
+func TestNewHDWallet(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		mnemonic string
+		basePath string
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			mnemonic: testMnemonic,
+			basePath: k2common.DefaultDerivationPath,
+		},
+		{
+			name:     "other account",
+			mnemonic: testMnemonic,
+			basePath: "m/44'/60'/1'/0",
+		},
+		{
+			name:     "invalid checksum",
+			mnemonic: strings.Replace(testMnemonic, "junk", "test", 1),
+			basePath: k2common.DefaultDerivationPath,
+			wantErr:  "invalid mnemonic",
+		},
+		{
+			name:     "invalid path",
+			mnemonic: testMnemonic,
+			basePath: "m/44'/sixty",
+			wantErr:  "invalid derivation path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := k2common.NewHDWallet(tt.mnemonic, tt.basePath)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("NewHDWallet() error = %v", err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("NewHDWallet() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
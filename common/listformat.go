@@ -0,0 +1,404 @@
+package common
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+const (
+	// Formats accepted for the exclusion list, strict inclusion list and representative mapping files
+	ListFormatJSON = "json"
+	ListFormatYAML = "yaml"
+	ListFormatCSV  = "csv"
+)
+
+var ListFormats = []string{ListFormatJSON, ListFormatYAML, ListFormatCSV}
+
+var zeroHexPattern = regexp.MustCompile(`^0x0+$`)
+
+// DetectListFormat returns the format of a list file from its extension, or from its content
+// if the extension is not one of the list formats
+func DetectListFormat(filePath string, content []byte) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		return ListFormatJSON
+	case ".yaml", ".yml":
+		return ListFormatYAML
+	case ".csv":
+		return ListFormatCSV
+	}
+
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 || trimmed[0] == '[' || bytes.Equal(trimmed, []byte("null")) {
+		return ListFormatJSON
+	}
+	firstLine, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	if trimmed[0] != '-' && trimmed[0] != '#' && bytes.Contains(firstLine, []byte(",")) {
+		return ListFormatCSV
+	}
+	return ListFormatYAML
+}
+
+// DecodeList decodes the entries of a list file in any of the list formats, reporting the line of the offending entry on failure.
+// YAML and CSV entries may only use the JSON field names of the entry type as keys and columns
+func DecodeList[T any](filePath string, content []byte, entries *[]T) error {
+	switch DetectListFormat(filePath, content) {
+	case ListFormatYAML:
+		return decodeYAMLList(content, entries)
+	case ListFormatCSV:
+		return decodeCSVList(content, entries)
+	default:
+		return decodeJSONList(content, entries)
+	}
+}
+
+// EncodeList encodes the entries of a list in the given format, leaving out zero keys and addresses in YAML and CSV
+func EncodeList[T any](entries []T, format string) ([]byte, error) {
+	switch format {
+	case ListFormatJSON:
+		if entries == nil {
+			entries = []T{}
+		}
+		content, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(content, '\n'), nil
+	case ListFormatYAML:
+		return encodeYAMLList(entries)
+	case ListFormatCSV:
+		return encodeCSVList(entries)
+	default:
+		return nil, fmt.Errorf("unknown list format %q, must be one of %s", format, strings.Join(ListFormats, ", "))
+	}
+}
+
+func decodeJSONList[T any](content []byte, entries *[]T) error {
+
+	if len(bytes.TrimSpace(content)) == 0 {
+		*entries = nil
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("line %d: %w", lineAt(content, decoder.InputOffset()), err)
+	}
+	if token == nil {
+		*entries = nil
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("line %d: list must be an array of entries", lineAt(content, decoder.InputOffset()))
+	}
+
+	decoded := []T{}
+	for decoder.More() {
+		// the decoder offset is at the end of the previous token, so skip to the start of the entry
+		offset := decoder.InputOffset()
+		for offset < int64(len(content)) && bytes.ContainsRune([]byte(" \t\r\n,"), rune(content[offset])) {
+			offset++
+		}
+		line := lineAt(content, offset)
+		var entry T
+		if err := decoder.Decode(&entry); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				line = lineAt(content, syntaxErr.Offset)
+			}
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		decoded = append(decoded, entry)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("line %d: %w", lineAt(content, decoder.InputOffset()), err)
+	}
+
+	*entries = decoded
+	return nil
+}
+
+func decodeYAMLList[T any](content []byte, entries *[]T) error {
+
+	columns := listColumns(reflect.TypeOf(*new(T)))
+
+	file, err := parser.ParseBytes(content, 0)
+	if err != nil {
+		return errors.New(yaml.FormatError(err, false, false))
+	}
+
+	decoded := []T{}
+	for _, doc := range file.Docs {
+		if doc.Body == nil {
+			continue
+		}
+		if _, ok := doc.Body.(*ast.NullNode); ok {
+			continue
+		}
+		sequence, ok := doc.Body.(*ast.SequenceNode)
+		if !ok {
+			return fmt.Errorf("line %d: list must be a sequence of entries", doc.Body.GetToken().Position.Line)
+		}
+		for _, value := range sequence.Values {
+			line := value.GetToken().Position.Line
+
+			var fields []*ast.MappingValueNode
+			switch node := value.(type) {
+			case *ast.MappingNode:
+				fields = node.Values
+			case *ast.MappingValueNode:
+				fields = []*ast.MappingValueNode{node}
+			default:
+				return fmt.Errorf("line %d: list entry must be a mapping", line)
+			}
+
+			object := make(map[string]any, len(fields))
+			for _, field := range fields {
+				key := field.Key.GetToken().Value
+				switch fieldValue := field.Value.(type) {
+				case *ast.NullNode:
+				case *ast.MappingNode, *ast.MappingValueNode, *ast.SequenceNode:
+					return fmt.Errorf("line %d: unsupported value for %s", field.Value.GetToken().Position.Line, key)
+				default:
+					// convert scalars as written so hex keys and addresses are not read as numbers
+					value, err := listValue(columns[key], fieldValue.GetToken().Value)
+					if err != nil {
+						return fmt.Errorf("line %d: invalid value for %s: %w", field.Value.GetToken().Position.Line, key, err)
+					}
+					object[key] = value
+				}
+			}
+
+			var entry T
+			if err := decodeListObject(object, &entry); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			decoded = append(decoded, entry)
+		}
+	}
+
+	*entries = decoded
+	return nil
+}
+
+func decodeCSVList[T any](content []byte, entries *[]T) error {
+
+	columns := listColumns(reflect.TypeOf(*new(T)))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		*entries = nil
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for i, column := range header {
+		header[i] = strings.TrimSpace(column)
+		if _, ok := columns[header[i]]; !ok {
+			line, _ := reader.FieldPos(i)
+			return fmt.Errorf("line %d: unknown column %q", line, header[i])
+		}
+	}
+
+	decoded := []T{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+
+		object := make(map[string]any, len(record))
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			value, err := listValue(columns[header[i]], cell)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid value %q for %s", line, cell, header[i])
+			}
+			object[header[i]] = value
+		}
+
+		var entry T
+		if err := decodeListObject(object, &entry); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		decoded = append(decoded, entry)
+	}
+
+	*entries = decoded
+	return nil
+}
+
+// listValue converts a YAML or CSV scalar to the JSON value of a field of the given kind
+func listValue(kind reflect.Kind, raw string) (any, error) {
+	switch kind {
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, err
+		}
+		return json.Number(raw), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseUint(raw, 10, 64); err != nil {
+			return nil, err
+		}
+		return json.Number(raw), nil
+	default:
+		return raw, nil
+	}
+}
+
+func decodeListObject(object map[string]any, entry any) error {
+	content, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(entry)
+}
+
+func encodeYAMLList[T any](entries []T) ([]byte, error) {
+
+	if len(entries) == 0 {
+		return []byte("[]\n"), nil
+	}
+
+	names := listColumnNames(reflect.TypeOf(*new(T)))
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		object, err := encodeListObject(entry)
+		if err != nil {
+			return nil, err
+		}
+		prefix := "- "
+		for _, name := range names {
+			value, ok := object[name]
+			if !ok {
+				continue
+			}
+			if s, isString := value.(string); isString {
+				value = strconv.Quote(s)
+			}
+			fmt.Fprintf(&buf, "%s%s: %v\n", prefix, name, value)
+			prefix = "  "
+		}
+		if prefix == "- " {
+			buf.WriteString("- {}\n")
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeCSVList[T any](entries []T) ([]byte, error) {
+
+	names := listColumnNames(reflect.TypeOf(*new(T)))
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(names); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		object, err := encodeListObject(entry)
+		if err != nil {
+			return nil, err
+		}
+		record := make([]string, len(names))
+		for i, name := range names {
+			if value, ok := object[name]; ok {
+				record[i] = fmt.Sprint(value)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+
+	return buf.Bytes(), writer.Error()
+}
+
+// encodeListObject returns the JSON fields of an entry, leaving out zero keys and addresses
+func encodeListObject(entry any) (map[string]any, error) {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	object := make(map[string]any)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	for name, value := range object {
+		if s, ok := value.(string); ok && zeroHexPattern.MatchString(s) {
+			delete(object, name)
+		}
+	}
+	return object, nil
+}
+
+// listColumns returns the JSON field names of a list entry type with their kinds
+func listColumns(entryType reflect.Type) map[string]reflect.Kind {
+	columns := make(map[string]reflect.Kind)
+	for i := 0; i < entryType.NumField(); i++ {
+		field := entryType.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		columns[jsonFieldName(field)] = fieldType.Kind()
+	}
+	return columns
+}
+
+// listColumnNames returns the JSON field names of a list entry type in field order
+func listColumnNames(entryType reflect.Type) []string {
+	names := make([]string, 0, entryType.NumField())
+	for i := 0; i < entryType.NumField(); i++ {
+		names = append(names, jsonFieldName(entryType.Field(i)))
+	}
+	return names
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func lineAt(content []byte, offset int64) int {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
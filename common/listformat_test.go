@@ -0,0 +1,208 @@
+package common_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+const (
+	testListPubKey       = "0xa1d1ad0714035353258038e964ae9675dc0252ee22cea896825c01458e1807bfad2f9969338798548d9858a571f7425c"
+	testListFeeRecipient = "0x1111111111111111111111111111111111111111"
+)
+
+func TestDetectListFormat(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     string
+	}{
+		{name: "json extension", filePath: "list.json", content: "- publicKey: 0x01", want: k2common.ListFormatJSON},
+		{name: "yaml extension", filePath: "list.yaml", content: "[]", want: k2common.ListFormatYAML},
+		{name: "yml extension", filePath: "list.YML", content: "[]", want: k2common.ListFormatYAML},
+		{name: "csv extension", filePath: "list.csv", content: "[]", want: k2common.ListFormatCSV},
+		{name: "empty content", filePath: "list", content: "", want: k2common.ListFormatJSON},
+		{name: "json array", filePath: "list", content: "  [\n{}]", want: k2common.ListFormatJSON},
+		{name: "json null", filePath: "list", content: "null", want: k2common.ListFormatJSON},
+		{name: "yaml sequence", filePath: "list", content: "- publicKey: 0x01, 0x02", want: k2common.ListFormatYAML},
+		{name: "yaml comment", filePath: "list", content: "# a, b\n- publicKey: 0x01", want: k2common.ListFormatYAML},
+		{name: "csv header", filePath: "list", content: "publicKey,allowNativeDelegation\n0x01,true", want: k2common.ListFormatCSV},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := k2common.DetectListFormat(tt.filePath, []byte(tt.content)); got != tt.want {
+				t.Errorf("DetectListFormat() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeList(t *testing.T) {
+
+	want := k2common.ValidatorFilter{
+		FeeRecipient:         common.HexToAddress(testListFeeRecipient),
+		ProposerRegistration: true,
+		NativeDelegation:     false,
+		Priority:             5,
+	}
+	copy(want.PublicKey[:], common.FromHex(testListPubKey))
+
+	tests := []struct {
+		name      string
+		filePath  string
+		content   string
+		wantCount int
+		wantErr   string // substring of the expected error, including the line of the offending entry
+	}{
+		{
+			name:      "json",
+			filePath:  "list.json",
+			content:   `[{"publicKey": "` + testListPubKey + `", "feeRecipientAddress": "` + testListFeeRecipient + `", "allowProposerRegistration": true, "priority": 5}]`,
+			wantCount: 1,
+		},
+		{
+			name:      "yaml",
+			filePath:  "list.yaml",
+			content:   "# exclusions\n- publicKey: " + testListPubKey + "\n  feeRecipientAddress: " + testListFeeRecipient + "\n  allowProposerRegistration: true\n  priority: 5\n",
+			wantCount: 1,
+		},
+		{
+			name:      "csv",
+			filePath:  "list.csv",
+			content:   "publicKey,feeRecipientAddress,allowProposerRegistration,allowNativeDelegation,priority\n" + testListPubKey + "," + testListFeeRecipient + ",true,false,5\n",
+			wantCount: 1,
+		},
+		{name: "empty json", filePath: "list.json", content: "", wantCount: 0},
+		{name: "null json", filePath: "list.json", content: "null", wantCount: 0},
+		{name: "empty yaml", filePath: "list.yaml", content: "", wantCount: 0},
+		{name: "empty csv", filePath: "list.csv", content: "", wantCount: 0},
+		{
+			name:     "json not an array",
+			filePath: "list.json",
+			content:  "\n{}",
+			wantErr:  "line 2: list must be an array of entries",
+		},
+		{
+			name:     "json invalid entry",
+			filePath: "list.json",
+			content:  "[\n  {\"priority\": 1},\n  {\"priority\": \"high\"}\n]",
+			wantErr:  "line 3:",
+		},
+		{
+			name:     "json unterminated",
+			filePath: "list.json",
+			content:  "[\n  {\"priority\": 1},\n",
+			wantErr:  "line 3:",
+		},
+		{
+			name:     "yaml not a sequence",
+			filePath: "list.yaml",
+			content:  "publicKey: " + testListPubKey,
+			wantErr:  "line 1: list must be a sequence of entries",
+		},
+		{
+			name:     "yaml scalar entry",
+			filePath: "list.yaml",
+			content:  "- priority: 1\n- " + testListPubKey,
+			wantErr:  "line 2: list entry must be a mapping",
+		},
+		{
+			name:     "yaml invalid value",
+			filePath: "list.yaml",
+			content:  "- priority: 1\n- allowProposerRegistration: true\n  priority: high",
+			wantErr:  "line 3: invalid value for priority",
+		},
+		{
+			name:     "yaml unknown key",
+			filePath: "list.yaml",
+			content:  "- priority: 1\n- pubkey: " + testListPubKey,
+			wantErr:  "line 2:",
+		},
+		{
+			name:     "csv unknown column",
+			filePath: "list.csv",
+			content:  "# exclusions\npublicKey,pubkey\n",
+			wantErr:  `line 2: unknown column "pubkey"`,
+		},
+		{
+			name:     "csv invalid value",
+			filePath: "list.csv",
+			content:  "publicKey,allowNativeDelegation\n" + testListPubKey + ",true\n" + testListPubKey + ",maybe\n",
+			wantErr:  `line 3: invalid value "maybe" for allowNativeDelegation`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []k2common.ValidatorFilter
+			err := k2common.DecodeList(tt.filePath, []byte(tt.content), &entries)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DecodeList() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeList() error = %v", err)
+			}
+			if len(entries) != tt.wantCount {
+				t.Fatalf("DecodeList() decoded %d entries, want %d", len(entries), tt.wantCount)
+			}
+			for _, entry := range entries {
+				if entry.PublicKey != want.PublicKey || entry.FeeRecipient != want.FeeRecipient ||
+					entry.ProposerRegistration != want.ProposerRegistration || entry.NativeDelegation != want.NativeDelegation ||
+					entry.Priority != want.Priority {
+					t.Errorf("DecodeList() entry = %+v, want %+v", entry, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeList_RoundTrip(t *testing.T) {
+
+	minValidatorIndex := uint64(100)
+	entries := []k2common.ValidatorFilter{
+		{FeeRecipient: common.HexToAddress(testListFeeRecipient), NativeDelegation: true},
+		{Rule: "large-index", Priority: 2, MinValidatorIndex: &minValidatorIndex},
+	}
+	copy(entries[0].PublicKey[:], common.FromHex(testListPubKey))
+
+	for _, format := range k2common.ListFormats {
+		t.Run(format, func(t *testing.T) {
+			content, err := k2common.EncodeList(entries, format)
+			if err != nil {
+				t.Fatalf("EncodeList() error = %v", err)
+			}
+			if format != k2common.ListFormatJSON && strings.Contains(string(content), "0x0000000000") {
+				t.Errorf("EncodeList() kept a zero key or address:\n%s", content)
+			}
+
+			var decoded []k2common.ValidatorFilter
+			if err := k2common.DecodeList("list."+format, content, &decoded); err != nil {
+				t.Fatalf("DecodeList() error = %v\n%s", err, content)
+			}
+			if len(decoded) != len(entries) {
+				t.Fatalf("decoded %d entries, want %d", len(decoded), len(entries))
+			}
+			for i := range entries {
+				got, want := decoded[i], entries[i]
+				if got.PublicKey != want.PublicKey || got.FeeRecipient != want.FeeRecipient ||
+					got.NativeDelegation != want.NativeDelegation || got.Rule != want.Rule || got.Priority != want.Priority ||
+					(got.MinValidatorIndex == nil) != (want.MinValidatorIndex == nil) ||
+					(got.MinValidatorIndex != nil && *got.MinValidatorIndex != *want.MinValidatorIndex) {
+					t.Errorf("entry %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := k2common.EncodeList(entries, "toml"); err == nil {
+		t.Error("EncodeList() with an unknown format returned no error")
+	}
+}
@@ -3,6 +3,8 @@ package common
 import (
 	"crypto/ecdsa"
 	"encoding/json"
+	"math/big"
+	"time"
 
 	apiv1 "github.com/attestantio/go-builder-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -28,22 +30,67 @@ type K2ValidatorRegistration struct {
 	K2Success                   bool                               `json:"k2Success"`
 }
 
+// RegisterResult is the outcome of the registrations sent to the register endpoint when the excluded registrations are asked for
+type RegisterResult struct {
+	Registrations []K2ValidatorRegistration `json:"registrations"`
+	Invalid       map[string]string         `json:"invalid,omitempty"` // [Validator pubKey] -> Reason the registration was excluded
+}
+
 type ValidatorFilter struct {
 	PublicKey            phase0.BLSPubKey `json:"publicKey,omitempty"`
 	FeeRecipient         common.Address   `json:"feeRecipientAddress,omitempty"`
 	ProposerRegistration bool             `json:"allowProposerRegistration"`
 	NativeDelegation     bool             `json:"allowNativeDelegation"`
+
+	// Rule entries match validators by the conditions below instead of by BLS key or fee recipient
+	Rule              string  `json:"rule,omitempty"`              // unique name of the rule
+	Priority          int64   `json:"priority,omitempty"`          // rules with a higher priority are evaluated first
+	MinValidatorIndex *uint64 `json:"minValidatorIndex,omitempty"` // validator index on the beacon node
+	MaxValidatorIndex *uint64 `json:"maxValidatorIndex,omitempty"`
+	Web3SignerKey     *bool   `json:"web3SignerKey,omitempty"` // whether the key is loaded in the web3signer
+	MinGasLimit       *uint64 `json:"minGasLimit,omitempty"`   // gas limit of the registration message
+	MaxGasLimit       *uint64 `json:"maxGasLimit,omitempty"`
+	Tag               string  `json:"tag,omitempty"` // tag attached to the key in the validator metadata file
+}
+
+// HasRuleConditions returns whether any of the conditions of a rule entry are set
+func (f ValidatorFilter) HasRuleConditions() bool {
+	return f.MinValidatorIndex != nil || f.MaxValidatorIndex != nil || f.Web3SignerKey != nil ||
+		f.MinGasLimit != nil || f.MaxGasLimit != nil || f.Tag != ""
+}
+
+type ValidatorTag struct {
+	PublicKey phase0.BLSPubKey `json:"publicKey"`
+	Tag       string           `json:"tag"`
+}
+
+type DelegationPolicy struct {
+	RepresentativeAddress  common.Address `json:"representativeAddress"`
+	MaxDelegationsPerDay   uint64         `json:"maxDelegationsPerDay,omitempty"`   // new native delegations per UTC day, unlimited if zero
+	MaxDelegationsPerEpoch uint64         `json:"maxDelegationsPerEpoch,omitempty"` // new native delegations per beacon chain epoch, unlimited if zero
+	WindowDays             string         `json:"windowDays,omitempty"`             // comma separated UTC weekdays delegations are allowed on, every day if empty
+	WindowStart            string         `json:"windowStart,omitempty"`            // UTC time of day (HH:MM) delegations are allowed from
+	WindowEnd              string         `json:"windowEnd,omitempty"`              // UTC time of day (HH:MM) delegations are allowed until
+}
+
+type DeferredDelegation struct {
+	ValidatorPubKey       string         `json:"validatorPubKey"`
+	RepresentativeAddress common.Address `json:"representativeAddress"`
+	Reason                string         `json:"reason"`
+	DeferredAt            time.Time      `json:"deferredAt"` // when the native delegation was first deferred
+	Attempts              uint64         `json:"attempts"`   // the number of times the native delegation has been deferred
 }
 
 type CustomPayoutRepresentative struct {
 	RepresentativeAddress common.Address   `json:"representativeAddress"`
 	FeeRecipientAddress   common.Address   `json:"feeRecipientAddress,omitempty"`
 	PublicKey             phase0.BLSPubKey `json:"publicKey,omitempty"`
+	DerivationIndex       *uint32          `json:"derivationIndex,omitempty"` // index of the representative wallet derived from the mnemonic
 }
 
 type K2Claim struct {
 	RepresentativeAddress common.Address `json:"representativeAddress"`
-	ClaimAmount           uint64         `json:"claimAmount"`
+	ClaimAmount           uint64         `json:"claimAmount,omitempty"` // node operator rewards claimed with nodeOperatorClaim
 
 	// Data used internally to claim rewards
 	// Reward claiming requires at least one validate balance report
@@ -51,6 +98,55 @@ type K2Claim struct {
 	EffectiveBalanceReportSignature EcdsaSignature   `json:"-"`
 	EffectiveBalance                uint64           `json:"-"`
 	ValidatorPubKey                 phase0.BLSPubKey `json:"-"`
+
+	ClaimRecipient *common.Address `json:"claimRecipient,omitempty"` // delegated recipient the rewards were claimed to, if any
+	Error          string          `json:"error,omitempty"`          // why the claim failed, failed claims are returned alongside the claims made
+
+	// Resulting KETH balances of the representative in the K2 lending contract
+	ClaimedKETH     *big.Int      `json:"claimedKETH,omitempty"`     // claimed to the representative wallet with claimKETH
+	TransferredKETH *big.Int      `json:"transferredKETH,omitempty"` // transferred from the representative wallet to the treasury
+	ClaimableKETH   *big.Int      `json:"claimableKETH,omitempty"`   // left to claim with claimKETH
+	KETHBalance     *big.Int      `json:"kethBalance,omitempty"`     // held by the representative wallet
+	TxHashes        []common.Hash `json:"txHashes,omitempty"`        // of the KETH claim and transfer transactions
+}
+
+type RewardsWallet struct {
+	RepresentativeAddress common.Address `json:"representativeAddress"`
+	Symbol                string         `json:"symbol"`
+	Decimals              uint8          `json:"decimals"`
+	ClaimableRewards      uint64         `json:"claimableRewards"` // node operator rewards to claim with nodeOperatorClaim
+	ClaimableKETH         *big.Int       `json:"claimableKETH"`    // to claim with claimKETH
+	ClaimedKETH           *big.Int       `json:"claimedKETH"`      // claimed with claimKETH by the module since it started
+	HeldKETH              *big.Int       `json:"heldKETH"`         // held by the representative wallet
+}
+
+type LendPosition struct {
+	CumulativeKethPerShare *big.Int `json:"cumulativeKethPerShare"` // per share of the node operator at its last update, in RAY
+	KethEarned             *big.Int `json:"kethEarned"`
+}
+
+type NodeOperatorStatus struct {
+	RepresentativeAddress common.Address     `json:"representativeAddress"`
+	LendPosition          LendPosition       `json:"lendPosition"`
+	BlsPublicKeyCount     uint64             `json:"blsPublicKeyCount"`
+	DelegatedValidators   []phase0.BLSPubKey `json:"delegatedValidators"`
+	KickedValidators      []phase0.BLSPubKey `json:"kickedValidators"`
+	Banned                bool               `json:"banned"`
+	InclusionList         common.Address     `json:"inclusionList"` // the zero address if no inclusion list is enforced
+	PartOfInclusionList   bool               `json:"partOfInclusionList"`
+}
+
+type NativeDelegationPrechecks struct {
+	PartOfInclusionList           bool
+	IndividualMaxNativeDelegation *big.Int
+	TotalNativeDelegations        *big.Int        // of the representative
+	PermittedValidators           map[string]bool // [Validator pubKey] -> Whether the node operator contract permits the key to be natively delegated
+}
+
+type KickedValidator struct {
+	ValidatorPubKey       phase0.BLSPubKey `json:"validatorPubKey"`
+	RepresentativeAddress common.Address   `json:"representativeAddress"`
+	TxHash                common.Hash      `json:"txHash"` // of the kick, the zero hash if only seen in the contract state
 }
 
 type K2Exit struct {
@@ -151,3 +247,102 @@ type ChangedK2PayoutRepresentative struct {
 	TxHash                common.Hash    `json:"txHash"`
 	Success               bool           `json:"success"`
 }
+
+type ClaimRecipient struct {
+	RepresentativeAddress common.Address `json:"representativeAddress"`
+	ClaimRecipient        common.Address `json:"claimRecipient"`
+}
+
+type ChangedClaimRecipient struct {
+	RepresentativeAddress  common.Address `json:"representativeAddress"`
+	PreviousClaimRecipient common.Address `json:"previousClaimRecipient"`
+	NewClaimRecipient      common.Address `json:"newClaimRecipient"`
+	TxHash                 common.Hash    `json:"txHash"`
+	Success                bool           `json:"success"`
+}
+
+type DependencyHealth struct {
+	Name         string `json:"name"`
+	Required     bool   `json:"required"`
+	Reachable    bool   `json:"reachable"`
+	Syncing      bool   `json:"syncing"`
+	SyncDistance uint64 `json:"syncDistance,omitempty"`
+	ChainID      uint64 `json:"chainId,omitempty"`
+	LatencyMs    int64  `json:"latencyMs"`
+	Error        string `json:"error,omitempty"`
+
+	Endpoints []EndpointHealth `json:"endpoints,omitempty"` // for dependencies served by a failover pool of endpoints or nodes
+}
+
+type EndpointHealth struct {
+	Url                 string     `json:"url"`
+	Verified            bool       `json:"verified"`
+	Circuit             string     `json:"circuit,omitempty"` // for endpoints failed over on error
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	CircuitOpenUntil    *time.Time `json:"circuitOpenUntil,omitempty"`
+	LastError           string     `json:"lastError,omitempty"`
+
+	// for nodes selected by their health
+	Active       bool   `json:"active,omitempty"`
+	Healthy      bool   `json:"healthy,omitempty"`
+	SyncDistance uint64 `json:"syncDistance,omitempty"`
+}
+
+type WalletHealth struct {
+	RepresentativeAddress common.Address    `json:"representativeAddress"`
+	Balance               float64           `json:"balance"` // in ETH
+	LowBalance            bool              `json:"lowBalance"`
+	Runway                map[string]uint64 `json:"runway,omitempty"` // batches the wallet can fund per operation as last estimated
+	Error                 string            `json:"error,omitempty"`
+}
+
+type HealthStatus struct {
+	Live                             bool               `json:"live"`
+	Ready                            bool               `json:"ready"`
+	CheckedAt                        time.Time          `json:"checkedAt"` // when the dependencies and wallets were last checked
+	Dependencies                     []DependencyHealth `json:"dependencies"`
+	Wallets                          []WalletHealth     `json:"wallets"`
+	LowBalanceThreshold              float64            `json:"lowBalanceThreshold"` // in ETH
+	LastRegistrationMessageTimestamp *time.Time         `json:"lastRegistrationMessageTimestamp,omitempty"`
+	PendingJobs                      map[string]int     `json:"pendingJobs"`
+	DeferredDelegations              int                `json:"deferredDelegations"` // native delegations queued by the delegation policies
+}
+
+type DecisionNode struct {
+	Check    string         `json:"check"`
+	Outcome  string         `json:"outcome"`
+	Reason   string         `json:"reason,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
+	Children []DecisionNode `json:"children,omitempty"`
+}
+
+type RegistrationExplanation struct {
+	ValidatorPubKey       phase0.BLSPubKey                   `json:"validatorPubKey"`
+	Registration          *apiv1.SignedValidatorRegistration `json:"registration"` // the last registration message seen for the validator
+	RepresentativeAddress common.Address                     `json:"representativeAddress"`
+	PayoutRecipient       common.Address                     `json:"payoutRecipient"`
+	ProposerRegistration  string                             `json:"proposerRegistration"` // the action that would be taken for the Proposer Registry
+	NativeDelegation      string                             `json:"nativeDelegation"`     // the action that would be taken for K2 native delegation
+	Decisions             []DecisionNode                     `json:"decisions"`
+}
+
+type ListChange struct {
+	List               string   `json:"list"`
+	Key                string   `json:"key"`                // the validator BLS key or fee recipient of the changed entry
+	Entries            int      `json:"entries"`            // the number of entries in the list after the change
+	AffectedValidators []string `json:"affectedValidators"` // recently seen validators the changed entry applies to
+}
+
+type ListIssue struct {
+	Severity string `json:"severity"` // error issues cause a list change or reload to be refused
+	Kind     string `json:"kind"`
+	Key      string `json:"key"` // the validator BLS key or fee recipient the issue was found for
+	Message  string `json:"message"`
+}
+
+type ListValidation struct {
+	Valid                    bool        `json:"valid"`
+	FilterPrecedence         []string    `json:"filterPrecedence"`         // the order exclusion and inclusion list entries are matched in, the first match applies
+	RepresentativePrecedence []string    `json:"representativePrecedence"` // the order representatives are selected in, the first match applies
+	Issues                   []ListIssue `json:"issues"`
+}
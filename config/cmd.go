@@ -15,6 +15,9 @@ func NewCommand() *cli.Command {
 		UsageText: "The K2 native delegation module is responsible for on-chain registration of native delegations and proposer registries",
 		Category:  strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 		Flags:     k2Flags(),
+		Subcommands: []*cli.Command{
+			NewConvertListCommand(),
+		},
 	}
 }
 
@@ -22,6 +25,8 @@ func k2Flags() []cli.Flag {
 	return []cli.Flag{
 		LoggerLevelFlag,
 		WalletPrivateKeyFlag,
+		WalletMnemonicFlag,
+		WalletDerivationPathFlag,
 		Web3SignerUrlFlag,
 		PayoutRecipientFlag,
 		BeaconNodeUrlFlag,
@@ -29,15 +34,35 @@ func k2Flags() []cli.Flag {
 		ExclusionListFlag,
 		StrictInclusionListFileFlag,
 		RepresentativeMappingFlag,
+		ValidatorMetadataFlag,
+		DelegationPolicyFlag,
+		RepresentativeStrategyFlag,
+		RepresentativeAssignmentFileFlag,
 		MaxGasPriceFlag,
 		RegistrationOnlyFlag,
 		ListenAddressFlag,
 		ClaimThresholdFlag,
+		KETHTreasuryFlag,
 		K2LendingContractAddressFlag,
 		K2NodeOperatorContractAddressFlag,
 		ProposerRegistryContractAddressFlag,
 		SignatureSwapperUrlFlag,
 		BalanceVerificationUrlFlag,
 		SubgraphUrlFlag,
+		LowBalanceThresholdFlag,
+		BalanceCheckIntervalFlag,
+		RunwayWarningThresholdFlag,
+		RunwayAlertThresholdFlag,
+		NodeOperatorCheckIntervalFlag,
+		NodeHealthCheckIntervalFlag,
+		WebhookUrlsFlag,
+		WebhookSecretFlag,
+		WebhookEventsFlag,
+		WebhookRepresentativesFlag,
+		WebhookMaxRetriesFlag,
+		WebhookDeadLetterFileFlag,
+		AuditLogFileFlag,
+		ListPollIntervalFlag,
+		ListSignerAddressFlag,
 	}
 }
\ No newline at end of file
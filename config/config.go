@@ -2,51 +2,111 @@ package config
 
 import (
 	"net/url"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
 )
 
+const (
+	// Strategies to select the representative for fee recipients without a mapped representative
+	RepresentativeStrategyPriority   = "priority"    // the first eligible wallet in the configured order
+	RepresentativeStrategyCapacity   = "capacity"    // the eligible wallet with the most remaining individual native delegation capacity
+	RepresentativeStrategyBalance    = "balance"     // the eligible wallet with the highest ETH balance
+	RepresentativeStrategyRoundRobin = "round-robin" // the eligible wallets in turn
+)
+
+var RepresentativeStrategies = []string{
+	RepresentativeStrategyPriority,
+	RepresentativeStrategyCapacity,
+	RepresentativeStrategyBalance,
+	RepresentativeStrategyRoundRobin,
+}
+
 type K2Config struct {
 	LoggerLevel                     string
 	ValidatorWallets                []k2common.ValidatorWallet
+	WalletMnemonic                  string // BIP-39 mnemonic to derive new representative wallets from
+	WalletDerivationPath            string // BIP-44 path the index of each derived wallet is appended to
 	Web3SignerUrl                   *url.URL
-	SignatureSwapperUrl             *url.URL
-	BeaconNodeUrl                   *url.URL
-	ExecutionNodeUrl                *url.URL
+	SignatureSwapperUrls            []*url.URL // in order of preference, failed over on error
+	BeaconNodeUrls                  []*url.URL // in order of preference, failed over to by health
+	ExecutionNodeUrls               []*url.URL // in order of preference, failed over to by health
 	K2LendingContractAddress        common.Address
 	K2NodeOperatorContractAddress   common.Address
 	ProposerRegistryContractAddress common.Address
-	BalanceVerificationUrl          *url.URL       // for effective balance reporting for verifiable signatures to claim rewards
+	BalanceVerificationUrls         []*url.URL     // for effective balance reporting for verifiable signatures to claim rewards, in order of preference
 	SubgraphUrl                     *url.URL       // for querying the subgraph for validator registration status
 	PayoutRecipient                 common.Address // to override the payout recipient for all validators
-	ExclusionListFile               string         // to exclude validators from registration or native delegation
-	StrictInclusionListFile         string         // to include only specified validators in registration or native delegation
-	RepresentativeMappingFile       string         // to map fee recipients / specific validators to representatives
+	ExclusionListFile               string         // file or url of the list to exclude validators from registration or native delegation
+	StrictInclusionListFile         string         // file or url of the list to include only specified validators in registration or native delegation
+	RepresentativeMappingFile       string         // file or url of the mapping of fee recipients / specific validators to representatives
+	ValidatorMetadataFile           string         // file or url of the tags attached to validator keys for list rules
+	DelegationPolicyFile            string         // file or url of the per-representative native delegation quotas and windows
+	RepresentativeStrategy          string         // how to select the representative for fee recipients without a mapped representative
+	RepresentativeAssignmentFile    string         // file to persist the representative selected for each fee recipient to
 	MaxGasPrice                     uint64
 	RegistrationOnly                bool
 	ListenAddress                   *url.URL
-	ClaimThreshold                  float64 // To only claim rewards if the validator has earned more than this threshold (in KETH)
+	ClaimThreshold                  float64          // To only claim rewards if the validator has earned more than this threshold (in KETH)
+	KETHTreasury                    common.Address   // to transfer the KETH claimed by the representative wallets to
+	LowBalanceThreshold             float64          // To report representative wallets with less than this balance (in ETH) as low
+	BalanceCheckInterval            time.Duration    // How often to check the representative wallet balances
+	RunwayWarningThreshold          uint64           // To warn when a representative wallet can fund less than this many batches
+	RunwayAlertThreshold            uint64           // To alert when a representative wallet can fund less than this many batches
+	NodeOperatorCheckInterval       time.Duration    // How often to check the representatives and their validators for bans and kicks
+	NodeHealthCheckInterval         time.Duration    // How often to check the beacon and execution nodes and fail over between them
+	WebhookUrls                     []*url.URL       // to post event notifications to
+	WebhookSecret                   string           // to sign the webhook payloads
+	WebhookEvents                   []string         // to only notify these event types
+	WebhookRepresentatives          []common.Address // to only notify events for these representatives
+	WebhookMaxRetries               uint64
+	WebhookDeadLetterFile           string         // to record notifications that could not be delivered
+	AuditLogFile                    string         // to record the decisions taken for every validator
+	ListPollInterval                time.Duration  // How often to poll the lists set to a url
+	ListSignerAddress               common.Address // to only accept lists fetched from a url if signed by this address
 }
 
 var K2ConfigDefaults = K2Config{
 	LoggerLevel:                     "info",
 	ValidatorWallets:                nil,
+	WalletMnemonic:                  "",
+	WalletDerivationPath:            k2common.DefaultDerivationPath,
 	Web3SignerUrl:                   nil,
-	SignatureSwapperUrl:             nil,
-	BeaconNodeUrl:                   nil,
-	ExecutionNodeUrl:                nil,
+	SignatureSwapperUrls:            nil,
+	BeaconNodeUrls:                  nil,
+	ExecutionNodeUrls:               nil,
 	K2LendingContractAddress:        common.Address{},
 	K2NodeOperatorContractAddress:   common.Address{},
 	ProposerRegistryContractAddress: common.Address{},
-	BalanceVerificationUrl:          nil,
+	BalanceVerificationUrls:         nil,
 	SubgraphUrl:                     nil,
 	PayoutRecipient:                 common.Address{},
 	ExclusionListFile:               "",
 	StrictInclusionListFile:         "",
 	RepresentativeMappingFile:       "",
+	ValidatorMetadataFile:           "",
+	DelegationPolicyFile:            "",
+	RepresentativeStrategy:          RepresentativeStrategyPriority,
+	RepresentativeAssignmentFile:    "",
 	MaxGasPrice:                     0,
 	RegistrationOnly:                false,
 	ListenAddress:                   &url.URL{Scheme: "http", Host: "localhost:10000"},
 	ClaimThreshold:                  0.0,
+	KETHTreasury:                    common.Address{},
+	LowBalanceThreshold:             0.05,
+	BalanceCheckInterval:            5 * time.Minute,
+	RunwayWarningThreshold:          10,
+	RunwayAlertThreshold:            3,
+	NodeOperatorCheckInterval:       5 * time.Minute,
+	NodeHealthCheckInterval:         12 * time.Second,
+	WebhookUrls:                     nil,
+	WebhookSecret:                   "",
+	WebhookEvents:                   nil,
+	WebhookRepresentatives:          nil,
+	WebhookMaxRetries:               3,
+	WebhookDeadLetterFile:           "",
+	AuditLogFile:                    "",
+	ListPollInterval:                time.Minute,
+	ListSignerAddress:               common.Address{},
 }
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cli "github.com/urfave/cli/v2"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+// NewConvertListCommand returns the command converting list files between the list formats
+func NewConvertListCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "convert-list",
+		Usage:     "Convert an exclusion list, strict inclusion list or representative mapping file between JSON, YAML and CSV",
+		UsageText: "convert-list --list <exclusion|inclusion|representatives> --input <file> [--output <file>] [--format <json|yaml|csv>]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "list",
+				Usage:    "The kind of list to convert: exclusion, inclusion or representatives",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "input",
+				Usage:    "The list file to convert, its format is detected from the file extension or content",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "The file to write the converted list to, the list is written to stdout if not set",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "The format to convert to (json, yaml or csv), defaults to the format of the output file extension",
+			},
+		},
+		Action: convertList,
+	}
+}
+
+func convertList(ctx *cli.Context) error {
+
+	inputFile, outputFile := ctx.String("input"), ctx.String("output")
+
+	format := strings.ToLower(ctx.String("format"))
+	if format == "" {
+		if outputFile == "" {
+			return fmt.Errorf("--format is required when writing to stdout")
+		}
+		format = k2common.DetectListFormat(outputFile, nil)
+	}
+
+	inputContent, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read list file: %w", err)
+	}
+
+	var outputContent []byte
+	switch ctx.String("list") {
+	case "exclusion", "inclusion":
+		var entries []k2common.ValidatorFilter
+		if err := k2common.DecodeList(inputFile, inputContent, &entries); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", inputFile, err)
+		}
+		outputContent, err = k2common.EncodeList(entries, format)
+	case "representatives":
+		var entries []k2common.CustomPayoutRepresentative
+		if err := k2common.DecodeList(inputFile, inputContent, &entries); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", inputFile, err)
+		}
+		outputContent, err = k2common.EncodeList(entries, format)
+	default:
+		return fmt.Errorf("unknown list %q, must be one of exclusion, inclusion or representatives", ctx.String("list"))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode list: %w", err)
+	}
+
+	if outputFile == "" {
+		_, err = os.Stdout.Write(outputContent)
+		return err
+	}
+
+	return os.WriteFile(outputFile, outputContent, 0644)
+}
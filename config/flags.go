@@ -2,7 +2,9 @@ package config
 
 import (
 	"strings"
+	"time"
 
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
 	cli "github.com/urfave/cli/v2"
 )
 
@@ -19,6 +21,18 @@ var (
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 		EnvVars:  []string{"ETH1_PRIVATE_KEY"},
 	}
+	WalletMnemonicFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "eth1-mnemonic",
+		Usage:    "The BIP-39 mnemonic to derive a new representative wallet from for each new fee recipient",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		EnvVars:  []string{"ETH1_MNEMONIC"},
+	}
+	WalletDerivationPathFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "eth1-derivation-path",
+		Usage:    "The BIP-44 derivation path the index of each representative wallet derived from the mnemonic is appended to",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    k2common.DefaultDerivationPath,
+	}
 	Web3SignerUrlFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "web3-signer-url",
 		Usage:    "The url of the web3 signer",
@@ -31,27 +45,48 @@ var (
 	}
 	BeaconNodeUrlFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "beacon-node-url",
-		Usage:    "The url of the beacon node",
+		Usage:    "The url of the beacon node. You can set multiple urls to fail over between, in order of preference, by separating them with a comma",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
 	ExecutionNodeUrlFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "execution-node-url",
-		Usage:    "The url of the execution node",
+		Usage:    "The url of the execution node. You can set multiple urls to fail over between, in order of preference, by separating them with a comma",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
 	ExclusionListFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "exclusion-list",
-		Usage:    "The list of addresses to exclude from either the Proposer Registration or Native Delegation",
+		Usage:    "The file or http(s) url of the list of addresses to exclude from either the Proposer Registration or Native Delegation",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
 	StrictInclusionListFileFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "strict-inclusion-list",
-		Usage:    "The list of addresses to include in either the Proposer Registration or Native Delegation",
+		Usage:    "The file or http(s) url of the list of addresses to include in either the Proposer Registration or Native Delegation",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
 	RepresentativeMappingFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "representative-mapping",
-		Usage:    "The mapping of representative addresses designated to handle validators that pay to different fee recipients",
+		Usage:    "The file or http(s) url of the mapping of representative addresses designated to handle validators that pay to different fee recipients",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
+	ValidatorMetadataFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "validator-metadata",
+		Usage:    "The file or http(s) url of the tags attached to validator keys, for exclusion and inclusion list rules to match validators by",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
+	DelegationPolicyFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "delegation-policy",
+		Usage:    "The file or http(s) url of the per-representative policies limiting new K2 native delegations to a quota per day or epoch and to maintenance windows",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
+	RepresentativeStrategyFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "representative-strategy",
+		Usage:    "How to select the representative wallet for fee recipients without a mapped representative: priority, capacity, balance or round-robin",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    RepresentativeStrategyPriority,
+	}
+	RepresentativeAssignmentFileFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "representative-assignment-file",
+		Usage:    "The file to persist the representative selected for each fee recipient to, so the selection stays stable. Required for strategies other than priority",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
 	MaxGasPriceFlag = &cli.Uint64Flag{
@@ -74,6 +109,11 @@ var (
 		Usage:    "The threshold for claiming rewards, in KETH",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
+	KETHTreasuryFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "keth-treasury",
+		Usage:    "The address to transfer the KETH claimed by the representative wallets to",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
 	K2LendingContractAddressFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "k2-lending-contract-address",
 		Usage:    "The address of the K2 lending contract to override the internal configuration",
@@ -91,12 +131,12 @@ var (
 	}
 	SignatureSwapperUrlFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "signature-swapper-url",
-		Usage:    "The url of the signature swapper to override the internal configuration",
+		Usage:    "The url of the signature swapper to override the internal configuration. You can set multiple urls to fail over between, in order of preference, by separating them with a comma",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
 	BalanceVerificationUrlFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "balance-verification-url",
-		Usage:    "The url of the balance verification service to override the internal configuration",
+		Usage:    "The url of the balance verification service to override the internal configuration. You can set multiple urls to fail over between, in order of preference, by separating them with a comma",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
 	SubgraphUrlFlag = &cli.StringFlag{
@@ -104,4 +144,88 @@ var (
 		Usage:    "The url of the subgraph to override the internal configuration",
 		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
 	}
+	LowBalanceThresholdFlag = &cli.Float64Flag{
+		Name:     ModuleName + "." + "low-balance-threshold",
+		Usage:    "The ETH balance below which a representative wallet is reported as low on the health endpoint",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    0.05,
+	}
+	BalanceCheckIntervalFlag = &cli.DurationFlag{
+		Name:     ModuleName + "." + "balance-check-interval",
+		Usage:    "How often to check the representative wallet balances and estimate their transaction runway",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    5 * time.Minute,
+	}
+	RunwayWarningThresholdFlag = &cli.Uint64Flag{
+		Name:     ModuleName + "." + "runway-warning-threshold",
+		Usage:    "The number of registration, delegation or claim batches a representative wallet can fund below which a warning is logged",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    10,
+	}
+	RunwayAlertThresholdFlag = &cli.Uint64Flag{
+		Name:     ModuleName + "." + "runway-alert-threshold",
+		Usage:    "The number of registration, delegation or claim batches a representative wallet can fund below which an alert is raised",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    3,
+	}
+	NodeOperatorCheckIntervalFlag = &cli.DurationFlag{
+		Name:     ModuleName + "." + "node-operator-check-interval",
+		Usage:    "How often to check if the representatives were banned or their validators kicked from the K2 contract",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    5 * time.Minute,
+	}
+	NodeHealthCheckIntervalFlag = &cli.DurationFlag{
+		Name:     ModuleName + "." + "node-health-check-interval",
+		Usage:    "How often to check the health of the beacon and execution nodes and fail over to the first healthy node",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    12 * time.Second,
+	}
+	WebhookUrlsFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "webhook-urls",
+		Usage:    "The urls to post module event notifications to. You can set multiple urls by separating them with a comma",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
+	WebhookSecretFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "webhook-secret",
+		Usage:    "The secret used to sign webhook payloads with HMAC-SHA256",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		EnvVars:  []string{"K2_WEBHOOK_SECRET"},
+	}
+	WebhookEventsFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "webhook-events",
+		Usage:    "The event types to post to the webhooks, separated by a comma. All events are posted if not set",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
+	WebhookRepresentativesFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "webhook-representatives",
+		Usage:    "The representative addresses to post events for, separated by a comma. Events for all representatives are posted if not set",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
+	WebhookMaxRetriesFlag = &cli.Uint64Flag{
+		Name:     ModuleName + "." + "webhook-max-retries",
+		Usage:    "The number of times to retry posting a notification to a webhook before it is dead-lettered",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    3,
+	}
+	WebhookDeadLetterFileFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "webhook-dead-letter-file",
+		Usage:    "The file to append notifications that could not be delivered to, as JSON lines",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
+	AuditLogFileFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "audit-log-file",
+		Usage:    "The file to append every registration, signing and transaction decision to, as JSON lines. Queryable through the audit API",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
+	ListPollIntervalFlag = &cli.DurationFlag{
+		Name:     ModuleName + "." + "list-poll-interval",
+		Usage:    "How often to poll the exclusion list, strict inclusion list and representative mapping when they are set to an http(s) url",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+		Value:    time.Minute,
+	}
+	ListSignerAddressFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "list-signer-address",
+		Usage:    "The address that must have signed the lists fetched from an http(s) url. The detached signature is fetched from the list url with a .sig suffix",
+		Category: strings.ReplaceAll(strings.ToUpper(ModuleName), "_", " "),
+	}
 )
@@ -0,0 +1,479 @@
+package k2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+)
+
+const slotsPerEpoch = 32
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// delegationPolicy is a parsed entry of the delegation policy file
+type delegationPolicy struct {
+	k2common.DelegationPolicy
+	days     map[time.Weekday]bool // weekdays the window is open on, every day if empty
+	windowed bool
+	start    time.Duration // since UTC midnight
+	end      time.Duration // since UTC midnight, before start if the window spans midnight
+}
+
+// delegationUsage counts the new native delegations made by a representative in the current day and epoch
+type delegationUsage struct {
+	day        string // UTC date
+	dayCount   uint64
+	epoch      uint64
+	epochCount uint64
+}
+
+// windowOpen returns whether new native delegations are allowed at the time. A window spanning midnight
+// is open on the listed days from its start, and on the following days until its end
+func (p delegationPolicy) windowOpen(now time.Time) bool {
+
+	if !p.windowed {
+		return true
+	}
+
+	now = now.UTC()
+	sinceMidnight := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	day := now.Weekday()
+
+	if p.start > p.end && sinceMidnight < p.end {
+		// in the part of the window after midnight, opened the previous day
+		day = (day + 6) % 7
+	} else if p.start < p.end && (sinceMidnight < p.start || sinceMidnight >= p.end) {
+		return false
+	} else if p.start > p.end && sinceMidnight < p.start {
+		return false
+	}
+
+	return len(p.days) == 0 || p.days[day]
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, must be HH:MM", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+func (k2 *K2Service) parseDelegationPolicy(entry k2common.DelegationPolicy) (delegationPolicy, error) {
+
+	policy := delegationPolicy{DelegationPolicy: entry}
+
+	var configured bool
+	for _, wallet := range k2.validatorWallets() {
+		if wallet.Address == entry.RepresentativeAddress {
+			configured = true
+			break
+		}
+	}
+	if !configured {
+		return policy, fmt.Errorf("invalid delegation policy for representative %s, representative is not a configured wallet", entry.RepresentativeAddress.String())
+	}
+
+	if (entry.WindowStart == "") != (entry.WindowEnd == "") {
+		return policy, fmt.Errorf("invalid delegation policy for representative %s, must specify both a window start and end", entry.RepresentativeAddress.String())
+	}
+	if entry.WindowStart != "" {
+		var err error
+		policy.start, err = parseTimeOfDay(entry.WindowStart)
+		if err != nil {
+			return policy, fmt.Errorf("invalid delegation policy for representative %s, window start: %w", entry.RepresentativeAddress.String(), err)
+		}
+		policy.end, err = parseTimeOfDay(entry.WindowEnd)
+		if err != nil {
+			return policy, fmt.Errorf("invalid delegation policy for representative %s, window end: %w", entry.RepresentativeAddress.String(), err)
+		}
+		if policy.start == policy.end {
+			return policy, fmt.Errorf("invalid delegation policy for representative %s, window start and end cannot be the same", entry.RepresentativeAddress.String())
+		}
+		policy.windowed = true
+	}
+
+	if entry.WindowDays != "" {
+		policy.days = make(map[time.Weekday]bool)
+		for _, day := range strings.Split(entry.WindowDays, ",") {
+			weekday, ok := weekdays[strings.ToLower(strings.TrimSpace(day))]
+			if !ok {
+				return policy, fmt.Errorf("invalid delegation policy for representative %s, unknown window day %q, must be one of sun, mon, tue, wed, thu, fri, sat", entry.RepresentativeAddress.String(), day)
+			}
+			policy.days[weekday] = true
+		}
+		policy.windowed = true
+		if entry.WindowStart == "" {
+			// the whole day
+			policy.start, policy.end = 0, 24*time.Hour
+		}
+	}
+
+	return policy, nil
+}
+
+func (k2 *K2Service) readDelegationPolicy(source string) error {
+	return k2.readListSource("delegation policy", source, k2.loadDelegationPolicy)
+}
+
+// loadDelegationPolicy decodes and applies the delegation policies read from the policy file or url
+func (k2 *K2Service) loadDelegationPolicy(source string, content []byte) error {
+
+	var entries []k2common.DelegationPolicy
+	err := k2common.DecodeList(source, content, &entries)
+	if err != nil {
+		return fmt.Errorf("failed to parse delegation policy: %w", err)
+	}
+
+	policies := make(map[string]delegationPolicy)
+	for _, entry := range entries {
+		policy, err := k2.parseDelegationPolicy(entry)
+		if err != nil {
+			return err
+		}
+		key := strings.ToLower(entry.RepresentativeAddress.String())
+		if _, ok := policies[key]; ok {
+			return fmt.Errorf("invalid delegation policy for representative %s, cannot specify more than one policy per representative", entry.RepresentativeAddress.String())
+		}
+		policies[key] = policy
+	}
+
+	k2.lock.Lock()
+	defer k2.lock.Unlock()
+
+	k2.delegationPolicies = policies
+
+	k2.log.Infof("Delegation policy updated with policies for %d representatives", len(policies))
+
+	return nil
+}
+
+func (k2 *K2Service) clearDelegationPolicy() error {
+	k2.lock.Lock()
+	defer k2.lock.Unlock()
+	k2.delegationPolicies = make(map[string]delegationPolicy)
+	return nil
+}
+
+// currentEpoch returns the current beacon chain epoch from the head events, or from the beacon node sync status
+// if no head event has been seen yet
+func (k2 *K2Service) currentEpoch() (uint64, error) {
+	slot := k2.beacon.CurrentSlot()
+	if slot == 0 {
+		status, err := k2.beacon.Status()
+		if err != nil {
+			return 0, err
+		}
+		slot = status.HeadSlot
+	}
+	return slot / slotsPerEpoch, nil
+}
+
+// delegationAllowance returns how many new native delegations the policy of the representative allows now,
+// or the reason none are allowed. Representatives without a policy are not limited.
+// k2.lock must be held by the caller
+func (k2 *K2Service) delegationAllowance(representative ethcommon.Address, now time.Time) (allowance uint64, limited bool, reason string) {
+
+	policy, ok := k2.delegationPolicies[strings.ToLower(representative.String())]
+	if !ok {
+		return 0, false, ""
+	}
+
+	if !policy.windowOpen(now) {
+		return 0, true, "outside the delegation window of the representative"
+	}
+
+	if policy.MaxDelegationsPerDay == 0 && policy.MaxDelegationsPerEpoch == 0 {
+		return 0, false, ""
+	}
+
+	usage := k2.delegationUsage[representative]
+	if usage == nil {
+		usage = &delegationUsage{}
+		k2.delegationUsage[representative] = usage
+	}
+
+	allowance = ^uint64(0)
+	if policy.MaxDelegationsPerDay > 0 {
+		if day := now.UTC().Format(time.DateOnly); usage.day != day {
+			usage.day, usage.dayCount = day, 0
+		}
+		if usage.dayCount >= policy.MaxDelegationsPerDay {
+			return 0, true, fmt.Sprintf("daily delegation quota of %d reached", policy.MaxDelegationsPerDay)
+		}
+		allowance = policy.MaxDelegationsPerDay - usage.dayCount
+	}
+	if policy.MaxDelegationsPerEpoch > 0 {
+		epoch, err := k2.currentEpoch()
+		if err != nil {
+			k2.log.WithError(err).Warn("Failed to get the current epoch for the delegation epoch quota")
+			return 0, true, "current epoch unknown for the epoch delegation quota"
+		}
+		if usage.epoch != epoch {
+			usage.epoch, usage.epochCount = epoch, 0
+		}
+		if usage.epochCount >= policy.MaxDelegationsPerEpoch {
+			return 0, true, fmt.Sprintf("epoch delegation quota of %d reached", policy.MaxDelegationsPerEpoch)
+		}
+		if remaining := policy.MaxDelegationsPerEpoch - usage.epochCount; remaining < allowance {
+			allowance = remaining
+		}
+	}
+
+	return allowance, true, ""
+}
+
+// applyDelegationPolicy splits the native delegations of a representative into those its policy allows now
+// and those to defer, giving precedence to the validators deferred the longest.
+// k2.lock must be held by the caller
+func (k2 *K2Service) applyDelegationPolicy(representative ethcommon.Address, registrations []k2common.K2ValidatorRegistration) (allowed []k2common.K2ValidatorRegistration, deferred []k2common.K2ValidatorRegistration, reason string) {
+
+	allowance, limited, reason := k2.delegationAllowance(representative, time.Now())
+	if !limited || (reason == "" && uint64(len(registrations)) <= allowance) {
+		return registrations, nil, ""
+	}
+
+	k2.statusLock.RLock()
+	deferredAt := make(map[string]time.Time, len(registrations))
+	for _, registration := range registrations {
+		validator := strings.ToLower(registration.SignedValidatorRegistration.Message.Pubkey.String())
+		if queued, ok := k2.deferredDelegations[validator]; ok {
+			deferredAt[validator] = queued.DeferredAt
+		}
+	}
+	k2.statusLock.RUnlock()
+
+	ordered := append([]k2common.K2ValidatorRegistration{}, registrations...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a := strings.ToLower(ordered[i].SignedValidatorRegistration.Message.Pubkey.String())
+		b := strings.ToLower(ordered[j].SignedValidatorRegistration.Message.Pubkey.String())
+		aDeferred, aQueued := deferredAt[a]
+		bDeferred, bQueued := deferredAt[b]
+		if aQueued != bQueued {
+			return aQueued
+		}
+		if !aDeferred.Equal(bDeferred) {
+			return aDeferred.Before(bDeferred)
+		}
+		return a < b
+	})
+
+	if reason != "" {
+		return nil, ordered, reason
+	}
+	return ordered[:allowance], ordered[allowance:], "delegation quota of the representative reached"
+}
+
+// recordDelegations counts new native delegations of a representative against the quotas of its policy, and records
+// the day and epoch they were counted in to the audit log to restore the usage of the quotas on restart.
+// k2.lock must be held by the caller
+func (k2 *K2Service) recordDelegations(representative ethcommon.Address, validators []string) {
+	usage, ok := k2.delegationUsage[representative]
+	if !ok {
+		return
+	}
+	usage.dayCount += uint64(len(validators))
+	usage.epochCount += uint64(len(validators))
+
+	k2.auditValidators(validators, representative, audit.DecisionDelegationPolicy, audit.OutcomeAllowed, "counted against the delegation quotas", map[string]any{
+		"day":   usage.day,
+		"epoch": usage.epoch,
+	})
+}
+
+// restoreDelegationUsage rebuilds the usage of the delegation quotas in the current day and the last epoch
+// delegations were made in from the audit log, so that a restart does not reset the quotas
+func (k2 *K2Service) restoreDelegationUsage(now time.Time) error {
+
+	if !k2.auditLog.Enabled() {
+		return nil
+	}
+
+	today := now.UTC().Format(time.DateOnly)
+	midnight, _ := time.Parse(time.DateOnly, today)
+	entries, err := k2.auditLog.Query(audit.Filter{
+		// an epoch started before midnight can still be the current epoch
+		From: midnight.Add(-time.Duration(12*slotsPerEpoch) * time.Second),
+	})
+	if err != nil {
+		return err
+	}
+
+	usages := make(map[ethcommon.Address]*delegationUsage)
+	for _, entry := range entries {
+		if entry.Decision != audit.DecisionDelegationPolicy || entry.Outcome != audit.OutcomeAllowed || !ethcommon.IsHexAddress(entry.RepresentativeAddress) {
+			continue
+		}
+		day, _ := entry.Data["day"].(string)
+		epoch, _ := entry.Data["epoch"].(float64)
+
+		representative := ethcommon.HexToAddress(entry.RepresentativeAddress)
+		usage := usages[representative]
+		if usage == nil {
+			usage = &delegationUsage{day: today}
+			usages[representative] = usage
+		}
+		if day == today {
+			usage.dayCount++
+		}
+		if uint64(epoch) > usage.epoch {
+			usage.epoch, usage.epochCount = uint64(epoch), 0
+		}
+		if uint64(epoch) == usage.epoch {
+			usage.epochCount++
+		}
+	}
+
+	k2.lock.Lock()
+	defer k2.lock.Unlock()
+
+	for representative, usage := range usages {
+		k2.delegationUsage[representative] = usage
+	}
+
+	k2.log.Infof("Delegation quota usage restored from the audit log for %d representatives", len(usages))
+
+	return nil
+}
+
+// updateDeferredDelegations replaces the queued native delegations of the processed validators with those deferred,
+// so that validators delegated, excluded or no longer eligible leave the queue
+func (k2 *K2Service) updateDeferredDelegations(processed []phase0.BLSPubKey, representative ethcommon.Address, deferred []k2common.K2ValidatorRegistration, reason string) {
+
+	k2.statusLock.Lock()
+	defer k2.statusLock.Unlock()
+
+	previous := make(map[string]k2common.DeferredDelegation)
+	for _, blsKey := range processed {
+		validator := strings.ToLower(blsKey.String())
+		if queued, ok := k2.deferredDelegations[validator]; ok {
+			previous[validator] = queued
+			delete(k2.deferredDelegations, validator)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, registration := range deferred {
+		validator := strings.ToLower(registration.SignedValidatorRegistration.Message.Pubkey.String())
+		queued, ok := previous[validator]
+		if !ok {
+			queued = k2common.DeferredDelegation{
+				ValidatorPubKey: registration.SignedValidatorRegistration.Message.Pubkey.String(),
+				DeferredAt:      now,
+			}
+		}
+		queued.RepresentativeAddress = representative
+		queued.Reason = reason
+		queued.Attempts++
+		k2.deferredDelegations[validator] = queued
+	}
+}
+
+// deferDelegations records and notifies the native delegations deferred by the policy of the representative
+func (k2 *K2Service) deferDelegations(representative ethcommon.Address, deferred []k2common.K2ValidatorRegistration, reason string) {
+
+	validators := registrationPubKeys(deferred)
+
+	k2.log.WithFields(logrus.Fields{
+		"representative": representative.String(),
+		"deferred":       len(deferred),
+		"reason":         reason,
+	}).Info("Deferring native delegations by the delegation policy of the representative")
+
+	k2.auditValidators(validators, representative, audit.DecisionDelegationPolicy, audit.OutcomeDeferred, reason, nil)
+	k2.notify(notifier.EventDelegationDeferred, []ethcommon.Address{representative}, map[string]any{
+		"validators": validators,
+		"reason":     reason,
+	})
+}
+
+// getDeferredDelegations returns the queued native delegations, longest deferred first
+func (k2 *K2Service) getDeferredDelegations() []k2common.DeferredDelegation {
+
+	k2.statusLock.RLock()
+	defer k2.statusLock.RUnlock()
+
+	deferred := make([]k2common.DeferredDelegation, 0, len(k2.deferredDelegations))
+	for _, queued := range k2.deferredDelegations {
+		deferred = append(deferred, queued)
+	}
+	sort.Slice(deferred, func(i, j int) bool {
+		if !deferred[i].DeferredAt.Equal(deferred[j].DeferredAt) {
+			return deferred[i].DeferredAt.Before(deferred[j].DeferredAt)
+		}
+		return deferred[i].ValidatorPubKey < deferred[j].ValidatorPubKey
+	})
+
+	return deferred
+}
+
+// processDeferredDelegations retries the queued native delegations every epoch once the policy of their
+// representative allows delegations again, using the last registration message seen for each validator
+func (k2 *K2Service) processDeferredDelegations() {
+
+	ticker := time.NewTicker(time.Duration(12*slotsPerEpoch) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-k2.exit:
+			return
+		case <-ticker.C:
+		}
+
+		queued := k2.getDeferredDelegations()
+		if len(queued) == 0 {
+			continue
+		}
+
+		now := time.Now()
+		ready := make(map[ethcommon.Address]bool)
+		k2.lock.Lock()
+		for _, delegation := range queued {
+			if _, ok := ready[delegation.RepresentativeAddress]; !ok {
+				allowance, limited, _ := k2.delegationAllowance(delegation.RepresentativeAddress, now)
+				ready[delegation.RepresentativeAddress] = !limited || allowance > 0
+			}
+		}
+		k2.lock.Unlock()
+
+		var payload []apiv1.SignedValidatorRegistration
+		k2.statusLock.RLock()
+		for _, delegation := range queued {
+			if !ready[delegation.RepresentativeAddress] {
+				continue
+			}
+			if registration, ok := k2.recentRegistrations[strings.ToLower(delegation.ValidatorPubKey)]; ok {
+				payload = append(payload, registration)
+			}
+		}
+		k2.statusLock.RUnlock()
+
+		if len(payload) == 0 {
+			continue
+		}
+
+		k2.log.WithField("validators", len(payload)).Info("Retrying deferred native delegations")
+		if _, err := k2.batchProcessValidatorRegistrations(payload); err != nil {
+			k2.log.WithError(err).Error("Failed to process deferred native delegations")
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package k2
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	auditconfig "github.com/restaking-cloud/native-delegation-for-plus/audit/config"
+	beaconconfig "github.com/restaking-cloud/native-delegation-for-plus/beacon/config"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/internal/testserver"
+)
+
+var testRepresentative = ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+
+func newTestDelegationPolicyService(t *testing.T, policies ...k2common.DelegationPolicy) *K2Service {
+	t.Helper()
+
+	k2 := NewK2Service()
+	k2.cfg.ValidatorWallets = []k2common.ValidatorWallet{{Address: testRepresentative}}
+	for _, entry := range policies {
+		policy, err := k2.parseDelegationPolicy(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k2.delegationPolicies[strings.ToLower(entry.RepresentativeAddress.String())] = policy
+	}
+	return k2
+}
+
+// configureTestBeacon connects the beacon service to a node reporting the head slot
+func configureTestBeacon(t *testing.T, k2 *K2Service, headSlot uint64) {
+	t.Helper()
+
+	node := testserver.NewBeacon(t)
+	node.Set(func(n *testserver.Beacon) { n.HeadSlot = headSlot })
+	if err := k2.beacon.Configure(beaconconfig.BeaconConfig{BeaconNodeUrl: node.URL}, logrus.NewEntry(logrus.New())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseDelegationPolicy(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		entry   k2common.DelegationPolicy
+		wantErr string
+	}{
+		{
+			name:  "quotas only",
+			entry: k2common.DelegationPolicy{RepresentativeAddress: testRepresentative, MaxDelegationsPerDay: 10},
+		},
+		{
+			name:  "window",
+			entry: k2common.DelegationPolicy{RepresentativeAddress: testRepresentative, WindowDays: "Mon, tue", WindowStart: "22:00", WindowEnd: "02:00"},
+		},
+		{
+			name:    "representative not configured",
+			entry:   k2common.DelegationPolicy{RepresentativeAddress: ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")},
+			wantErr: "representative is not a configured wallet",
+		},
+		{
+			name:    "window start without end",
+			entry:   k2common.DelegationPolicy{RepresentativeAddress: testRepresentative, WindowStart: "09:00"},
+			wantErr: "must specify both a window start and end",
+		},
+		{
+			name:    "invalid window start",
+			entry:   k2common.DelegationPolicy{RepresentativeAddress: testRepresentative, WindowStart: "9am", WindowEnd: "17:00"},
+			wantErr: `window start: invalid time of day "9am"`,
+		},
+		{
+			name:    "invalid window end",
+			entry:   k2common.DelegationPolicy{RepresentativeAddress: testRepresentative, WindowStart: "09:00", WindowEnd: "24:00"},
+			wantErr: `window end: invalid time of day "24:00"`,
+		},
+		{
+			name:    "empty window",
+			entry:   k2common.DelegationPolicy{RepresentativeAddress: testRepresentative, WindowStart: "09:00", WindowEnd: "09:00"},
+			wantErr: "window start and end cannot be the same",
+		},
+		{
+			name:    "unknown window day",
+			entry:   k2common.DelegationPolicy{RepresentativeAddress: testRepresentative, WindowDays: "mon,funday"},
+			wantErr: `unknown window day "funday"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestDelegationPolicyService(t).parseDelegationPolicy(tt.entry)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("parseDelegationPolicy() error = %v", err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("parseDelegationPolicy() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDelegationPolicy_WindowOpen(t *testing.T) {
+
+	// 2026-10-19 is a Monday
+	at := func(day int, clock string) time.Time {
+		parsed, err := time.Parse(time.DateTime, fmt.Sprintf("2026-10-%02d %s:00", day, clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name  string
+		days  string
+		start string
+		end   string
+		now   time.Time
+		want  bool
+	}{
+		{name: "no window", now: at(19, "03:00"), want: true},
+		{name: "before the window", start: "09:00", end: "17:00", now: at(19, "08:59"), want: false},
+		{name: "at the window start", start: "09:00", end: "17:00", now: at(19, "09:00"), want: true},
+		{name: "at the window end", start: "09:00", end: "17:00", now: at(19, "17:00"), want: false},
+		{name: "window in another time zone", start: "09:00", end: "17:00", now: at(19, "12:00").In(time.FixedZone("UTC-10", -10*3600)), want: true},
+		{name: "listed day", days: "mon,wed", now: at(19, "00:00"), want: true},
+		{name: "unlisted day", days: "tue,wed", now: at(19, "12:00"), want: false},
+		{name: "listed day outside the window", days: "mon", start: "09:00", end: "17:00", now: at(19, "18:00"), want: false},
+		{name: "spanning midnight before midnight", start: "22:00", end: "02:00", now: at(19, "23:00"), want: true},
+		{name: "spanning midnight after midnight", start: "22:00", end: "02:00", now: at(20, "01:00"), want: true},
+		{name: "spanning midnight outside", start: "22:00", end: "02:00", now: at(19, "12:00"), want: false},
+		{name: "spanning midnight from a listed day", days: "mon", start: "22:00", end: "02:00", now: at(20, "01:00"), want: true},
+		{name: "spanning midnight into a listed day", days: "tue", start: "22:00", end: "02:00", now: at(20, "01:00"), want: false},
+		{name: "spanning midnight on a listed day", days: "tue", start: "22:00", end: "02:00", now: at(20, "23:00"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := newTestDelegationPolicyService(t).parseDelegationPolicy(k2common.DelegationPolicy{
+				RepresentativeAddress: testRepresentative,
+				WindowDays:            tt.days,
+				WindowStart:           tt.start,
+				WindowEnd:             tt.end,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := policy.windowOpen(tt.now); got != tt.want {
+				t.Errorf("windowOpen(%s) = %v, want %v", tt.now.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelegationAllowance(t *testing.T) {
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	today := now.Format(time.DateOnly)
+	const headEpoch = 100
+
+	tests := []struct {
+		name          string
+		policy        *k2common.DelegationPolicy // no policy if nil
+		usage         *delegationUsage
+		wantAllowance uint64
+		wantLimited   bool
+		wantReason    string
+	}{
+		{
+			name:        "no policy",
+			wantLimited: false,
+		},
+		{
+			name:        "window only",
+			policy:      &k2common.DelegationPolicy{WindowStart: "09:00", WindowEnd: "17:00"},
+			wantLimited: false,
+		},
+		{
+			name:        "outside the window",
+			policy:      &k2common.DelegationPolicy{MaxDelegationsPerDay: 5, WindowStart: "13:00", WindowEnd: "17:00"},
+			wantLimited: true,
+			wantReason:  "outside the delegation window of the representative",
+		},
+		{
+			name:          "daily quota unused",
+			policy:        &k2common.DelegationPolicy{MaxDelegationsPerDay: 5},
+			wantAllowance: 5,
+			wantLimited:   true,
+		},
+		{
+			name:          "daily quota partly used",
+			policy:        &k2common.DelegationPolicy{MaxDelegationsPerDay: 5},
+			usage:         &delegationUsage{day: today, dayCount: 3},
+			wantAllowance: 2,
+			wantLimited:   true,
+		},
+		{
+			name:        "daily quota reached",
+			policy:      &k2common.DelegationPolicy{MaxDelegationsPerDay: 5},
+			usage:       &delegationUsage{day: today, dayCount: 5},
+			wantLimited: true,
+			wantReason:  "daily delegation quota of 5 reached",
+		},
+		{
+			name:          "daily quota reset on a new day",
+			policy:        &k2common.DelegationPolicy{MaxDelegationsPerDay: 5},
+			usage:         &delegationUsage{day: "2026-10-18", dayCount: 5},
+			wantAllowance: 5,
+			wantLimited:   true,
+		},
+		{
+			name:          "epoch quota partly used",
+			policy:        &k2common.DelegationPolicy{MaxDelegationsPerEpoch: 4},
+			usage:         &delegationUsage{epoch: headEpoch, epochCount: 1},
+			wantAllowance: 3,
+			wantLimited:   true,
+		},
+		{
+			name:        "epoch quota reached",
+			policy:      &k2common.DelegationPolicy{MaxDelegationsPerEpoch: 4},
+			usage:       &delegationUsage{epoch: headEpoch, epochCount: 4},
+			wantLimited: true,
+			wantReason:  "epoch delegation quota of 4 reached",
+		},
+		{
+			name:          "epoch quota reset on a new epoch",
+			policy:        &k2common.DelegationPolicy{MaxDelegationsPerEpoch: 4},
+			usage:         &delegationUsage{epoch: headEpoch - 1, epochCount: 4},
+			wantAllowance: 4,
+			wantLimited:   true,
+		},
+		{
+			name:          "lowest remaining quota",
+			policy:        &k2common.DelegationPolicy{MaxDelegationsPerDay: 10, MaxDelegationsPerEpoch: 4},
+			usage:         &delegationUsage{day: today, dayCount: 8, epoch: headEpoch},
+			wantAllowance: 2,
+			wantLimited:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var k2 *K2Service
+			if tt.policy != nil {
+				policy := *tt.policy
+				policy.RepresentativeAddress = testRepresentative
+				k2 = newTestDelegationPolicyService(t, policy)
+			} else {
+				k2 = newTestDelegationPolicyService(t)
+			}
+			if tt.usage != nil {
+				k2.delegationUsage[testRepresentative] = tt.usage
+			}
+			configureTestBeacon(t, k2, headEpoch*slotsPerEpoch+5)
+
+			allowance, limited, reason := k2.delegationAllowance(testRepresentative, now)
+			if allowance != tt.wantAllowance || limited != tt.wantLimited || reason != tt.wantReason {
+				t.Errorf("delegationAllowance() = (%d, %v, %q), want (%d, %v, %q)", allowance, limited, reason, tt.wantAllowance, tt.wantLimited, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestApplyDelegationPolicy(t *testing.T) {
+
+	registration := func(key byte) k2common.K2ValidatorRegistration {
+		var registration k2common.K2ValidatorRegistration
+		registration.SignedValidatorRegistration = &apiv1.SignedValidatorRegistration{
+			Message: &apiv1.ValidatorRegistration{Pubkey: phase0.BLSPubKey{key}},
+		}
+		return registration
+	}
+	registrations := []k2common.K2ValidatorRegistration{registration(0x01), registration(0x02), registration(0x03)}
+
+	k2 := newTestDelegationPolicyService(t, k2common.DelegationPolicy{RepresentativeAddress: testRepresentative, MaxDelegationsPerDay: 2})
+
+	// the last validator was deferred before, so is delegated first
+	queued := strings.ToLower(registrations[2].SignedValidatorRegistration.Message.Pubkey.String())
+	k2.deferredDelegations[queued] = k2common.DeferredDelegation{ValidatorPubKey: queued, DeferredAt: time.Now().Add(-time.Hour)}
+
+	allowed, deferred, reason := k2.applyDelegationPolicy(testRepresentative, registrations)
+	if len(allowed) != 2 || allowed[0].SignedValidatorRegistration.Message.Pubkey != registrations[2].SignedValidatorRegistration.Message.Pubkey ||
+		allowed[1].SignedValidatorRegistration.Message.Pubkey != registrations[0].SignedValidatorRegistration.Message.Pubkey {
+		t.Errorf("allowed = %v, want the deferred validator then the first", registrationPubKeys(allowed))
+	}
+	if len(deferred) != 1 || deferred[0].SignedValidatorRegistration.Message.Pubkey != registrations[1].SignedValidatorRegistration.Message.Pubkey {
+		t.Errorf("deferred = %v, want the second validator", registrationPubKeys(deferred))
+	}
+	if reason != "delegation quota of the representative reached" {
+		t.Errorf("reason = %q", reason)
+	}
+
+	k2.recordDelegations(testRepresentative, registrationPubKeys(allowed))
+	if allowed, deferred, reason := k2.applyDelegationPolicy(testRepresentative, deferred); len(allowed) != 0 || len(deferred) != 1 || reason != "daily delegation quota of 2 reached" {
+		t.Errorf("after the quota is used applyDelegationPolicy() = (%d allowed, %d deferred, %q)", len(allowed), len(deferred), reason)
+	}
+}
+
+func TestRestoreDelegationUsage(t *testing.T) {
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	midnight := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	other := ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	k2 := newTestDelegationPolicyService(t)
+	if err := k2.auditLog.Configure(auditconfig.AuditConfig{File: filepath.Join(t.TempDir(), "audit.jsonl")}, k2.log); err != nil {
+		t.Fatal(err)
+	}
+	defer k2.auditLog.Close()
+
+	counted := func(at time.Time, representative ethcommon.Address, day string, epoch uint64) audit.Entry {
+		return audit.Entry{
+			Timestamp:             at,
+			RepresentativeAddress: representative.String(),
+			Decision:              audit.DecisionDelegationPolicy,
+			Outcome:               audit.OutcomeAllowed,
+			Data:                  map[string]any{"day": day, "epoch": epoch},
+		}
+	}
+	k2.auditLog.Record(
+		// counted the day before, too long ago to be in the current epoch
+		counted(midnight.Add(-time.Hour), testRepresentative, "2026-10-18", 90),
+		// counted the day before in an epoch that can still be current
+		counted(midnight.Add(-time.Minute), other, "2026-10-18", 99),
+		counted(midnight.Add(time.Hour), testRepresentative, "2026-10-19", 100),
+		counted(midnight.Add(2*time.Hour), testRepresentative, "2026-10-19", 100),
+		counted(midnight.Add(3*time.Hour), testRepresentative, "2026-10-19", 101),
+		audit.Entry{
+			Timestamp:             midnight.Add(4 * time.Hour),
+			RepresentativeAddress: testRepresentative.String(),
+			Decision:              audit.DecisionDelegationPolicy,
+			Outcome:               audit.OutcomeDeferred,
+		},
+	)
+
+	if err := k2.restoreDelegationUsage(now); err != nil {
+		t.Fatalf("restoreDelegationUsage() error = %v", err)
+	}
+
+	tests := []struct {
+		representative ethcommon.Address
+		want           delegationUsage
+	}{
+		{representative: testRepresentative, want: delegationUsage{day: "2026-10-19", dayCount: 3, epoch: 101, epochCount: 1}},
+		{representative: other, want: delegationUsage{day: "2026-10-19", dayCount: 0, epoch: 99, epochCount: 1}},
+	}
+	for _, tt := range tests {
+		usage := k2.delegationUsage[tt.representative]
+		if usage == nil || *usage != tt.want {
+			t.Errorf("usage of %s = %+v, want %+v", tt.representative.String(), usage, tt.want)
+		}
+	}
+	if len(k2.delegationUsage) != len(tests) {
+		t.Errorf("usage restored for %d representatives, want %d", len(k2.delegationUsage), len(tests))
+	}
+}
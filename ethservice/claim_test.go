@@ -0,0 +1,78 @@
+package ethservice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/ethservice/contracts"
+)
+
+func TestEthService_packDelegatedClaim(t *testing.T) {
+
+	lendingABI, err := abi.JSON(strings.NewReader(contracts.K2_LENDING_CONTRACT_ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEthService()
+	e.cfg.K2LendingContractABI = &lendingABI
+
+	representative := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	recipient := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	tests := []struct {
+		name           string
+		claimRecipient *common.Address
+		wantErr        bool
+	}{
+		{
+			name:           "paid out to the delegated recipient",
+			claimRecipient: &recipient,
+		},
+		{
+			name:    "no delegated recipient",
+			wantErr: true,
+		},
+		{
+			name:           "zero delegated recipient",
+			claimRecipient: &common.Address{},
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := e.packDelegatedClaim(k2common.K2Claim{
+				RepresentativeAddress: representative,
+				ClaimRecipient:        tt.claimRecipient,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("packDelegatedClaim() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			method, err := lendingABI.MethodById(data[:4])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if method.Name != "nodeOperatorClaim" {
+				t.Fatalf("packed %s, want nodeOperatorClaim", method.Name)
+			}
+			args := map[string]any{}
+			if err := method.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+				t.Fatal(err)
+			}
+			if got := args["_recipientOverride"]; got != recipient {
+				t.Errorf("_recipientOverride = %v, want %v", got, recipient)
+			}
+			if got := args["_nodeOperator"]; got != representative {
+				t.Errorf("_nodeOperator = %v, want %v", got, representative)
+			}
+		})
+	}
+}
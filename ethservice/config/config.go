@@ -12,6 +12,7 @@ import (
 
 type EthServiceConfig struct {
 	ExecutionNodeUrl *url.URL
+	FallbackExecutionNodeUrls []*url.URL // failed over to in order of preference when the execution node is unhealthy
 	ChainID          *big.Int
 
 	MaxGasPrice *big.Int
@@ -0,0 +1,134 @@
+package ethservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/sirupsen/logrus"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/ethservice/contracts"
+	"github.com/restaking-cloud/native-delegation-for-plus/internal/testserver"
+)
+
+// testContractCall answers a contract call with the outputs of the method for its arguments
+type testContractCall func(args []any) ([]any, error)
+
+// newTestContractService returns a service connected to an execution node answering eth_call for the module contracts
+// with the calls by method name, answering each call of a multicall aggregate3 the same way and failing those allowed to
+// fail if the call returns an error
+func newTestContractService(t *testing.T, calls map[string]testContractCall) *EthService {
+	t.Helper()
+
+	e := NewEthService()
+	e.log = logrus.NewEntry(logrus.New())
+	e.cfg.ValidatorWallets = []k2common.ValidatorWallet{{Address: common.HexToAddress("0x1111111111111111111111111111111111111111")}}
+	e.cfg.K2LendingContractAddress = common.HexToAddress("0x000000000000000000000000000000000000a001")
+	e.cfg.K2NodeOperatorContractAddress = common.HexToAddress("0x000000000000000000000000000000000000a002")
+	e.cfg.ProposerRegistryContractAddress = common.HexToAddress("0x000000000000000000000000000000000000a003")
+	e.cfg.MulticallContractAddress = common.HexToAddress("0x000000000000000000000000000000000000a004")
+
+	for _, contract := range []struct {
+		abi  string
+		dest **abi.ABI
+	}{
+		{contracts.K2_LENDING_CONTRACT_ABI, &e.cfg.K2LendingContractABI},
+		{contracts.K2_NODE_OPERATOR_CONTRACT_ABI, &e.cfg.K2NodeOperatorContractABI},
+		{contracts.PROPOSER_REGISTRY_CONTRACT_ABI, &e.cfg.ProposerRegistryContractABI},
+		{contracts.MULTICALL3_CONTRACT_ABI, &e.cfg.MulticallContractABI},
+	} {
+		parsed, err := abi.JSON(strings.NewReader(contract.abi))
+		if err != nil {
+			t.Fatal(err)
+		}
+		*contract.dest = &parsed
+	}
+
+	var answer func(data []byte) ([]byte, error)
+	answer = func(data []byte) ([]byte, error) {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("no method selector")
+		}
+
+		if method, err := e.cfg.MulticallContractABI.MethodById(data[:4]); err == nil && method.Name == "aggregate3" {
+			args, err := method.Inputs.Unpack(data[4:])
+			if err != nil {
+				return nil, err
+			}
+			multicalls := *abi.ConvertType(args[0], new([]contracts.Call3)).(*[]contracts.Call3)
+			results := make([]contracts.Result, len(multicalls))
+			for i, multicall := range multicalls {
+				returnData, err := answer(multicall.CallData)
+				if err != nil && multicall.AllowFailure {
+					results[i] = contracts.Result{Success: false}
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				results[i] = contracts.Result{Success: true, ReturnData: returnData}
+			}
+			return method.Outputs.Pack(results)
+		}
+
+		for _, contractAbi := range []*abi.ABI{e.cfg.K2LendingContractABI, e.cfg.K2NodeOperatorContractABI, e.cfg.ProposerRegistryContractABI} {
+			method, err := contractAbi.MethodById(data[:4])
+			if err != nil {
+				continue
+			}
+			call, ok := calls[method.Name]
+			if !ok {
+				return nil, fmt.Errorf("unexpected call to %s", method.Name)
+			}
+			args, err := method.Inputs.Unpack(data[4:])
+			if err != nil {
+				return nil, err
+			}
+			outputs, err := call(args)
+			if err != nil {
+				return nil, err
+			}
+			return method.Outputs.Pack(outputs...)
+		}
+
+		return nil, fmt.Errorf("unknown method selector %x", data[:4])
+	}
+
+	node := testserver.NewExecution(t)
+	node.Set(func(n *testserver.Execution) {
+		n.Methods = map[string]func([]json.RawMessage) (any, error){
+			"eth_call": func(params []json.RawMessage) (any, error) {
+				var call struct {
+					Data  hexutil.Bytes `json:"data"`
+					Input hexutil.Bytes `json:"input"`
+				}
+				if len(params) == 0 {
+					return nil, fmt.Errorf("no call")
+				}
+				if err := json.Unmarshal(params[0], &call); err != nil {
+					return nil, err
+				}
+				if len(call.Input) == 0 {
+					call.Input = call.Data
+				}
+				result, err := answer(call.Input)
+				if err != nil {
+					return nil, err
+				}
+				return hexutil.Bytes(result), nil
+			},
+		}
+	})
+
+	if err := e.connect([]*url.URL{node.URL}); err != nil {
+		t.Fatalf("connect() error = %v", err)
+	}
+
+	return e
+}
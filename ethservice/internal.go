@@ -3,41 +3,16 @@ package ethservice
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"reflect"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/restaking-cloud/native-delegation-for-plus/ethservice/contracts"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
 )
 
-func (e *EthService) connect(url *url.URL) error {
-	client, err := ethclient.Dial(url.String())
-	if err != nil {
-		return err
-	}
-	e.client = client
-	e.cfg.ExecutionNodeUrl = url
-
-	id, err := client.NetworkID(context.Background())
-	if err != nil {
-		return err
-	}
-	e.cfg.ChainID = id
-
-	synced, err := client.SyncProgress(context.Background())
-	if err != nil {
-		return err
-	}
-	if synced != nil && synced.CurrentBlock != synced.HighestBlock {
-		return fmt.Errorf("execution node not synced")
-	}
-
-	return nil
-}
-
 func (e *EthService) configureK2LendingContract(address common.Address) error {
 	e.cfg.K2LendingContractAddress = address
 
@@ -48,7 +23,7 @@ func (e *EthService) configureK2LendingContract(address common.Address) error {
 	e.cfg.K2LendingContractABI = &contractAbi
 
 	// check if the contract is deployed
-	contractByteCode, err := e.client.CodeAt(context.Background(), address, nil)
+	contractByteCode, err := e.client().CodeAt(context.Background(), address, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get k2 lending contract code: %w", err)
 	}
@@ -70,7 +45,7 @@ func (e *EthService) configureK2NodeOperatorContract(address common.Address) err
 	e.cfg.K2NodeOperatorContractABI = &contractAbi
 
 	// check if the contract is deployed
-	contractByteCode, err := e.client.CodeAt(context.Background(), address, nil)
+	contractByteCode, err := e.client().CodeAt(context.Background(), address, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get k2 node operator contract code: %w", err)
 	}
@@ -91,7 +66,7 @@ func (e *EthService) configureProposerRegistryContract(address common.Address) e
 	}
 	e.cfg.ProposerRegistryContractABI = &contractAbi
 
-	contractByteCode, err := e.client.CodeAt(context.Background(), address, nil)
+	contractByteCode, err := e.client().CodeAt(context.Background(), address, nil)
 	if err != nil {
 		return err
 	}
@@ -113,7 +88,7 @@ func (e *EthService) configureMulticallContract(address common.Address) error {
 	e.cfg.MulticallContractABI = &contractAbi
 
 	// check if the contract is deployed
-	contractByteCode, err := e.client.CodeAt(context.Background(), address, nil)
+	contractByteCode, err := e.client().CodeAt(context.Background(), address, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get multicall contract code: %w", err)
 	}
@@ -124,3 +99,71 @@ func (e *EthService) configureMulticallContract(address common.Address) error {
 
 	return nil
 }
+
+// transactionOperation resolves the module operation a transaction performs
+// from its method selector, for labelling transaction metrics
+func (e *EthService) transactionOperation(data []byte) string {
+	if len(data) < 4 {
+		return "unknown"
+	}
+
+	for _, contractAbi := range []*abi.ABI{e.cfg.ProposerRegistryContractABI, e.cfg.K2LendingContractABI, e.cfg.K2NodeOperatorContractABI} {
+		if contractAbi == nil {
+			continue
+		}
+		method, err := contractAbi.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		switch method.Name {
+		case "batchRegisterProposerWithoutPayoutPoolRegistration":
+			return metrics.OperationProposerRegistry
+		case "batchNodeOperatorDeposit":
+			return metrics.OperationNativeDelegation
+		case "nodeOperatorClaim":
+			return metrics.OperationClaim
+		case "nodeOperatorWithdraw":
+			return metrics.OperationExit
+		case "nodeOperatorFeeRecipientUpdate":
+			return metrics.OperationPayoutUpdate
+		case "setDelegatedRecipient":
+			return metrics.OperationClaimRecipientUpdate
+		case "claimKETH":
+			return metrics.OperationKETHClaim
+		case "transfer":
+			return metrics.OperationKETHTransfer
+		default:
+			return method.Name
+		}
+	}
+
+	return "unknown"
+}
+
+// transactionBatchSize returns the number of validators a transaction carries from the length of the first
+// array argument of its call data, or 1 if the call has none
+func (e *EthService) transactionBatchSize(data []byte) uint64 {
+	if len(data) < 4 {
+		return 1
+	}
+
+	for _, contractAbi := range []*abi.ABI{e.cfg.ProposerRegistryContractABI, e.cfg.K2LendingContractABI, e.cfg.K2NodeOperatorContractABI} {
+		if contractAbi == nil {
+			continue
+		}
+		method, err := contractAbi.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil || len(args) == 0 {
+			return 1
+		}
+		if arg := reflect.ValueOf(args[0]); arg.Kind() == reflect.Slice && arg.Len() > 0 {
+			return uint64(arg.Len())
+		}
+		return 1
+	}
+
+	return 1
+}
@@ -0,0 +1,83 @@
+package ethservice
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+)
+
+func TestEthService_KETHToken(t *testing.T) {
+
+	e := newTestContractService(t, map[string]testContractCall{
+		"symbol":   func([]any) ([]any, error) { return []any{"kETH"}, nil },
+		"decimals": func([]any) ([]any, error) { return []any{uint8(18)}, nil },
+	})
+
+	symbol, decimals, err := e.KETHToken()
+	if err != nil {
+		t.Fatalf("KETHToken() error = %v", err)
+	}
+	if symbol != "kETH" || decimals != 18 {
+		t.Errorf("KETHToken() = %s %d, want kETH 18", symbol, decimals)
+	}
+}
+
+func TestEthService_KETHBalances(t *testing.T) {
+
+	representatives := []common.Address{
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		common.HexToAddress("0x3333333333333333333333333333333333333333"),
+	}
+	claimable := map[common.Address]*big.Int{representatives[0]: big.NewInt(100), representatives[1]: big.NewInt(0)}
+	held := map[common.Address]*big.Int{representatives[0]: big.NewInt(5), representatives[1]: big.NewInt(250)}
+
+	e := newTestContractService(t, map[string]testContractCall{
+		"claimableKETH": func(args []any) ([]any, error) { return []any{claimable[args[0].(common.Address)]}, nil },
+		"balanceOf":     func(args []any) ([]any, error) { return []any{held[args[0].(common.Address)]}, nil },
+	})
+
+	gotClaimable, gotHeld, err := e.KETHBalances(representatives)
+	if err != nil {
+		t.Fatalf("KETHBalances() error = %v", err)
+	}
+	for _, representative := range representatives {
+		if gotClaimable[representative].Cmp(claimable[representative]) != 0 {
+			t.Errorf("claimable KETH of %s = %v, want %v", representative, gotClaimable[representative], claimable[representative])
+		}
+		if gotHeld[representative].Cmp(held[representative]) != 0 {
+			t.Errorf("held KETH of %s = %v, want %v", representative, gotHeld[representative], held[representative])
+		}
+	}
+
+	if gotClaimable, gotHeld, err := e.KETHBalances(nil); err != nil || len(gotClaimable) != 0 || len(gotHeld) != 0 {
+		t.Errorf("KETHBalances(nil) = %v, %v, %v, want no balances", gotClaimable, gotHeld, err)
+	}
+}
+
+func TestEthService_KETHTransactionOperations(t *testing.T) {
+
+	e := newTestContractService(t, nil)
+
+	claim, err := e.cfg.K2LendingContractABI.Pack("claimKETH", common.HexToAddress("0x2222222222222222222222222222222222222222"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer, err := e.cfg.K2LendingContractABI.Pack("transfer", common.HexToAddress("0x4444444444444444444444444444444444444444"), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := e.transactionOperation(claim); got != metrics.OperationKETHClaim {
+		t.Errorf("claimKETH operation = %q, want %q", got, metrics.OperationKETHClaim)
+	}
+	if got := e.transactionOperation(transfer); got != metrics.OperationKETHTransfer {
+		t.Errorf("transfer operation = %q, want %q", got, metrics.OperationKETHTransfer)
+	}
+
+	if _, err := e.TransferKETH(common.HexToAddress("0x2222222222222222222222222222222222222222"), common.Address{}, big.NewInt(1)); err == nil {
+		t.Errorf("TransferKETH() accepted the null address as recipient")
+	}
+}
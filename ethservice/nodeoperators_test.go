@@ -0,0 +1,62 @@
+package ethservice
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEthService_K2NodeOperatorStatuses(t *testing.T) {
+
+	inclusionList := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	operator := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	banned := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	kicked := phase0.BLSPubKey{0xa1}
+	active := phase0.BLSPubKey{0xb2}
+
+	e := newTestContractService(t, map[string]testContractCall{
+		"nodeOperatorInclusionList": func([]any) ([]any, error) { return []any{inclusionList}, nil },
+		"nodeOperatorToLendPosition": func(args []any) ([]any, error) {
+			if args[0].(common.Address) == operator {
+				return []any{big.NewInt(7e9), big.NewInt(3e18)}, nil
+			}
+			return []any{big.NewInt(0), big.NewInt(0)}, nil
+		},
+		"nodeOperatorToBlsPublicKeyCount": func(args []any) ([]any, error) {
+			if args[0].(common.Address) == operator {
+				return []any{big.NewInt(2)}, nil
+			}
+			return []any{big.NewInt(0)}, nil
+		},
+		"isNodeOperatorBanned":  func(args []any) ([]any, error) { return []any{args[0].(common.Address) == banned}, nil },
+		"isPartOfInclusionList": func(args []any) ([]any, error) { return []any{args[0].(common.Address) == operator}, nil },
+		"blsPublicKeyToKicked":  func(args []any) ([]any, error) { return []any{bytes.Equal(args[0].([]byte), kicked[:])}, nil },
+	})
+
+	statuses, err := e.K2NodeOperatorStatuses([]common.Address{operator, banned}, map[common.Address][]phase0.BLSPubKey{
+		operator: {kicked, active},
+	})
+	if err != nil {
+		t.Fatalf("K2NodeOperatorStatuses() error = %v", err)
+	}
+
+	got := statuses[operator]
+	if got.LendPosition.CumulativeKethPerShare.Cmp(big.NewInt(7e9)) != 0 || got.LendPosition.KethEarned.Cmp(big.NewInt(3e18)) != 0 {
+		t.Errorf("lend position = %+v, want 7e9 per share and 3e18 earned", got.LendPosition)
+	}
+	if got.BlsPublicKeyCount != 2 || got.Banned || !got.PartOfInclusionList || got.InclusionList != inclusionList {
+		t.Errorf("status = %+v, want 2 keys, not banned, part of inclusion list %s", got, inclusionList)
+	}
+	if !reflect.DeepEqual(got.DelegatedValidators, []phase0.BLSPubKey{kicked, active}) || !reflect.DeepEqual(got.KickedValidators, []phase0.BLSPubKey{kicked}) {
+		t.Errorf("delegated %v kicked %v, want delegated %v kicked %v", got.DelegatedValidators, got.KickedValidators, []phase0.BLSPubKey{kicked, active}, []phase0.BLSPubKey{kicked})
+	}
+
+	got = statuses[banned]
+	if !got.Banned || got.PartOfInclusionList || got.BlsPublicKeyCount != 0 || len(got.KickedValidators) != 0 {
+		t.Errorf("status = %+v, want banned without keys", got)
+	}
+}
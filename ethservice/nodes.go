@@ -0,0 +1,210 @@
+package ethservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+)
+
+// MaxSyncDistance is the number of blocks an execution node can be behind the head it knows of and still be used
+const MaxSyncDistance = 2
+
+var errChainIdMismatch = errors.New("chain id mismatch")
+
+type executionNode struct {
+	url           *url.URL
+	client        *ethclient.Client
+	health        k2common.EndpointHealth
+	chainMismatch bool
+}
+
+// client returns the client of the execution node in use
+func (e *EthService) client() *ethclient.Client {
+	e.nodeLock.RLock()
+	defer e.nodeLock.RUnlock()
+	return e.activeNode.client
+}
+
+// dial creates the client of the node if it does not have one yet
+func (e *EthService) dial(node *executionNode) error {
+
+	if node.client != nil {
+		return nil
+	}
+
+	rpcClient, err := rpc.DialOptions(context.Background(), node.url.String(), rpc.WithHTTPClient(&http.Client{
+		Transport: metrics.NewTransport(metrics.DependencyExecutionNode),
+	}))
+	if err != nil {
+		return err
+	}
+	node.client = ethclient.NewClient(rpcClient)
+
+	return nil
+}
+
+// checkChainId checks a node reports the configured chain id, setting it from the node if none is configured yet
+func (e *EthService) checkChainId(id *big.Int) error {
+	e.nodeLock.Lock()
+	defer e.nodeLock.Unlock()
+
+	if e.cfg.ChainID == nil {
+		e.cfg.ChainID = id
+	} else if id.Cmp(e.cfg.ChainID) != 0 {
+		return fmt.Errorf("%w: execution node reports %v, expected %v", errChainIdMismatch, id, e.cfg.ChainID)
+	}
+	return nil
+}
+
+// checkNode checks the node is reachable, on the configured chain and synced to within MaxSyncDistance blocks.
+// If no chain is configured yet the node sets it
+func (e *EthService) checkNode(node *executionNode) (health k2common.EndpointHealth, err error) {
+
+	health = node.health
+	health.Healthy = false
+
+	defer func() {
+		if err != nil {
+			health.ConsecutiveFailures++
+			health.LastError = err.Error()
+		} else {
+			health.ConsecutiveFailures = 0
+			health.LastError = ""
+		}
+	}()
+
+	if err := e.dial(node); err != nil {
+		return health, err
+	}
+
+	id, err := node.client.NetworkID(context.Background())
+	if err != nil {
+		return health, err
+	}
+	if err := e.checkChainId(id); err != nil {
+		return health, err
+	}
+	health.Verified = true
+
+	synced, err := node.client.SyncProgress(context.Background())
+	if err != nil {
+		return health, err
+	}
+	health.SyncDistance = 0
+	if synced != nil && synced.HighestBlock > synced.CurrentBlock {
+		health.SyncDistance = synced.HighestBlock - synced.CurrentBlock
+	}
+	if health.SyncDistance > MaxSyncDistance {
+		return health, fmt.Errorf("execution node not synced, %d blocks behind", health.SyncDistance)
+	}
+
+	health.Healthy = true
+	return health, nil
+}
+
+// CheckNodes checks the health of every configured execution node and switches to the first healthy node in order
+// of preference. The node in use is kept if none are healthy
+func (e *EthService) CheckNodes() error {
+
+	e.checkLock.Lock()
+	defer e.checkLock.Unlock()
+
+	checked := make([]k2common.EndpointHealth, len(e.nodes))
+	var selected *executionNode
+	for i, node := range e.nodes {
+		var err error
+		checked[i], err = e.checkNode(node)
+		node.chainMismatch = errors.Is(err, errChainIdMismatch)
+		if selected == nil && checked[i].Healthy {
+			selected = node
+		}
+	}
+
+	e.nodeLock.Lock()
+	defer e.nodeLock.Unlock()
+
+	for i, node := range e.nodes {
+		node.health = checked[i]
+	}
+
+	if selected == nil {
+		if e.activeNode == nil {
+			return fmt.Errorf("no execution node synced and on the expected chain: %s", nodeErrors(e.nodes))
+		}
+		return fmt.Errorf("no execution node healthy, keeping %s: %s", e.activeNode.url.Redacted(), nodeErrors(e.nodes))
+	}
+
+	if selected != e.activeNode {
+		if e.activeNode != nil {
+			e.log.WithFields(logrus.Fields{
+				"from": e.activeNode.url.Redacted(),
+				"to":   selected.url.Redacted(),
+			}).Warn("Execution node failover")
+		}
+		e.activeNode = selected
+		e.cfg.ExecutionNodeUrl = selected.url
+	}
+
+	return nil
+}
+
+// Nodes returns the health of each configured execution node as last checked, in order of preference
+func (e *EthService) Nodes() []k2common.EndpointHealth {
+	e.nodeLock.RLock()
+	defer e.nodeLock.RUnlock()
+
+	nodes := make([]k2common.EndpointHealth, len(e.nodes))
+	for i, node := range e.nodes {
+		nodes[i] = node.health
+		nodes[i].Active = node == e.activeNode
+	}
+	return nodes
+}
+
+func nodeErrors(nodes []*executionNode) string {
+	var errs string
+	for i, node := range nodes {
+		if i > 0 {
+			errs += "; "
+		}
+		errs += node.url.Redacted() + ": " + node.health.LastError
+	}
+	return errs
+}
+
+// connect sets the execution nodes in order of preference and selects the first healthy node. Every node that
+// can be reached must report the same chain id, nodes that cannot be reached are checked again with CheckNodes
+func (e *EthService) connect(urls []*url.URL) error {
+
+	if len(urls) == 0 {
+		return fmt.Errorf("execution node url not set")
+	}
+
+	e.nodes = make([]*executionNode, 0, len(urls))
+	for _, u := range urls {
+		e.nodes = append(e.nodes, &executionNode{
+			url:    u,
+			health: k2common.EndpointHealth{Url: u.Redacted()},
+		})
+	}
+
+	err := e.CheckNodes()
+	for _, node := range e.nodes {
+		// a reachable node that does not agree on the chain is misconfigured rather than unavailable
+		if node.chainMismatch {
+			return fmt.Errorf("%s: %s", node.url.Redacted(), node.health.LastError)
+		}
+	}
+
+	return err
+}
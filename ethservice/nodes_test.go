@@ -0,0 +1,176 @@
+package ethservice
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/internal/testserver"
+)
+
+func TestEthService_ConnectNodes(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		chainID       *big.Int // configured chain id, set from the nodes if nil
+		primary       func(*testserver.Execution)
+		fallback      func(*testserver.Execution)
+		wantErr       string
+		wantActive    int // index of the node in use
+		wantLastError string
+	}{
+		{
+			name:       "primary healthy",
+			wantActive: 0,
+		},
+		{
+			name:       "primary within sync distance",
+			primary:    func(n *testserver.Execution) { n.CurrentBlock, n.HighestBlock = 100, 100+MaxSyncDistance },
+			wantActive: 0,
+		},
+		{
+			name:          "primary behind",
+			primary:       func(n *testserver.Execution) { n.CurrentBlock, n.HighestBlock = 100, 101+MaxSyncDistance },
+			wantActive:    1,
+			wantLastError: fmt.Sprintf("execution node not synced, %d blocks behind", MaxSyncDistance+1),
+		},
+		{
+			name:          "primary unreachable",
+			primary:       func(n *testserver.Execution) { n.Down = true },
+			wantActive:    1,
+			wantLastError: "503",
+		},
+		{
+			name:     "fallback on another chain",
+			fallback: func(n *testserver.Execution) { n.NetworkID = "5" },
+			wantErr:  "chain id mismatch",
+		},
+		{
+			name:    "nodes on another chain than configured",
+			chainID: big.NewInt(5),
+			wantErr: "chain id mismatch",
+		},
+		{
+			name:     "no node healthy",
+			primary:  func(n *testserver.Execution) { n.CurrentBlock, n.HighestBlock = 0, 100 },
+			fallback: func(n *testserver.Execution) { n.Down = true },
+			wantErr:  "no execution node synced and on the expected chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := testserver.NewExecution(t)
+			fallback := testserver.NewExecution(t)
+			if tt.primary != nil {
+				primary.Set(tt.primary)
+			}
+			if tt.fallback != nil {
+				fallback.Set(tt.fallback)
+			}
+
+			e := NewEthService()
+			e.log = logrus.NewEntry(logrus.New())
+			e.cfg.ChainID = tt.chainID
+
+			err := e.connect([]*url.URL{primary.URL, fallback.URL})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("connect() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("connect() error = %v", err)
+			}
+
+			if id := e.ConnectedChainId(); id == nil || id.Cmp(big.NewInt(1)) != 0 {
+				t.Errorf("connected chain id = %v, want 1", id)
+			}
+
+			nodes := e.Nodes()
+			for i, node := range nodes {
+				if node.Active != (i == tt.wantActive) {
+					t.Errorf("node %d active = %v, want %v", i, node.Active, i == tt.wantActive)
+				}
+			}
+			if !strings.Contains(nodes[0].LastError, tt.wantLastError) {
+				t.Errorf("primary last error = %q, want %q", nodes[0].LastError, tt.wantLastError)
+			}
+			if (nodes[0].LastError == "") != nodes[0].Healthy {
+				t.Errorf("primary healthy = %v with last error %q", nodes[0].Healthy, nodes[0].LastError)
+			}
+		})
+	}
+}
+
+func TestEthService_CheckNodes(t *testing.T) {
+
+	primary := testserver.NewExecution(t)
+	fallback := testserver.NewExecution(t)
+
+	e := NewEthService()
+	e.log = logrus.NewEntry(logrus.New())
+	if err := e.connect([]*url.URL{primary.URL, fallback.URL}); err != nil {
+		t.Fatalf("connect() error = %v", err)
+	}
+
+	steps := []struct {
+		name         string
+		update       func()
+		wantErr      string
+		wantActive   int
+		wantFailures []int // consecutive failures of each node
+	}{
+		{
+			name:         "fails over when the primary falls behind",
+			update:       func() { primary.Set(func(n *testserver.Execution) { n.CurrentBlock, n.HighestBlock = 100, 200 }) },
+			wantActive:   1,
+			wantFailures: []int{1, 0},
+		},
+		{
+			name:         "keeps the node in use when none are healthy",
+			update:       func() { fallback.Set(func(n *testserver.Execution) { n.Down = true }) },
+			wantErr:      "no execution node healthy, keeping " + fallback.URL.Redacted(),
+			wantActive:   1,
+			wantFailures: []int{2, 1},
+		},
+		{
+			name:         "switches back to the primary once synced",
+			update:       func() { primary.Set(func(n *testserver.Execution) { n.HighestBlock = 0 }) },
+			wantActive:   0,
+			wantFailures: []int{0, 2},
+		},
+		{
+			name:         "does not use a node that moved to another chain",
+			update:       func() { primary.Set(func(n *testserver.Execution) { n.NetworkID = "5" }) },
+			wantErr:      "chain id mismatch",
+			wantActive:   0,
+			wantFailures: []int{1, 3},
+		},
+	}
+
+	for _, step := range steps {
+		step.update()
+
+		err := e.CheckNodes()
+		if step.wantErr == "" && err != nil {
+			t.Fatalf("%s: CheckNodes() error = %v", step.name, err)
+		} else if step.wantErr != "" && (err == nil || !strings.Contains(err.Error(), step.wantErr)) {
+			t.Fatalf("%s: CheckNodes() error = %v, want %q", step.name, err, step.wantErr)
+		}
+
+		for i, node := range e.Nodes() {
+			if node.Active != (i == step.wantActive) {
+				t.Errorf("%s: node %d active = %v, want %v", step.name, i, node.Active, i == step.wantActive)
+			}
+			if node.ConsecutiveFailures != step.wantFailures[i] {
+				t.Errorf("%s: node %d consecutive failures = %d, want %d", step.name, i, node.ConsecutiveFailures, step.wantFailures[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package ethservice
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEthService_K2NativeDelegationPrechecks(t *testing.T) {
+
+	representative := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	permitted := phase0.BLSPubKey{0xa1}
+	notPermitted := phase0.BLSPubKey{0xa2}
+	reverted := phase0.BLSPubKey{0xa3}
+
+	e := newTestContractService(t, map[string]testContractCall{
+		"isPartOfInclusionList":                   func(args []any) ([]any, error) { return []any{args[0].(common.Address) == representative}, nil },
+		"MAX_NATIVE_DELEGATION_PER_NODE_OPERATOR": func([]any) ([]any, error) { return []any{big.NewInt(50)}, nil },
+		"totalNativeDelegationsForRepresentative": func([]any) ([]any, error) { return []any{big.NewInt(12)}, nil },
+		"isNewBLSKeyPermitted": func(args []any) ([]any, error) {
+			if bytes.Equal(args[0].([]byte), reverted[:]) {
+				return nil, fmt.Errorf("execution reverted")
+			}
+			return []any{bytes.Equal(args[0].([]byte), permitted[:])}, nil
+		},
+	})
+
+	prechecks, err := e.K2NativeDelegationPrechecks(representative, []phase0.BLSPubKey{permitted, notPermitted, reverted})
+	if err != nil {
+		t.Fatalf("K2NativeDelegationPrechecks() error = %v", err)
+	}
+
+	if !prechecks.PartOfInclusionList || prechecks.IndividualMaxNativeDelegation.Cmp(big.NewInt(50)) != 0 || prechecks.TotalNativeDelegations.Cmp(big.NewInt(12)) != 0 {
+		t.Errorf("prechecks = %+v, want part of the inclusion list with 12 of 50 native delegations", prechecks)
+	}
+
+	// a key whose check reverts is not permitted
+	for validator, want := range map[phase0.BLSPubKey]bool{permitted: true, notPermitted: false, reverted: false} {
+		got, ok := prechecks.PermittedValidators[validator.String()]
+		if !ok || got != want {
+			t.Errorf("permitted %s = %v (checked %v), want %v", validator, got, ok, want)
+		}
+	}
+}
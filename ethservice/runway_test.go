@@ -0,0 +1,92 @@
+package ethservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/internal/testserver"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+)
+
+func TestEthService_BatchRunway(t *testing.T) {
+
+	wallet := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	gasPrice := big.NewInt(10_000_000_000) // 10 gwei
+	balance := new(big.Int).Mul(big.NewInt(1e18), big.NewInt(2))
+
+	node := testserver.NewExecution(t)
+	node.Set(func(n *testserver.Execution) {
+		n.Methods = map[string]func([]json.RawMessage) (any, error){
+			"eth_getBalance": func([]json.RawMessage) (any, error) { return fmt.Sprintf("%#x", balance), nil },
+			"eth_gasPrice":   func([]json.RawMessage) (any, error) { return fmt.Sprintf("%#x", gasPrice), nil },
+		}
+	})
+
+	tests := []struct {
+		name            string
+		gasPerValidator map[string]uint64 // recorded from the batches the wallet executed
+		batchSize       uint64
+		want            map[string]uint64
+	}{
+		{
+			name:      "estimated from the default gas of a full batch",
+			batchSize: FullBatchSize,
+			want: map[string]uint64{
+				// 2 ETH / (6,000,000 gas * 10 gwei)
+				metrics.OperationProposerRegistry: 33,
+				// 2 ETH / (9,000,000 gas * 10 gwei)
+				metrics.OperationNativeDelegation: 22,
+			},
+		},
+		{
+			name:            "estimated from the gas the wallet used",
+			gasPerValidator: map[string]uint64{metrics.OperationProposerRegistry: 10_000},
+			batchSize:       FullBatchSize,
+			want: map[string]uint64{
+				// 2 ETH / (10,000 gas * 90 validators * 10 gwei)
+				metrics.OperationProposerRegistry: 222,
+				metrics.OperationNativeDelegation: 22,
+			},
+		},
+		{
+			name:      "scaled to the batch size",
+			batchSize: FullBatchSize / 2,
+			want: map[string]uint64{
+				metrics.OperationProposerRegistry: 66,
+				metrics.OperationNativeDelegation: 44,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEthService()
+			e.log = logrus.NewEntry(logrus.New())
+			if err := e.connect([]*url.URL{node.URL}); err != nil {
+				t.Fatalf("connect() error = %v", err)
+			}
+			if tt.gasPerValidator != nil {
+				e.gasPerValidator[wallet] = tt.gasPerValidator
+			}
+
+			gotBalance, runway, err := e.BatchRunway(wallet, []string{metrics.OperationProposerRegistry, metrics.OperationNativeDelegation}, tt.batchSize)
+			if err != nil {
+				t.Fatalf("BatchRunway() error = %v", err)
+			}
+			if gotBalance.Cmp(balance) != 0 {
+				t.Errorf("balance = %v, want %v", gotBalance, balance)
+			}
+			for operation, want := range tt.want {
+				if runway[operation] != want {
+					t.Errorf("%s runway = %d, want %d", operation, runway[operation], want)
+				}
+			}
+		})
+	}
+}
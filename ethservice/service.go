@@ -6,13 +6,14 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	types "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/sirupsen/logrus"
 
 	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
@@ -22,21 +23,43 @@ import (
 )
 
 type EthService struct {
-	client *ethclient.Client
-	cfg    config.EthServiceConfig
+	cfg config.EthServiceConfig
+
+	nodeLock   sync.RWMutex // guards the node in use, the health of the nodes and the chain they report
+	checkLock  sync.Mutex   // serializes the node health checks
+	nodes      []*executionNode
+	activeNode *executionNode
 
 	log *logrus.Entry
+
+	gasLock         sync.Mutex
+	gasPerValidator map[common.Address]map[string]uint64 // [Representative address] -> [Operation] -> Highest gas used per validator by a batch
+
+	walletLock sync.RWMutex // guards the representative wallets added after the service was configured
+
+	transactionListener func(TransactionUpdate)
+}
+
+// TransactionUpdate describes a step in the lifecycle of a transaction sent by the module
+type TransactionUpdate struct {
+	TxHash         common.Hash    `json:"txHash"`
+	Representative common.Address `json:"representativeAddress"`
+	Operation      string         `json:"operation"`
+	Status         string         `json:"status"`
+	GasUsed        uint64         `json:"gasUsed,omitempty"`
 }
 
 func NewEthService() *EthService {
-	return &EthService{}
+	return &EthService{
+		gasPerValidator: make(map[common.Address]map[string]uint64),
+	}
 }
 
 func (e *EthService) Configure(cfg config.EthServiceConfig, logger *logrus.Entry) error {
 	e.cfg = cfg
 	e.log = logger
 
-	err := e.connect(cfg.ExecutionNodeUrl)
+	err := e.connect(append([]*url.URL{cfg.ExecutionNodeUrl}, cfg.FallbackExecutionNodeUrls...))
 	if err != nil {
 		return fmt.Errorf("failed to connect to execution node: %w", err)
 	}
@@ -101,12 +124,98 @@ func (e *EthService) Configure(cfg config.EthServiceConfig, logger *logrus.Entry
 	return nil
 }
 
+// OnTransactionUpdate sets a listener to be called as transactions are sent and mined.
+// Must be set before any transactions are sent
+func (e *EthService) OnTransactionUpdate(listener func(TransactionUpdate)) {
+	e.transactionListener = listener
+}
+
 func (e *EthService) ConnectedChainId() *big.Int {
+	e.nodeLock.RLock()
+	defer e.nodeLock.RUnlock()
 	return e.cfg.ChainID
 }
 
 func (e *EthService) Status() (*ethereum.SyncProgress, error) {
-	return e.client.SyncProgress(context.Background())
+	return e.client().SyncProgress(context.Background())
+}
+
+func (e *EthService) NetworkChainId() (*big.Int, error) {
+	return e.client().ChainID(context.Background())
+}
+
+// AddValidatorWallet makes a representative wallet available for transactions after the service was configured
+func (e *EthService) AddValidatorWallet(wallet k2common.ValidatorWallet) {
+	e.walletLock.Lock()
+	defer e.walletLock.Unlock()
+
+	if _, ok := e.findValidatorWallet(wallet.Address); ok {
+		return
+	}
+	e.cfg.ValidatorWallets = append(e.cfg.ValidatorWallets, wallet)
+}
+
+// validatorWallet returns the configured wallet of the representative address
+func (e *EthService) validatorWallet(address common.Address) (k2common.ValidatorWallet, bool) {
+	e.walletLock.RLock()
+	defer e.walletLock.RUnlock()
+
+	return e.findValidatorWallet(address)
+}
+
+// primaryWallet returns the first configured wallet, which sends the transactions made on behalf of all representatives
+// and the calls where the sender is not important
+func (e *EthService) primaryWallet() k2common.ValidatorWallet {
+	e.walletLock.RLock()
+	defer e.walletLock.RUnlock()
+
+	return e.cfg.ValidatorWallets[0]
+}
+
+func (e *EthService) findValidatorWallet(address common.Address) (k2common.ValidatorWallet, bool) {
+	for _, wallet := range e.cfg.ValidatorWallets {
+		if wallet.Address == address {
+			return wallet, true
+		}
+	}
+	return k2common.ValidatorWallet{}, false
+}
+
+func (e *EthService) WalletBalance(address common.Address) (*big.Int, error) {
+	return e.client().BalanceAt(context.Background(), address, nil)
+}
+
+// BatchRunway estimates how many batches of batchSize validators of each operation the wallet can still fund at the
+// current gas price. The highest gas used per validator by the wallet's executed batches of an operation is used as
+// the estimate, falling back to the cost of a full batch
+func (e *EthService) BatchRunway(address common.Address, operations []string, batchSize uint64) (balance *big.Int, runway map[string]uint64, err error) {
+
+	balance, err = e.client().BalanceAt(context.Background(), address, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	gasPrice, err := e.client().SuggestGasPrice(context.Background())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve current gas price: %w", err)
+	}
+
+	runway = make(map[string]uint64)
+	e.gasLock.Lock()
+	defer e.gasLock.Unlock()
+	for _, operation := range operations {
+		validatorGas, ok := e.gasPerValidator[address][operation]
+		if !ok {
+			validatorGas = defaultBatchGas[operation] / FullBatchSize
+		}
+		batchCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(validatorGas*batchSize))
+		if batchCost.Sign() == 0 {
+			continue
+		}
+		runway[operation] = new(big.Int).Quo(balance, batchCost).Uint64()
+	}
+
+	return balance, runway, nil
 }
 
 func (e *EthService) SetMaxGasPrice(maxGasPrice uint64) {
@@ -114,7 +223,7 @@ func (e *EthService) SetMaxGasPrice(maxGasPrice uint64) {
 	e.cfg.MaxGasPrice = big.NewInt(int64(maxGasPrice))
 
 	logger := e.log.WithField("maxGasPrice", e.cfg.MaxGasPrice.String())
-	currentGasPrice, err := e.client.SuggestGasPrice(context.Background())
+	currentGasPrice, err := e.client().SuggestGasPrice(context.Background())
 	if err != nil {
 		logger.WithError(err).Debug("Failed to retrieve current gas price")
 	} else {
@@ -132,9 +241,13 @@ func (e *EthService) SetMaxGasPrice(maxGasPrice uint64) {
 	}
 }
 
+func (e *EthService) BlockNumber() (uint64, error) {
+	return e.client().BlockNumber(context.Background())
+}
+
 func (e *EthService) GetBlock(number *big.Int) (*types.Block, error) {
 
-	block, err := e.client.BlockByNumber(context.Background(), number)
+	block, err := e.client().BlockByNumber(context.Background(), number)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching block: %w", err)
 	}
@@ -148,8 +261,8 @@ func (e *EthService) FetchProposerRegistryAddressFromK2Lending() (string, error)
 		return "", fmt.Errorf("error packing proposerRegistry call: %w", err)
 	}
 
-	callResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.K2LendingContractAddress,
 		Data: data,
 	}, nil)
@@ -173,8 +286,8 @@ func (e *EthService) FetchProposerRegistryAddressFromK2NodeOperator() (string, e
 		return "", err
 	}
 
-	callResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.K2NodeOperatorContractAddress,
 		Data: data,
 	}, nil)
@@ -222,8 +335,8 @@ func (e *EthService) BatchCheckRegisteredValidators(validators []phase0.BLSPubKe
 		return nil, err
 	}
 
-	batchCallResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.MulticallContractAddress,
 		Data: multicallInputsEncoded,
 	}, nil)
@@ -264,6 +377,222 @@ func (e *EthService) BatchCheckRegisteredValidators(validators []phase0.BLSPubKe
 	return results, nil
 }
 
+// ValidRegistrationSignatures checks the ECDSA signature of each registration with the proposer registry's
+// validateRegistrationSignature, and recovers its signer locally from the registry's EIP-712 domain separator and
+// typed struct hash of the registration, only accepting signers the registry reports with isSignatureSwapper.
+// A call that reverts is treated as an invalid signature. The recovered signer of each registration is returned
+func (e *EthService) ValidRegistrationSignatures(validatorRegistrations []k2common.K2ValidatorRegistration) (valid map[phase0.BLSPubKey]bool, signers map[phase0.BLSPubKey]common.Address, err error) {
+
+	var multicallInputs contracts.Multicall3AggregateArgs
+
+	valid = make(map[phase0.BLSPubKey]bool)
+	signers = make(map[phase0.BLSPubKey]common.Address)
+
+	if len(validatorRegistrations) == 0 {
+		return valid, signers, nil
+	}
+
+	signatures := make([][]byte, len(validatorRegistrations))
+	for i, reg := range validatorRegistrations {
+
+		sig_r, err := hex.DecodeString(strings.TrimPrefix(reg.ECDSASignature.R, "0x"))
+		if err != nil {
+			return nil, nil, err
+		}
+		var sig_r32 [32]byte
+		copy(sig_r32[:], sig_r)
+		sig_s, err := hex.DecodeString(strings.TrimPrefix(reg.ECDSASignature.S, "0x"))
+		if err != nil {
+			return nil, nil, err
+		}
+		var sig_s32 [32]byte
+		copy(sig_s32[:], sig_s)
+		ecdsaSignature := struct {
+			V uint8
+			R [32]byte
+			S [32]byte
+		}{
+			V: reg.ECDSASignature.V,
+			R: sig_r32,
+			S: sig_s32,
+		}
+		signatures[i] = append(append(sig_r32[:], sig_s32[:]...), reg.ECDSASignature.V)
+
+		payoutRecipient := common.HexToAddress(reg.SignedValidatorRegistration.Message.FeeRecipient.String())
+		validateData, err := e.cfg.ProposerRegistryContractABI.Pack(
+			"validateRegistrationSignature",
+			reg.SignedValidatorRegistration.Message.Pubkey[:],
+			reg.RepresentativeAddress,
+			payoutRecipient,
+			reg.SignedValidatorRegistration.Signature[:],
+			ecdsaSignature,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		structHashData, err := e.cfg.ProposerRegistryContractABI.Pack(
+			"computeTypedStructHash",
+			reg.SignedValidatorRegistration.Message.Pubkey[:],
+			payoutRecipient,
+			reg.SignedValidatorRegistration.Signature[:],
+			reg.RepresentativeAddress,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+			Target:       e.cfg.ProposerRegistryContractAddress,
+			CallData:     validateData,
+			AllowFailure: true,
+		}, contracts.Call3{
+			Target:       e.cfg.ProposerRegistryContractAddress,
+			CallData:     structHashData,
+			AllowFailure: true,
+		})
+	}
+
+	domainSeparatorData, err := e.cfg.ProposerRegistryContractABI.Pack("getDomainSeparator")
+	if err != nil {
+		return nil, nil, err
+	}
+	multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+		Target:       e.cfg.ProposerRegistryContractAddress,
+		CallData:     domainSeparatorData,
+		AllowFailure: false,
+	})
+
+	multicallInputsEncoded, err := e.cfg.MulticallContractABI.Pack("aggregate3", multicallInputs.Calls)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+		To:   &e.cfg.MulticallContractAddress,
+		Data: multicallInputsEncoded,
+	}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var batchCallResultDecoded contracts.Multicall3AggregateResult
+	err = e.cfg.MulticallContractABI.UnpackIntoInterface(&batchCallResultDecoded, "aggregate3", batchCallResult)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error unpacking batch call result: %w", err)
+	}
+
+	var domainSeparator [32]byte
+	err = e.cfg.ProposerRegistryContractABI.UnpackIntoInterface(&domainSeparator, "getDomainSeparator", batchCallResultDecoded.ReturnData[len(batchCallResultDecoded.ReturnData)-1].ReturnData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error unpacking registration domain separator: %w", err)
+	}
+
+	var candidates []common.Address
+	for i, reg := range validatorRegistrations {
+		validator := reg.SignedValidatorRegistration.Message.Pubkey
+		validateResult, structHashResult := batchCallResultDecoded.ReturnData[2*i], batchCallResultDecoded.ReturnData[2*i+1]
+		if !validateResult.Success || !structHashResult.Success {
+			valid[validator] = false
+			continue
+		}
+
+		var validSignature bool
+		err = e.cfg.ProposerRegistryContractABI.UnpackIntoInterface(&validSignature, "validateRegistrationSignature", validateResult.ReturnData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error unpacking registration signature validation: %w", err)
+		}
+		var structHash [32]byte
+		err = e.cfg.ProposerRegistryContractABI.UnpackIntoInterface(&structHash, "computeTypedStructHash", structHashResult.ReturnData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error unpacking registration struct hash: %w", err)
+		}
+
+		signer, err := recoverTypedDataSigner(domainSeparator, structHash, signatures[i])
+		if err != nil {
+			valid[validator] = false
+			continue
+		}
+		valid[validator] = validSignature
+		signers[validator] = signer
+		candidates = append(candidates, signer)
+	}
+
+	swappers, err := e.SignatureSwappers(candidates)
+	if err != nil {
+		return nil, nil, err
+	}
+	for validator, signer := range signers {
+		if !swappers[signer] {
+			valid[validator] = false
+		}
+	}
+
+	return valid, signers, nil
+}
+
+// SignatureSwappers returns whether the proposer registry allows each address to sign registrations with isSignatureSwapper
+func (e *EthService) SignatureSwappers(addresses []common.Address) (map[common.Address]bool, error) {
+
+	var multicallInputs contracts.Multicall3AggregateArgs
+
+	results := make(map[common.Address]bool)
+
+	var unique []common.Address
+	for _, address := range addresses {
+		if _, ok := results[address]; ok {
+			continue
+		}
+		results[address] = false
+		unique = append(unique, address)
+
+		data, err := e.cfg.ProposerRegistryContractABI.Pack("isSignatureSwapper", address)
+		if err != nil {
+			return nil, err
+		}
+		multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+			Target:       e.cfg.ProposerRegistryContractAddress,
+			CallData:     data,
+			AllowFailure: false,
+		})
+	}
+
+	if len(unique) == 0 {
+		return results, nil
+	}
+
+	multicallInputsEncoded, err := e.cfg.MulticallContractABI.Pack("aggregate3", multicallInputs.Calls)
+	if err != nil {
+		return nil, err
+	}
+
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+		To:   &e.cfg.MulticallContractAddress,
+		Data: multicallInputsEncoded,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchCallResultDecoded contracts.Multicall3AggregateResult
+	err = e.cfg.MulticallContractABI.UnpackIntoInterface(&batchCallResultDecoded, "aggregate3", batchCallResult)
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking batch call result: %w", err)
+	}
+
+	for i, address := range unique {
+		var swapper bool
+		err = e.cfg.ProposerRegistryContractABI.UnpackIntoInterface(&swapper, "isSignatureSwapper", batchCallResultDecoded.ReturnData[i].ReturnData)
+		if err != nil {
+			return nil, fmt.Errorf("error unpacking signature swapper check: %w", err)
+		}
+		results[address] = swapper
+	}
+
+	return results, nil
+}
+
 func (e *EthService) BatchRegisterValidators(validatorRegistrations []k2common.K2ValidatorRegistration) (tx *types.Transaction, err error) {
 
 	var blsKeys [][]byte
@@ -318,11 +647,8 @@ func (e *EthService) BatchRegisterValidators(validatorRegistrations []k2common.K
 			}
 		} else {
 			// find the representative wallet from the configured wallets
-			for _, wallet := range e.cfg.ValidatorWallets {
-				if wallet.Address == reg.RepresentativeAddress {
-					representative = wallet
-					break
-				}
+			if wallet, ok := e.validatorWallet(reg.RepresentativeAddress); ok {
+				representative = wallet
 			}
 			// If no representative wallet found, return error
 			if representative.Address == (common.Address{}) {
@@ -378,8 +704,8 @@ func (e *EthService) BatchK2CheckRegisteredValidators(validators []phase0.BLSPub
 		return nil, err
 	}
 
-	batchCallResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.MulticallContractAddress,
 		Data: multicallInputsEncoded,
 	}, nil)
@@ -444,8 +770,8 @@ func (e *EthService) K2NodeOperatorToPayoutRecipient(nodeOperatorAddresses []com
 		return nil, err
 	}
 
-	batchCallResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.MulticallContractAddress,
 		Data: multicallInputsEncoded,
 	}, nil)
@@ -533,11 +859,8 @@ func (e *EthService) K2BatchNativeDelegation(validatorRegistrations []k2common.K
 			}
 		} else {
 			// find the representative wallet from the configured wallets
-			for _, wallet := range e.cfg.ValidatorWallets {
-				if wallet.Address == reg.RepresentativeAddress {
-					representative = wallet
-					break
-				}
+			if wallet, ok := e.validatorWallet(reg.RepresentativeAddress); ok {
+				representative = wallet
 			}
 			// If no representative wallet found, return error
 			if representative.Address == (common.Address{}) {
@@ -593,8 +916,8 @@ func (e *EthService) BatchK2CheckClaimableRewards(nodeOperators []common.Address
 		return nil, err
 	}
 
-	batchCallResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.MulticallContractAddress,
 		Data: multicallInputsEncoded,
 	}, nil)
@@ -638,6 +961,29 @@ func (e *EthService) BatchK2CheckClaimableRewards(nodeOperators []common.Address
 
 func (e *EthService) BatchK2ClaimRewards(rewardClaims []k2common.K2Claim) (tx *types.Transaction, err error) {
 
+	// Claim can be triggered by any representative wallet, use the primary wallet
+	representative := e.primaryWallet()
+
+	data, err := e.packNodeOperatorClaim(rewardClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	executedTx, err := e.transactAndWait(context.Background(), types.NewTx(&types.DynamicFeeTx{
+		To:   &e.cfg.K2NodeOperatorContractAddress,
+		Data: data,
+	}), representative.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error sending batch claim: %w", err)
+	}
+
+	return executedTx, nil
+}
+
+// packNodeOperatorClaim packs the node operator contract's nodeOperatorClaim call for the claims, each claim reporting
+// the effective balance of one of its representative's validators with the balance verifier's signature
+func (e *EthService) packNodeOperatorClaim(rewardClaims []k2common.K2Claim) ([]byte, error) {
+
 	var blsKeys [][]byte
 	var effectiveBalances []*big.Int
 	var ecdsaSignatures []struct {
@@ -646,9 +992,6 @@ func (e *EthService) BatchK2ClaimRewards(rewardClaims []k2common.K2Claim) (tx *t
 		S [32]byte
 	}
 
-	// Claim can be triggered by any representative wallet, use the primary wallet
-	representative := e.cfg.ValidatorWallets[0]
-
 	for _, claim := range rewardClaims {
 
 		blsKeys = append(blsKeys, claim.ValidatorPubKey[:])
@@ -678,20 +1021,99 @@ func (e *EthService) BatchK2ClaimRewards(rewardClaims []k2common.K2Claim) (tx *t
 		})
 	}
 
-	data, err := e.cfg.K2NodeOperatorContractABI.Pack("nodeOperatorClaim", blsKeys, effectiveBalances, ecdsaSignatures)
+	return e.cfg.K2NodeOperatorContractABI.Pack("nodeOperatorClaim", blsKeys, effectiveBalances, ecdsaSignatures)
+}
+
+// TransactionSender recovers the wallet that signed and sent the transaction
+func (e *EthService) TransactionSender(tx *types.Transaction) (common.Address, error) {
+	return types.Sender(types.LatestSignerForChainID(e.cfg.ChainID), tx)
+}
+
+// K2ValidEffectiveBalanceReports checks each effective balance report signed by the balance verifier against the
+// node operator contract's isValidEffectiveBalanceReport in a single batch call, a report that reverts is invalid
+func (e *EthService) K2ValidEffectiveBalanceReports(effectiveBalances map[phase0.BLSPubKey]uint64, signatures map[phase0.BLSPubKey]k2common.EcdsaSignature) (map[phase0.BLSPubKey]bool, error) {
+
+	var multicallInputs contracts.Multicall3AggregateArgs
+
+	results := make(map[phase0.BLSPubKey]bool)
+
+	var validators []phase0.BLSPubKey
+	for validator, signature := range signatures {
+
+		sig_r, err := hex.DecodeString(strings.TrimPrefix(signature.R, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		var sig_r32 [32]byte
+		copy(sig_r32[:], sig_r)
+		sig_s, err := hex.DecodeString(strings.TrimPrefix(signature.S, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		var sig_s32 [32]byte
+		copy(sig_s32[:], sig_s)
+		ecdsaSignature := struct {
+			V uint8
+			R [32]byte
+			S [32]byte
+		}{
+			V: signature.V,
+			R: sig_r32,
+			S: sig_s32,
+		}
+
+		data, err := e.cfg.K2NodeOperatorContractABI.Pack("isValidEffectiveBalanceReport", validator[:], new(big.Int).SetUint64(effectiveBalances[validator]), ecdsaSignature)
+		if err != nil {
+			return nil, err
+		}
+
+		multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+			Target:       e.cfg.K2NodeOperatorContractAddress,
+			CallData:     data,
+			AllowFailure: true,
+		})
+		validators = append(validators, validator)
+	}
+
+	if len(validators) == 0 {
+		return results, nil
+	}
+
+	multicallInputsEncoded, err := e.cfg.MulticallContractABI.Pack("aggregate3", multicallInputs.Calls)
 	if err != nil {
 		return nil, err
 	}
 
-	executedTx, err := e.transactAndWait(context.Background(), types.NewTx(&types.DynamicFeeTx{
-		To:   &e.cfg.K2NodeOperatorContractAddress,
-		Data: data,
-	}), representative.PrivateKey)
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+		To:   &e.cfg.MulticallContractAddress,
+		Data: multicallInputsEncoded,
+	}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error sending batch claim: %w", err)
+		return nil, err
 	}
 
-	return executedTx, nil
+	var batchCallResultDecoded contracts.Multicall3AggregateResult
+	err = e.cfg.MulticallContractABI.UnpackIntoInterface(&batchCallResultDecoded, "aggregate3", batchCallResult)
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking batch call result: %w", err)
+	}
+
+	for i, validator := range validators {
+		if !batchCallResultDecoded.ReturnData[i].Success {
+			results[validator] = false
+			continue
+		}
+
+		var valid bool
+		err = e.cfg.K2NodeOperatorContractABI.UnpackIntoInterface(&valid, "isValidEffectiveBalanceReport", batchCallResultDecoded.ReturnData[i].ReturnData)
+		if err != nil {
+			return nil, fmt.Errorf("error unpacking isValidEffectiveBalanceReport result: %w", err)
+		}
+		results[validator] = valid
+	}
+
+	return results, nil
 }
 
 func (e *EthService) K2Exit(validatorExit k2common.K2Exit) (tx *types.Transaction, err error) {
@@ -722,11 +1144,8 @@ func (e *EthService) K2Exit(validatorExit k2common.K2Exit) (tx *types.Transactio
 	}
 
 	var pk *ecdsa.PrivateKey
-	for _, wallet := range e.cfg.ValidatorWallets {
-		if wallet.Address == validatorExit.RepresentativeAddress {
-			pk = wallet.PrivateKey
-			break
-		}
+	if wallet, ok := e.validatorWallet(validatorExit.RepresentativeAddress); ok {
+		pk = wallet.PrivateKey
 	}
 
 	data, err := e.cfg.K2NodeOperatorContractABI.Pack("nodeOperatorWithdraw", blsKey, effectiveBalance, ecdsaSignature)
@@ -752,8 +1171,8 @@ func (e *EthService) NativeDelegationCountForNodeOperator(nodeOperatorAddress co
 		return nil, err
 	}
 
-	callResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address,
+	callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address,
 		To:   &e.cfg.K2LendingContractAddress,
 		Data: data,
 	}, nil)
@@ -786,11 +1205,8 @@ func (e *EthService) K2ChangeNodeOperatorPayoutAddress(nodeOperator common.Addre
 	}
 
 	var pk *ecdsa.PrivateKey
-	for _, wallet := range e.cfg.ValidatorWallets {
-		if wallet.Address == nodeOperator {
-			pk = wallet.PrivateKey
-			break
-		}
+	if wallet, ok := e.validatorWallet(nodeOperator); ok {
+		pk = wallet.PrivateKey
 	}
 
 	if pk == nil {
@@ -808,6 +1224,443 @@ func (e *EthService) K2ChangeNodeOperatorPayoutAddress(nodeOperator common.Addre
 	return executedTx, nil
 }
 
+// K2DelegatedClaimRecipients returns the recipient each representative delegated its KETH claims to, or the zero address if none
+func (e *EthService) K2DelegatedClaimRecipients(representatives []common.Address) (map[common.Address]common.Address, error) {
+
+	var multicallInputs contracts.Multicall3AggregateArgs
+
+	results := make(map[common.Address]common.Address)
+
+	if len(representatives) == 0 {
+		return results, nil
+	}
+
+	for _, representative := range representatives {
+
+		data, err := e.cfg.K2LendingContractABI.Pack("delegatedClaim", representative)
+		if err != nil {
+			return nil, err
+		}
+
+		multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+			Target:       e.cfg.K2LendingContractAddress,
+			CallData:     data,
+			AllowFailure: false,
+		})
+	}
+
+	multicallInputsEncoded, err := e.cfg.MulticallContractABI.Pack("aggregate3", multicallInputs.Calls)
+	if err != nil {
+		return nil, err
+	}
+
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+		To:   &e.cfg.MulticallContractAddress,
+		Data: multicallInputsEncoded,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchCallResultDecoded contracts.Multicall3AggregateResult
+	err = e.cfg.MulticallContractABI.UnpackIntoInterface(&batchCallResultDecoded, "aggregate3", batchCallResult)
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking batch call result: %w", err)
+	}
+
+	for i, representative := range representatives {
+		results[representative] = common.BytesToAddress(batchCallResultDecoded.ReturnData[i].ReturnData)
+	}
+
+	return results, nil
+}
+
+// K2SetDelegatedClaimRecipient sets the recipient of the representative's KETH claims, the zero address clears it
+func (e *EthService) K2SetDelegatedClaimRecipient(representative common.Address, recipient common.Address) (tx *types.Transaction, err error) {
+
+	data, err := e.cfg.K2LendingContractABI.Pack("setDelegatedRecipient", recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, ok := e.validatorWallet(representative)
+	if !ok {
+		return nil, fmt.Errorf("representative wallet not found for address: %s", representative.String())
+	}
+
+	executedTx, err := e.transactAndWait(context.Background(), types.NewTx(&types.DynamicFeeTx{
+		To:   &e.cfg.K2LendingContractAddress,
+		Data: data,
+	}), wallet.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error sending k2 set delegated claim recipient: %w", err)
+	}
+
+	return executedTx, nil
+}
+
+// K2DelegatedClaim claims the KETH of a representative that delegated its claims through the lending contract's
+// nodeOperatorClaim with the delegated recipient as the recipient override, sent and paid for by the representative
+// wallet itself so the payout goes to the recipient rather than the representative's payout recipient
+func (e *EthService) K2DelegatedClaim(claim k2common.K2Claim) (tx *types.Transaction, err error) {
+
+	data, err := e.packDelegatedClaim(claim)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, ok := e.validatorWallet(claim.RepresentativeAddress)
+	if !ok {
+		return nil, fmt.Errorf("representative wallet not found for address: %s", claim.RepresentativeAddress.String())
+	}
+
+	executedTx, err := e.transactAndWait(context.Background(), types.NewTx(&types.DynamicFeeTx{
+		To:   &e.cfg.K2LendingContractAddress,
+		Data: data,
+	}), wallet.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error sending delegated claim: %w", err)
+	}
+
+	return executedTx, nil
+}
+
+// packDelegatedClaim packs the lending contract's nodeOperatorClaim call paying the claim out to its delegated recipient
+func (e *EthService) packDelegatedClaim(claim k2common.K2Claim) ([]byte, error) {
+
+	if claim.ClaimRecipient == nil || *claim.ClaimRecipient == (common.Address{}) {
+		return nil, fmt.Errorf("claim of representative %s has no delegated claim recipient", claim.RepresentativeAddress.String())
+	}
+
+	return e.cfg.K2LendingContractABI.Pack("nodeOperatorClaim", *claim.ClaimRecipient, claim.RepresentativeAddress)
+}
+
+// KETHToken returns the ERC-20 symbol and decimals of the KETH held in the K2 lending contract
+func (e *EthService) KETHToken() (symbol string, decimals uint8, err error) {
+
+	for _, method := range []string{"symbol", "decimals"} {
+		data, err := e.cfg.K2LendingContractABI.Pack(method)
+		if err != nil {
+			return "", 0, err
+		}
+
+		callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+			From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+			To:   &e.cfg.K2LendingContractAddress,
+			Data: data,
+		}, nil)
+		if err != nil {
+			return "", 0, err
+		}
+
+		if method == "symbol" {
+			err = e.cfg.K2LendingContractABI.UnpackIntoInterface(&symbol, method, callResult)
+		} else {
+			err = e.cfg.K2LendingContractABI.UnpackIntoInterface(&decimals, method, callResult)
+		}
+		if err != nil {
+			return "", 0, fmt.Errorf("error unpacking %s result: %w", method, err)
+		}
+	}
+
+	return symbol, decimals, nil
+}
+
+// KETHBalances returns the KETH each representative can claim with claimKETH, and the KETH it holds
+func (e *EthService) KETHBalances(representatives []common.Address) (claimable map[common.Address]*big.Int, held map[common.Address]*big.Int, err error) {
+
+	var multicallInputs contracts.Multicall3AggregateArgs
+
+	claimable = make(map[common.Address]*big.Int)
+	held = make(map[common.Address]*big.Int)
+
+	if len(representatives) == 0 {
+		return claimable, held, nil
+	}
+
+	for _, representative := range representatives {
+		for _, method := range []string{"claimableKETH", "balanceOf"} {
+			data, err := e.cfg.K2LendingContractABI.Pack(method, representative)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+				Target:       e.cfg.K2LendingContractAddress,
+				CallData:     data,
+				AllowFailure: false,
+			})
+		}
+	}
+
+	multicallInputsEncoded, err := e.cfg.MulticallContractABI.Pack("aggregate3", multicallInputs.Calls)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+		To:   &e.cfg.MulticallContractAddress,
+		Data: multicallInputsEncoded,
+	}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var batchCallResultDecoded contracts.Multicall3AggregateResult
+	err = e.cfg.MulticallContractABI.UnpackIntoInterface(&batchCallResultDecoded, "aggregate3", batchCallResult)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error unpacking batch call result: %w", err)
+	}
+
+	for i, representative := range representatives {
+		claimable[representative] = new(big.Int).SetBytes(batchCallResultDecoded.ReturnData[2*i].ReturnData)
+		held[representative] = new(big.Int).SetBytes(batchCallResultDecoded.ReturnData[2*i+1].ReturnData)
+	}
+
+	return claimable, held, nil
+}
+
+// ClaimKETH claims the claimable KETH of the representative to the representative wallet
+func (e *EthService) ClaimKETH(representative common.Address) (tx *types.Transaction, err error) {
+
+	data, err := e.cfg.K2LendingContractABI.Pack("claimKETH", representative)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, ok := e.validatorWallet(representative)
+	if !ok {
+		return nil, fmt.Errorf("representative wallet not found for address: %s", representative.String())
+	}
+
+	executedTx, err := e.transactAndWait(context.Background(), types.NewTx(&types.DynamicFeeTx{
+		To:   &e.cfg.K2LendingContractAddress,
+		Data: data,
+	}), wallet.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error sending KETH claim: %w", err)
+	}
+
+	return executedTx, nil
+}
+
+// TransferKETH transfers KETH held by the representative wallet to the recipient
+func (e *EthService) TransferKETH(representative common.Address, recipient common.Address, amount *big.Int) (tx *types.Transaction, err error) {
+
+	if (recipient == common.Address{}) {
+		return nil, fmt.Errorf("recipient is null address")
+	}
+
+	data, err := e.cfg.K2LendingContractABI.Pack("transfer", recipient, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, ok := e.validatorWallet(representative)
+	if !ok {
+		return nil, fmt.Errorf("representative wallet not found for address: %s", representative.String())
+	}
+
+	executedTx, err := e.transactAndWait(context.Background(), types.NewTx(&types.DynamicFeeTx{
+		To:   &e.cfg.K2LendingContractAddress,
+		Data: data,
+	}), wallet.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error sending KETH transfer: %w", err)
+	}
+
+	return executedTx, nil
+}
+
+// K2NodeOperatorStatuses returns the lend position, key counts, ban status and inclusion list membership of each
+// representative, and which of the delegated validators of each representative were kicked, in a single batch call
+func (e *EthService) K2NodeOperatorStatuses(representatives []common.Address, delegatedValidators map[common.Address][]phase0.BLSPubKey) (map[common.Address]k2common.NodeOperatorStatus, error) {
+
+	var multicallInputs contracts.Multicall3AggregateArgs
+
+	results := make(map[common.Address]k2common.NodeOperatorStatus)
+
+	if len(representatives) == 0 {
+		return results, nil
+	}
+
+	data, err := e.cfg.K2LendingContractABI.Pack("nodeOperatorInclusionList")
+	if err != nil {
+		return nil, err
+	}
+	multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+		Target:       e.cfg.K2LendingContractAddress,
+		CallData:     data,
+		AllowFailure: false,
+	})
+
+	for _, representative := range representatives {
+		for _, method := range []string{"nodeOperatorToLendPosition", "nodeOperatorToBlsPublicKeyCount", "isNodeOperatorBanned"} {
+			data, err := e.cfg.K2LendingContractABI.Pack(method, representative)
+			if err != nil {
+				return nil, err
+			}
+
+			multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+				Target:       e.cfg.K2LendingContractAddress,
+				CallData:     data,
+				AllowFailure: false,
+			})
+		}
+
+		data, err := e.cfg.K2NodeOperatorContractABI.Pack("isPartOfInclusionList", representative)
+		if err != nil {
+			return nil, err
+		}
+
+		multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+			Target:       e.cfg.K2NodeOperatorContractAddress,
+			CallData:     data,
+			AllowFailure: false,
+		})
+
+		for _, validator := range delegatedValidators[representative] {
+			data, err := e.cfg.K2LendingContractABI.Pack("blsPublicKeyToKicked", validator[:])
+			if err != nil {
+				return nil, err
+			}
+
+			multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+				Target:       e.cfg.K2LendingContractAddress,
+				CallData:     data,
+				AllowFailure: false,
+			})
+		}
+	}
+
+	multicallInputsEncoded, err := e.cfg.MulticallContractABI.Pack("aggregate3", multicallInputs.Calls)
+	if err != nil {
+		return nil, err
+	}
+
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+		To:   &e.cfg.MulticallContractAddress,
+		Data: multicallInputsEncoded,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchCallResultDecoded contracts.Multicall3AggregateResult
+	err = e.cfg.MulticallContractABI.UnpackIntoInterface(&batchCallResultDecoded, "aggregate3", batchCallResult)
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking batch call result: %w", err)
+	}
+
+	inclusionList := common.BytesToAddress(batchCallResultDecoded.ReturnData[0].ReturnData)
+
+	i := 1
+	for _, representative := range representatives {
+		lendPosition, err := e.cfg.K2LendingContractABI.Unpack("nodeOperatorToLendPosition", batchCallResultDecoded.ReturnData[i].ReturnData)
+		if err != nil {
+			return nil, fmt.Errorf("error unpacking nodeOperatorToLendPosition result: %w", err)
+		}
+
+		var banned, partOfInclusionList bool
+		err = e.cfg.K2LendingContractABI.UnpackIntoInterface(&banned, "isNodeOperatorBanned", batchCallResultDecoded.ReturnData[i+2].ReturnData)
+		if err != nil {
+			return nil, fmt.Errorf("error unpacking isNodeOperatorBanned result: %w", err)
+		}
+		err = e.cfg.K2NodeOperatorContractABI.UnpackIntoInterface(&partOfInclusionList, "isPartOfInclusionList", batchCallResultDecoded.ReturnData[i+3].ReturnData)
+		if err != nil {
+			return nil, fmt.Errorf("error unpacking isPartOfInclusionList result: %w", err)
+		}
+
+		status := k2common.NodeOperatorStatus{
+			RepresentativeAddress: representative,
+			LendPosition: k2common.LendPosition{
+				CumulativeKethPerShare: lendPosition[0].(*big.Int),
+				KethEarned:             lendPosition[1].(*big.Int),
+			},
+			BlsPublicKeyCount:   new(big.Int).SetBytes(batchCallResultDecoded.ReturnData[i+1].ReturnData).Uint64(),
+			DelegatedValidators: delegatedValidators[representative],
+			KickedValidators:    []phase0.BLSPubKey{},
+			Banned:              banned,
+			InclusionList:       inclusionList,
+			PartOfInclusionList: partOfInclusionList,
+		}
+		i += 4
+
+		for _, validator := range delegatedValidators[representative] {
+			var kicked bool
+			err = e.cfg.K2LendingContractABI.UnpackIntoInterface(&kicked, "blsPublicKeyToKicked", batchCallResultDecoded.ReturnData[i].ReturnData)
+			if err != nil {
+				return nil, fmt.Errorf("error unpacking blsPublicKeyToKicked result: %w", err)
+			}
+			if kicked {
+				status.KickedValidators = append(status.KickedValidators, validator)
+			}
+			i++
+		}
+
+		results[representative] = status
+	}
+
+	return results, nil
+}
+
+// K2KickedValidatorLogs returns the validators of the representatives kicked from the K2 contract between the blocks,
+// from the NodeOperatorWithdrawn logs of the K2 lending contract
+func (e *EthService) K2KickedValidatorLogs(representatives []common.Address, fromBlock uint64, toBlock uint64) ([]k2common.KickedValidator, error) {
+
+	if len(representatives) == 0 {
+		return nil, nil
+	}
+
+	event, ok := e.cfg.K2LendingContractABI.Events["NodeOperatorWithdrawn"]
+	if !ok {
+		return nil, fmt.Errorf("NodeOperatorWithdrawn event not found in the K2 lending contract ABI")
+	}
+
+	var operatorTopics []common.Hash
+	for _, representative := range representatives {
+		operatorTopics = append(operatorTopics, common.BytesToHash(representative.Bytes()))
+	}
+
+	logs, err := e.client().FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{e.cfg.K2LendingContractAddress},
+		Topics:    [][]common.Hash{{event.ID}, operatorTopics},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var kicked []k2common.KickedValidator
+	for _, log := range logs {
+		if len(log.Topics) < 2 {
+			continue
+		}
+
+		values, err := e.cfg.K2LendingContractABI.Unpack("NodeOperatorWithdrawn", log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("error unpacking NodeOperatorWithdrawn log: %w", err)
+		}
+		blsPublicKey, _ := values[0].([]byte)
+		wasKicked, _ := values[1].(bool)
+		if !wasKicked || len(blsPublicKey) != len(phase0.BLSPubKey{}) {
+			continue
+		}
+
+		kicked = append(kicked, k2common.KickedValidator{
+			ValidatorPubKey:       phase0.BLSPubKey(blsPublicKey),
+			RepresentativeAddress: common.BytesToAddress(log.Topics[1].Bytes()),
+			TxHash:                log.TxHash,
+		})
+	}
+
+	return kicked, nil
+}
+
 // K2 Capacity, Limits & Node Operator Inclusion list
 func (e *EthService) K2CheckInclusionList(nodeOperatorRepresentative common.Address) (bool, error) {
 
@@ -816,8 +1669,8 @@ func (e *EthService) K2CheckInclusionList(nodeOperatorRepresentative common.Addr
 		return false, err
 	}
 
-	callResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.K2NodeOperatorContractAddress,
 		Data: data,
 	}, nil)
@@ -841,8 +1694,8 @@ func (e *EthService) K2CheckInclusionListKeysCount(nodeOperatorRepresentative co
 		return nil, err
 	}
 
-	callResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.K2NodeOperatorContractAddress,
 		Data: data,
 	}, nil)
@@ -859,6 +1712,146 @@ func (e *EthService) K2CheckInclusionListKeysCount(nodeOperatorRepresentative co
 	return callResultDecoded, nil
 }
 
+// K2NativeDelegationPrechecks returns, in a single batch call, whether the representative is part of the inclusion list,
+// its individual capacity and native delegations, and whether the node operator contract permits each key to be delegated
+func (e *EthService) K2NativeDelegationPrechecks(representative common.Address, validators []phase0.BLSPubKey) (k2common.NativeDelegationPrechecks, error) {
+
+	var multicallInputs contracts.Multicall3AggregateArgs
+
+	prechecks := k2common.NativeDelegationPrechecks{
+		PermittedValidators: make(map[string]bool),
+	}
+
+	for _, call := range []struct {
+		method string
+		args   []interface{}
+	}{
+		{"isPartOfInclusionList", []interface{}{representative}},
+		{"MAX_NATIVE_DELEGATION_PER_NODE_OPERATOR", nil},
+		{"totalNativeDelegationsForRepresentative", []interface{}{representative}},
+	} {
+		data, err := e.cfg.K2NodeOperatorContractABI.Pack(call.method, call.args...)
+		if err != nil {
+			return prechecks, err
+		}
+
+		multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+			Target:       e.cfg.K2NodeOperatorContractAddress,
+			CallData:     data,
+			AllowFailure: false,
+		})
+	}
+
+	for _, validator := range validators {
+		data, err := e.cfg.K2NodeOperatorContractABI.Pack("isNewBLSKeyPermitted", validator[:])
+		if err != nil {
+			return prechecks, err
+		}
+
+		multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+			Target:       e.cfg.K2NodeOperatorContractAddress,
+			CallData:     data,
+			AllowFailure: true,
+		})
+	}
+
+	multicallInputsEncoded, err := e.cfg.MulticallContractABI.Pack("aggregate3", multicallInputs.Calls)
+	if err != nil {
+		return prechecks, err
+	}
+
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+		To:   &e.cfg.MulticallContractAddress,
+		Data: multicallInputsEncoded,
+	}, nil)
+	if err != nil {
+		return prechecks, err
+	}
+
+	var batchCallResultDecoded contracts.Multicall3AggregateResult
+	err = e.cfg.MulticallContractABI.UnpackIntoInterface(&batchCallResultDecoded, "aggregate3", batchCallResult)
+	if err != nil {
+		return prechecks, fmt.Errorf("error unpacking batch call result: %w", err)
+	}
+
+	err = e.cfg.K2NodeOperatorContractABI.UnpackIntoInterface(&prechecks.PartOfInclusionList, "isPartOfInclusionList", batchCallResultDecoded.ReturnData[0].ReturnData)
+	if err != nil {
+		return prechecks, fmt.Errorf("error unpacking isPartOfInclusionList result: %w", err)
+	}
+	prechecks.IndividualMaxNativeDelegation = new(big.Int).SetBytes(batchCallResultDecoded.ReturnData[1].ReturnData)
+	prechecks.TotalNativeDelegations = new(big.Int).SetBytes(batchCallResultDecoded.ReturnData[2].ReturnData)
+
+	for i, validator := range validators {
+		result := batchCallResultDecoded.ReturnData[3+i]
+		if !result.Success {
+			// the key is not permitted if the check reverts
+			prechecks.PermittedValidators[validator.String()] = false
+			continue
+		}
+
+		var permitted bool
+		err = e.cfg.K2NodeOperatorContractABI.UnpackIntoInterface(&permitted, "isNewBLSKeyPermitted", result.ReturnData)
+		if err != nil {
+			return prechecks, fmt.Errorf("error unpacking isNewBLSKeyPermitted result: %w", err)
+		}
+		prechecks.PermittedValidators[validator.String()] = permitted
+	}
+
+	return prechecks, nil
+}
+
+func (e *EthService) TotalNativeDelegationsForRepresentatives(representatives []common.Address) (map[common.Address]*big.Int, error) {
+
+	var multicallInputs contracts.Multicall3AggregateArgs
+
+	results := make(map[common.Address]*big.Int)
+
+	if len(representatives) == 0 {
+		return results, nil
+	}
+
+	for _, representative := range representatives {
+
+		data, err := e.cfg.K2NodeOperatorContractABI.Pack("totalNativeDelegationsForRepresentative", representative)
+		if err != nil {
+			return nil, err
+		}
+
+		multicallInputs.Calls = append(multicallInputs.Calls, contracts.Call3{
+			Target:       e.cfg.K2NodeOperatorContractAddress,
+			CallData:     data,
+			AllowFailure: false,
+		})
+	}
+
+	multicallInputsEncoded, err := e.cfg.MulticallContractABI.Pack("aggregate3", multicallInputs.Calls)
+	if err != nil {
+		return nil, err
+	}
+
+	batchCallResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
+		To:   &e.cfg.MulticallContractAddress,
+		Data: multicallInputsEncoded,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchCallResultDecoded contracts.Multicall3AggregateResult
+	err = e.cfg.MulticallContractABI.UnpackIntoInterface(&batchCallResultDecoded, "aggregate3", batchCallResult)
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking batch call result: %w", err)
+	}
+
+	for i, representative := range representatives {
+		results[representative] = new(big.Int).SetBytes(batchCallResultDecoded.ReturnData[i].ReturnData)
+	}
+
+	return results, nil
+}
+
 func (e *EthService) IndividualMaxNativeDelegation() (*big.Int, error) {
 
 	data, err := e.cfg.K2NodeOperatorContractABI.Pack("MAX_NATIVE_DELEGATION_PER_NODE_OPERATOR")
@@ -866,8 +1859,8 @@ func (e *EthService) IndividualMaxNativeDelegation() (*big.Int, error) {
 		return nil, err
 	}
 
-	callResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.K2NodeOperatorContractAddress,
 		Data: data,
 	}, nil)
@@ -891,8 +1884,8 @@ func (e *EthService) GetTotalNativeDelegationCapacityConsumed() (*big.Int, error
 		return nil, err
 	}
 
-	callResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.K2NodeOperatorContractAddress,
 		Data: data,
 	}, nil)
@@ -916,8 +1909,8 @@ func (e *EthService) GlobalMaxNativeDelegation() (*big.Int, error) {
 		return nil, err
 	}
 
-	callResult, err := e.client.CallContract(context.Background(), ethereum.CallMsg{
-		From: e.cfg.ValidatorWallets[0].Address, // use the first wallet to make the call as sender address is not important
+	callResult, err := e.client().CallContract(context.Background(), ethereum.CallMsg{
+		From: e.primaryWallet().Address, // use the first wallet to make the call as sender address is not important
 		To:   &e.cfg.K2NodeOperatorContractAddress,
 		Data: data,
 	}, nil)
@@ -9,17 +9,37 @@ import (
 	"math/big"
 
 	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	types "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+)
+
+const (
+	TransactionStatusSent     = "sent"
+	TransactionStatusMined    = "mined"
+	TransactionStatusReverted = "reverted"
 )
 
+// FullBatchSize is the largest number of validators sent in a single batch transaction
+const FullBatchSize = 90
+
+// Estimated gas used by a full batch of 90 validators for each operation,
+// used for runway estimates until a batch has been executed by the module
+var defaultBatchGas = map[string]uint64{
+	metrics.OperationProposerRegistry: 6_000_000,
+	metrics.OperationNativeDelegation: 9_000_000,
+	metrics.OperationClaim:            2_500_000,
+}
+
 func (e *EthService) waitTx(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
 	queryTicker := time.NewTicker(time.Second)
 	defer queryTicker.Stop()
 
 	logger := e.log.WithField("tx", tx.Hash().Hex())
 	for {
-		receipt, err := e.client.TransactionReceipt(ctx, tx.Hash())
+		receipt, err := e.client().TransactionReceipt(ctx, tx.Hash())
 		if err == nil {
 			return receipt, nil
 		}
@@ -46,18 +66,18 @@ func (e *EthService) transact(context context.Context, tx *types.Transaction, pk
 
 	walletAddress := crypto.PubkeyToAddress(*pk.Public().(*ecdsa.PublicKey))
 	
-	gasPrice, err := e.client.SuggestGasPrice(context)
+	gasPrice, err := e.client().SuggestGasPrice(context)
 	if err != nil {
 		return signedTx, fmt.Errorf("failed to retrieve current gas price: %w", err)
 	}
 	if e.cfg.MaxGasPrice != nil && (e.cfg.MaxGasPrice.Sign() > 0) && gasPrice.Cmp(e.cfg.MaxGasPrice) > 0 {
 		return signedTx, fmt.Errorf("gas price (%s) is higher than max gas price (%s)", gasPrice.String(), e.cfg.MaxGasPrice.String())
 	}
-	gasTip, err := e.client.SuggestGasTipCap(context)
+	gasTip, err := e.client().SuggestGasTipCap(context)
 	if err != nil {
 		return signedTx, fmt.Errorf("failed to suggest gas tip: %w", err)
 	}
-	gasLimit, err := e.client.EstimateGas(context, ethereum.CallMsg{
+	gasLimit, err := e.client().EstimateGas(context, ethereum.CallMsg{
 		From: walletAddress,
 		To:   tx.To(),
 		Data: tx.Data(),
@@ -66,7 +86,7 @@ func (e *EthService) transact(context context.Context, tx *types.Transaction, pk
 	if err != nil {
 		return signedTx, fmt.Errorf("failed to estimate gas: %w", err)
 	}
-	nonce, err := e.client.PendingNonceAt(context, walletAddress)
+	nonce, err := e.client().PendingNonceAt(context, walletAddress)
 	if err != nil {
 		return signedTx, fmt.Errorf("failed to get nonce: %w", err)
 	}
@@ -84,7 +104,7 @@ func (e *EthService) transact(context context.Context, tx *types.Transaction, pk
 	// calculate the total transaction cost to check if the wallet has enough balance
 	maxTxCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
 	txCost := new(big.Int).Add(maxTxCost, tx.Value())
-	balance, err := e.client.BalanceAt(context, walletAddress, nil)
+	balance, err := e.client().BalanceAt(context, walletAddress, nil)
 	if err != nil {
 		return signedTx, fmt.Errorf("failed to get wallet balance: %w", err)
 	}
@@ -102,11 +122,11 @@ func (e *EthService) transact(context context.Context, tx *types.Transaction, pk
 	logger := e.log.WithField("tx", signedTx.Hash().Hex())
 	var pending bool
 
-	sendErr := e.client.SendTransaction(context, signedTx)
+	sendErr := e.client().SendTransaction(context, signedTx)
 	if sendErr != nil {
 		// check if the transaction was already sent using the hash and if in pending ignore the error
 		// this is to handle the case where the transaction was sent but the response was lost or returned a bug error
-		_, pending, err = e.client.TransactionByHash(context, signedTx.Hash())
+		_, pending, err = e.client().TransactionByHash(context, signedTx.Hash())
 		if err != nil {
 			return signedTx, fmt.Errorf("failed to send tx: %w", sendErr)
 		}
@@ -121,21 +141,55 @@ func (e *EthService) transact(context context.Context, tx *types.Transaction, pk
 
 func (e *EthService) transactAndWait(context context.Context, tx *types.Transaction, pk *ecdsa.PrivateKey) (executedTx *types.Transaction, err error) {
 
+	operation := e.transactionOperation(tx.Data())
+	var representativeAddress common.Address
+	if pk != nil {
+		representativeAddress = crypto.PubkeyToAddress(*pk.Public().(*ecdsa.PublicKey))
+	}
+	representative := representativeAddress.String()
+
 	executedTx, err = e.transact(context, tx, pk)
 	if err != nil {
+		metrics.TransactionFailures.WithLabelValues(representative, operation).Inc()
 		return executedTx, err
 	}
 
+	update := TransactionUpdate{
+		TxHash:         executedTx.Hash(),
+		Representative: representativeAddress,
+		Operation:      operation,
+		Status:         TransactionStatusSent,
+	}
+	e.publishTransactionUpdate(update)
+
 	logger := e.log.WithField("tx", executedTx.Hash().Hex())
 
 	logger.Info("K2 Module EthService: Waiting for transaction to be mined")
 
 	receipt, err := e.waitTx(context, executedTx)
 	if err != nil {
+		metrics.TransactionFailures.WithLabelValues(representative, operation).Inc()
 		return executedTx, fmt.Errorf("failed to wait for tx (%s) to be mined: %w", executedTx.Hash().Hex(), err)
 	}
 
+	// gas is paid whether or not the transaction succeeded in execution
+	e.recordBatchGas(representativeAddress, operation, receipt.GasUsed, e.transactionBatchSize(tx.Data()))
+	metrics.GasUsed.WithLabelValues(representative, operation).Add(float64(receipt.GasUsed))
+	if receipt.EffectiveGasPrice != nil {
+		fee := new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+		feeEth, _ := new(big.Float).Quo(new(big.Float).SetInt(fee), new(big.Float).SetInt64(1e18)).Float64()
+		metrics.GasSpent.WithLabelValues(representative, operation).Add(feeEth)
+	}
+
+	update.GasUsed = receipt.GasUsed
+	update.Status = TransactionStatusMined
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		update.Status = TransactionStatusReverted
+	}
+	e.publishTransactionUpdate(update)
+
 	if receipt.Status != types.ReceiptStatusSuccessful {
+		metrics.TransactionFailures.WithLabelValues(representative, operation).Inc()
 		return executedTx, fmt.Errorf("tx (%s) failed in execution", executedTx.Hash().Hex())
 	}
 
@@ -143,4 +197,42 @@ func (e *EthService) transactAndWait(context context.Context, tx *types.Transact
 
 	return executedTx, nil
 
-}
\ No newline at end of file
+}
+// recordBatchGas keeps the highest gas used per validator by the wallet's batches of the operation for runway estimates,
+// the fixed cost of a transaction makes small batches the most expensive per validator so the estimate errs on the low side
+func (e *EthService) recordBatchGas(wallet common.Address, operation string, gasUsed uint64, validators uint64) {
+	e.gasLock.Lock()
+	defer e.gasLock.Unlock()
+
+	if e.gasPerValidator[wallet] == nil {
+		e.gasPerValidator[wallet] = make(map[string]uint64)
+	}
+	if validatorGas := gasUsed / validators; validatorGas > e.gasPerValidator[wallet][operation] {
+		e.gasPerValidator[wallet][operation] = validatorGas
+	}
+}
+
+func (e *EthService) publishTransactionUpdate(update TransactionUpdate) {
+	if e.transactionListener != nil {
+		e.transactionListener(update)
+	}
+}
+
+// recoverTypedDataSigner recovers the signer of an EIP-712 signature over the struct hash in the domain
+func recoverTypedDataSigner(domainSeparator [32]byte, structHash [32]byte, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	digest := crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator[:], structHash[:])
+	publicKey, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
@@ -0,0 +1,171 @@
+package ethservice
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+// testECDSASignature is the signature tuple the contracts take
+type testECDSASignature struct {
+	V uint8
+	R [32]byte
+	S [32]byte
+}
+
+func TestEthService_K2ValidEffectiveBalanceReports(t *testing.T) {
+
+	valid := phase0.BLSPubKey{0xa1}
+	wrongBalance := phase0.BLSPubKey{0xa2}
+	wrongSigner := phase0.BLSPubKey{0xa3}
+	reverted := phase0.BLSPubKey{0xa4}
+
+	verifierR := common.HexToHash("0x01")
+
+	e := newTestContractService(t, map[string]testContractCall{
+		// the contract accepts the reports of 32 ETH signed by the designated verifier
+		"isValidEffectiveBalanceReport": func(args []any) ([]any, error) {
+			if bytes.Equal(args[0].([]byte), reverted[:]) {
+				return nil, fmt.Errorf("execution reverted")
+			}
+			signature := *abi.ConvertType(args[2], new(testECDSASignature)).(*testECDSASignature)
+			return []any{args[1].(*big.Int).Cmp(big.NewInt(32e9)) == 0 && signature.V == 27 && signature.R == verifierR}, nil
+		},
+	})
+
+	verifierSignature := k2common.EcdsaSignature{R: verifierR.Hex(), S: common.HexToHash("0x02").Hex(), V: 27}
+	otherSignature := k2common.EcdsaSignature{R: common.HexToHash("0x03").Hex(), S: common.HexToHash("0x02").Hex(), V: 27}
+
+	got, err := e.K2ValidEffectiveBalanceReports(
+		map[phase0.BLSPubKey]uint64{valid: 32e9, wrongBalance: 31e9, wrongSigner: 32e9, reverted: 32e9},
+		map[phase0.BLSPubKey]k2common.EcdsaSignature{valid: verifierSignature, wrongBalance: verifierSignature, wrongSigner: otherSignature, reverted: verifierSignature},
+	)
+	if err != nil {
+		t.Fatalf("K2ValidEffectiveBalanceReports() error = %v", err)
+	}
+
+	for validator, want := range map[phase0.BLSPubKey]bool{valid: true, wrongBalance: false, wrongSigner: false, reverted: false} {
+		if got[validator] != want {
+			t.Errorf("report of %s valid = %v, want %v", validator, got[validator], want)
+		}
+	}
+
+	if _, err := e.K2ValidEffectiveBalanceReports(
+		map[phase0.BLSPubKey]uint64{valid: 32e9},
+		map[phase0.BLSPubKey]k2common.EcdsaSignature{valid: {R: "0xzz", S: verifierSignature.S, V: 27}},
+	); err == nil {
+		t.Errorf("K2ValidEffectiveBalanceReports() accepted a signature that is not hex")
+	}
+}
+
+// signTypedData signs the EIP-712 digest of the struct hash under the domain separator, returning the signature with
+// the recovery id as v of 27 or 28
+func signTypedData(t *testing.T, key *ecdsa.PrivateKey, domainSeparator [32]byte, structHash [32]byte) k2common.EcdsaSignature {
+	t.Helper()
+
+	signature, err := crypto.Sign(crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator[:], structHash[:]), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k2common.EcdsaSignature{
+		R: hexutil.Encode(signature[:32]),
+		S: hexutil.Encode(signature[32:64]),
+		V: signature[64] + 27,
+	}
+}
+
+func TestRecoverTypedDataSigner(t *testing.T) {
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	domainSeparator := common.HexToHash("0xd0")
+	structHash := common.HexToHash("0x5e")
+
+	signature := signTypedData(t, key, domainSeparator, structHash)
+	raw := append(append(common.FromHex(signature.R), common.FromHex(signature.S)...), signature.V)
+
+	for _, v := range []byte{signature.V, signature.V - 27} {
+		raw[64] = v
+		signer, err := recoverTypedDataSigner(domainSeparator, structHash, raw)
+		if err != nil || signer != crypto.PubkeyToAddress(key.PublicKey) {
+			t.Errorf("recoverTypedDataSigner() with v %d = %s, %v, want %s", v, signer, err, crypto.PubkeyToAddress(key.PublicKey))
+		}
+	}
+
+	// the signer of another struct is not the key
+	raw[64] = signature.V
+	if signer, err := recoverTypedDataSigner(domainSeparator, common.HexToHash("0x5f"), raw); err == nil && signer == crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("recoverTypedDataSigner() recovered the key from the signature of another struct")
+	}
+
+	if _, err := recoverTypedDataSigner(domainSeparator, structHash, raw[:64]); err == nil {
+		t.Errorf("recoverTypedDataSigner() accepted a signature without a recovery id")
+	}
+}
+
+func TestEthService_ValidRegistrationSignatures(t *testing.T) {
+
+	swapperKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swapper := crypto.PubkeyToAddress(swapperKey.PublicKey)
+	domainSeparator := common.HexToHash("0xd0")
+	rejected := phase0.BLSPubKey{0xa3}
+
+	e := newTestContractService(t, map[string]testContractCall{
+		"getDomainSeparator": func([]any) ([]any, error) { return []any{domainSeparator}, nil },
+		"computeTypedStructHash": func(args []any) ([]any, error) {
+			return []any{crypto.Keccak256Hash(args[0].([]byte))}, nil
+		},
+		"validateRegistrationSignature": func(args []any) ([]any, error) {
+			return []any{!bytes.Equal(args[0].([]byte), rejected[:])}, nil
+		},
+		"isSignatureSwapper": func(args []any) ([]any, error) { return []any{args[0].(common.Address) == swapper}, nil },
+	})
+
+	registration := func(pubkey phase0.BLSPubKey, key *ecdsa.PrivateKey) k2common.K2ValidatorRegistration {
+		return k2common.K2ValidatorRegistration{
+			RepresentativeAddress: common.HexToAddress("0x2222222222222222222222222222222222222222"),
+			SignedValidatorRegistration: &apiv1.SignedValidatorRegistration{
+				Message: &apiv1.ValidatorRegistration{Pubkey: pubkey},
+			},
+			ECDSASignature: signTypedData(t, key, domainSeparator, crypto.Keccak256Hash(pubkey[:])),
+		}
+	}
+
+	signedBySwapper := registration(phase0.BLSPubKey{0xa1}, swapperKey)
+	signedByOther := registration(phase0.BLSPubKey{0xa2}, otherKey)
+	rejectedByRegistry := registration(rejected, swapperKey)
+
+	valid, signers, err := e.ValidRegistrationSignatures([]k2common.K2ValidatorRegistration{signedBySwapper, signedByOther, rejectedByRegistry})
+	if err != nil {
+		t.Fatalf("ValidRegistrationSignatures() error = %v", err)
+	}
+
+	for validator, want := range map[phase0.BLSPubKey]bool{{0xa1}: true, {0xa2}: false, rejected: false} {
+		if valid[validator] != want {
+			t.Errorf("signature of %s valid = %v, want %v", validator, valid[validator], want)
+		}
+	}
+	if signers[phase0.BLSPubKey{0xa1}] != swapper || signers[phase0.BLSPubKey{0xa2}] != crypto.PubkeyToAddress(otherKey.PublicKey) {
+		t.Errorf("signers = %v, want %s and %s", signers, swapper, crypto.PubkeyToAddress(otherKey.PublicKey))
+	}
+}
@@ -0,0 +1,560 @@
+package k2
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+const (
+	// Outcomes of each check in an explanation
+	checkPassed        = "passed"
+	checkFailed        = "failed"
+	checkNotApplicable = "not_applicable"
+)
+
+const (
+	// Actions that would be taken for a validator
+	actionRegister          = "register"
+	actionDelegate          = "delegate"
+	actionAlreadyRegistered = "already_registered"
+	actionExcluded          = "excluded"
+	actionUnsupported       = "unsupported"
+	actionDeferred          = "deferred"
+	actionNotConfigured     = "not_configured"
+)
+
+var errRegistrationNotSeen = fmt.Errorf("no registration message has been seen for the validator")
+
+// explainLists is a snapshot of the list entries that apply to a single validator
+type explainLists struct {
+	strictInclusion bool // whether a strict inclusion list is configured
+	included        bool // whether the validator, its fee recipient or a rule matching it is in the strict inclusion list
+
+	// the first entry matched, in the order of precedence used when processing registrations:
+	// exclusion by BLS key, exclusion by fee recipient, inclusion by BLS key, inclusion by fee recipient, then rules by priority
+	filter *k2common.ValidatorFilter
+	list   string
+	match  string
+
+	keyRepresentative          *ethcommon.Address
+	feeRecipientRepresentative *ethcommon.Address
+}
+
+// explainRegistration runs the registration decision logic against the last registration message seen for the
+// validator without sending anything, returning every check made and the action that would be taken
+func (k2 *K2Service) explainRegistration(pubkey phase0.BLSPubKey) (k2common.RegistrationExplanation, error) {
+
+	key := strings.ToLower(pubkey.String())
+
+	k2.statusLock.RLock()
+	registration, ok := k2.recentRegistrations[key]
+	k2.statusLock.RUnlock()
+	if !ok {
+		return k2common.RegistrationExplanation{}, errRegistrationNotSeen
+	}
+
+	explanation := k2common.RegistrationExplanation{
+		ValidatorPubKey:      pubkey,
+		Registration:         &registration,
+		ProposerRegistration: actionNotConfigured,
+		NativeDelegation:     actionNotConfigured,
+	}
+	payloadFeeRecipient := ethcommon.Address(registration.Message.FeeRecipient)
+
+	rules := k2.ruleSnapshot()
+	k2.gatherRuleFacts(&rules, []apiv1.SignedValidatorRegistration{registration})
+
+	lists := k2.explainListSnapshot(key, payloadFeeRecipient, &rules)
+
+	// strict inclusion list, applied when batching the registrations
+	if !lists.strictInclusion {
+		explanation.Decisions = append(explanation.Decisions, k2common.DecisionNode{
+			Check:   "strictInclusionList",
+			Outcome: checkNotApplicable,
+			Reason:  "no strict inclusion list configured",
+		})
+	} else if !lists.included {
+		explanation.Decisions = append(explanation.Decisions, k2common.DecisionNode{
+			Check:   "strictInclusionList",
+			Outcome: checkFailed,
+			Reason:  "neither the validator, its fee recipient nor a rule matching it is in the strict inclusion list",
+		})
+		explanation.ProposerRegistration = actionExcluded
+		if k2.k2Enabled() {
+			explanation.NativeDelegation = actionExcluded
+		}
+		return explanation, nil
+	} else {
+		explanation.Decisions = append(explanation.Decisions, k2common.DecisionNode{
+			Check:   "strictInclusionList",
+			Outcome: checkPassed,
+			Reason:  "validator, its fee recipient or a rule matching it is in the strict inclusion list",
+		})
+	}
+
+	filterNode := k2common.DecisionNode{
+		Check:   "listFilter",
+		Outcome: checkNotApplicable,
+		Reason:  "no exclusion or inclusion list entry for the validator or its fee recipient",
+	}
+	if lists.filter != nil {
+		filterNode.Outcome = checkPassed
+		filterNode.Reason = fmt.Sprintf("matched %s list entry by %s", lists.list, lists.match)
+		filterNode.Data = map[string]any{
+			"allowProposerRegistration": lists.filter.ProposerRegistration,
+			"allowNativeDelegation":     lists.filter.NativeDelegation,
+		}
+	}
+	explanation.Decisions = append(explanation.Decisions, filterNode)
+
+	var payoutMapping map[string]ethcommon.Address = make(map[string]ethcommon.Address)
+	if k2.cfg.K2LendingContractAddress != (ethcommon.Address{}) {
+		wallets := k2.validatorWallets()
+		configuredWalletAddresses := make([]ethcommon.Address, len(wallets))
+		for i, wallet := range wallets {
+			configuredWalletAddresses[i] = wallet.Address
+		}
+		var err error
+		payoutMapping, err = k2.eth1.K2NodeOperatorToPayoutRecipient(configuredWalletAddresses)
+		if err != nil {
+			return explanation, fmt.Errorf("failed to get configured wallet addresses to payout recipient mapping: %w", err)
+		}
+	}
+
+	representativeNode, representative, payoutRecipient := k2.explainRepresentative(lists, payloadFeeRecipient, payoutMapping)
+	explanation.Decisions = append(explanation.Decisions, representativeNode)
+	if representativeNode.Outcome == checkFailed {
+		explanation.ProposerRegistration = actionUnsupported
+		if k2.k2Enabled() {
+			explanation.NativeDelegation = actionUnsupported
+		}
+		return explanation, nil
+	}
+	explanation.RepresentativeAddress = representative
+	explanation.PayoutRecipient = payoutRecipient
+
+	signabilityNode, signable, err := k2.explainSignability(key, payloadFeeRecipient, payoutRecipient)
+	if err != nil {
+		return explanation, err
+	}
+
+	// Proposer Registry
+	proposerRegistryResults, err := k2.eth1.BatchCheckRegisteredValidators([]phase0.BLSPubKey{pubkey})
+	if err != nil {
+		return explanation, fmt.Errorf("failed to check if validator is already proposerRegistry registered: %w", err)
+	}
+	registered := proposerRegistryResults[pubkey.String()]
+
+	registryNode := k2common.DecisionNode{
+		Check: "proposerRegistry",
+	}
+	proposerRegistered := registered.Status != 0
+	if proposerRegistered {
+		registryNode.Outcome = checkPassed
+		registryNode.Reason = "validator is already registered in the Proposer Registry"
+		registryNode.Data = map[string]any{
+			"status":          registered.StatusString(),
+			"representative":  registered.Representative.String(),
+			"payoutRecipient": registered.PayoutRecipient.String(),
+		}
+		explanation.ProposerRegistration = actionAlreadyRegistered
+	} else if lists.filter != nil && !lists.filter.ProposerRegistration {
+		registryNode.Outcome = checkFailed
+		registryNode.Reason = fmt.Sprintf("excluded from Proposer Registry registration by the %s list entry", lists.list)
+		explanation.ProposerRegistration = actionExcluded
+	} else {
+		registryNode.Children = append(registryNode.Children, signabilityNode)
+		if signable {
+			registryNode.Outcome = checkPassed
+			registryNode.Reason = "validator would be registered in the Proposer Registry"
+			explanation.ProposerRegistration = actionRegister
+		} else {
+			registryNode.Outcome = checkFailed
+			registryNode.Reason = "registration cannot be signed for the selected payout recipient"
+			explanation.ProposerRegistration = actionUnsupported
+		}
+	}
+	explanation.Decisions = append(explanation.Decisions, registryNode)
+
+	if k2.cfg.K2LendingContractAddress == (ethcommon.Address{}) {
+		return explanation, nil
+	}
+
+	// K2 native delegation
+	k2RegistrationResults, err := k2.eth1.BatchK2CheckRegisteredValidators([]phase0.BLSPubKey{pubkey})
+	if err != nil {
+		return explanation, fmt.Errorf("failed to check if validator is already registered: %w", err)
+	}
+
+	delegationNode := k2common.DecisionNode{
+		Check: "nativeDelegation",
+	}
+	if delegatedRepresentative := k2RegistrationResults[pubkey.String()]; delegatedRepresentative != "" && !strings.EqualFold(delegatedRepresentative, ethcommon.Address{}.String()) {
+		delegationNode.Outcome = checkPassed
+		delegationNode.Reason = "validator is already natively delegated"
+		delegationNode.Data = map[string]any{
+			"representative": delegatedRepresentative,
+		}
+		explanation.NativeDelegation = actionAlreadyRegistered
+	} else if !proposerRegistered {
+		if explanation.ProposerRegistration != actionRegister {
+			delegationNode.Outcome = checkFailed
+			delegationNode.Reason = "validator is not registered in the Proposer Registry and would not be registered"
+			explanation.NativeDelegation = actionUnsupported
+		} else if lists.filter != nil && lists.filter.NativeDelegation {
+			delegationNode.Outcome = checkPassed
+			delegationNode.Reason = fmt.Sprintf("natively delegated alongside its Proposer Registry registration as allowed by the %s list entry", lists.list)
+			explanation.NativeDelegation = actionDelegate
+		} else {
+			delegationNode.Outcome = checkPassed
+			delegationNode.Reason = "native delegation is processed once the validator is registered in the Proposer Registry"
+			explanation.NativeDelegation = actionDeferred
+		}
+	} else {
+		explanation.NativeDelegation, err = k2.explainNativeDelegation(&delegationNode, pubkey, lists, registered.Representative, representative, signabilityNode, signable)
+		if err != nil {
+			return explanation, err
+		}
+	}
+	explanation.Decisions = append(explanation.Decisions, delegationNode)
+
+	return explanation, nil
+}
+
+func (k2 *K2Service) explainListSnapshot(key string, feeRecipient ethcommon.Address, rules *ruleContext) explainLists {
+
+	feeRecipientKey := strings.ToLower(feeRecipient.String())
+
+	exclusionList, strictInclusionList, representativeMapping := k2.listSnapshot()
+
+	var lists explainLists
+	lists.strictInclusion = len(strictInclusionList) > 0
+	_, keyIncluded := strictInclusionList[key]
+	_, feeRecipientIncluded := strictInclusionList[feeRecipientKey]
+	lists.included = keyIncluded || feeRecipientIncluded || rules.included(key)
+
+	if filter, ok := exclusionList[key]; ok {
+		lists.filter, lists.list, lists.match = &filter, "exclusion", "BLS key"
+	} else if filter, ok := exclusionList[feeRecipientKey]; ok {
+		lists.filter, lists.list, lists.match = &filter, "exclusion", "fee recipient"
+	} else if filter, ok := strictInclusionList[key]; ok {
+		lists.filter, lists.list, lists.match = &filter, "strict inclusion", "BLS key"
+	} else if filter, ok := strictInclusionList[feeRecipientKey]; ok {
+		lists.filter, lists.list, lists.match = &filter, "strict inclusion", "fee recipient"
+	} else if rule, ok := rules.match(key); ok {
+		list := "exclusion"
+		if rule.list == audit.ListInclusion {
+			list = "strict inclusion"
+		}
+		lists.filter, lists.list, lists.match = &rule.filter, list, "rule "+rule.filter.Rule
+	}
+
+	if representative, ok := representativeMapping[key]; ok {
+		lists.keyRepresentative = &representative
+	}
+	if representative, ok := representativeMapping[feeRecipientKey]; ok {
+		lists.feeRecipientRepresentative = &representative
+	}
+
+	return lists
+}
+
+// explainRepresentative selects the representative and payout recipient for the registration the same way
+// registrations are processed, validator specific representatives first, then fee recipient specific
+// representatives, then the configured wallets chosen by the representative strategy without recording the selection
+func (k2 *K2Service) explainRepresentative(lists explainLists, payloadFeeRecipient ethcommon.Address, payoutMapping map[string]ethcommon.Address) (k2common.DecisionNode, ethcommon.Address, ethcommon.Address) {
+
+	node := k2common.DecisionNode{
+		Check:   "representative",
+		Outcome: checkPassed,
+		Data: map[string]any{
+			"payloadFeeRecipient": payloadFeeRecipient.String(),
+		},
+	}
+
+	web3SignerOverride := k2.cfg.Web3SignerUrl != nil && k2.cfg.PayoutRecipient != (ethcommon.Address{})
+
+	var strictRepresentative *ethcommon.Address
+	if lists.keyRepresentative != nil {
+		strictRepresentative = lists.keyRepresentative
+		node.Reason = "representative mapped to the validator BLS key"
+	} else if lists.feeRecipientRepresentative != nil {
+		strictRepresentative = lists.feeRecipientRepresentative
+		node.Reason = "representative mapped to the fee recipient"
+	}
+
+	if strictRepresentative != nil {
+		var found bool
+		for _, wallet := range k2.validatorWallets() {
+			if wallet.Address == *strictRepresentative {
+				found = true
+				break
+			}
+		}
+		if !found {
+			node.Outcome = checkFailed
+			node.Reason = fmt.Sprintf("mapped representative %s is not a configured wallet", strictRepresentative.String())
+			return node, ethcommon.Address{}, ethcommon.Address{}
+		}
+
+		payoutRecipient := payloadFeeRecipient
+		if !k2.k2Enabled() {
+			// Proposer Registry only
+			if web3SignerOverride {
+				payoutRecipient = k2.cfg.PayoutRecipient
+			}
+		} else if onChainPayout := payoutMapping[strictRepresentative.String()]; onChainPayout == (ethcommon.Address{}) {
+			// representative potentially unused
+			if web3SignerOverride {
+				payoutRecipient = k2.cfg.PayoutRecipient
+			}
+		} else if onChainPayout != payloadFeeRecipient {
+			if k2.cfg.Web3SignerUrl == nil {
+				node.Outcome = checkFailed
+				node.Reason = fmt.Sprintf("mapped representative has payout recipient %s set in the contracts which does not match the fee recipient, and no web3signer is configured to re-sign the registration", onChainPayout.String())
+				return node, ethcommon.Address{}, ethcommon.Address{}
+			}
+			payoutRecipient = onChainPayout
+		}
+
+		node.Data["payoutRecipient"] = payoutRecipient.String()
+		return node, *strictRepresentative, payoutRecipient
+	}
+
+	if !k2.k2Enabled() {
+		payoutRecipient := payloadFeeRecipient
+		if web3SignerOverride {
+			payoutRecipient = k2.cfg.PayoutRecipient
+		}
+		representative, reason, err := k2.explainSelectRepresentative(payloadFeeRecipient, k2.validatorWallets())
+		if err != nil {
+			node.Outcome = checkFailed
+			node.Reason = err.Error()
+			return node, ethcommon.Address{}, ethcommon.Address{}
+		}
+		node.Reason = reason + ", used for Proposer Registry only operations"
+		node.Data["payoutRecipient"] = payoutRecipient.String()
+		return node, representative.Address, payoutRecipient
+	}
+
+	var unusedRepresentatives []k2common.ValidatorWallet
+	for _, wallet := range k2.validatorWallets() {
+		if k2.isRepresentativeBanned(wallet.Address) {
+			continue
+		}
+		onChainPayout := payoutMapping[wallet.Address.String()]
+		if onChainPayout == payloadFeeRecipient {
+			node.Reason = "configured wallet already paying out to the fee recipient"
+			node.Data["payoutRecipient"] = payloadFeeRecipient.String()
+			return node, wallet.Address, payloadFeeRecipient
+		} else if onChainPayout == (ethcommon.Address{}) && !k2.isDerivedWallet(wallet.Address) {
+			unusedRepresentatives = append(unusedRepresentatives, wallet)
+		}
+	}
+
+	if len(unusedRepresentatives) > 0 {
+		representative, reason, err := k2.explainSelectRepresentative(payloadFeeRecipient, unusedRepresentatives)
+		if err != nil {
+			node.Outcome = checkFailed
+			node.Reason = err.Error()
+			return node, ethcommon.Address{}, ethcommon.Address{}
+		}
+		payoutRecipient := payloadFeeRecipient
+		if web3SignerOverride {
+			payoutRecipient = k2.cfg.PayoutRecipient
+		}
+		node.Reason = reason + " among the unused configured wallets"
+		node.Data["payoutRecipient"] = payoutRecipient.String()
+		return node, representative.Address, payoutRecipient
+	}
+
+	if k2.hdWallet != nil {
+		k2.lock.Lock()
+		representative, derivationIndex, err := k2.deriveRepresentative(payloadFeeRecipient, false)
+		k2.lock.Unlock()
+		if err != nil {
+			node.Outcome = checkFailed
+			node.Reason = fmt.Sprintf("failed to derive a representative wallet from the mnemonic: %v", err)
+			return node, ethcommon.Address{}, ethcommon.Address{}
+		}
+		payoutRecipient := payloadFeeRecipient
+		if web3SignerOverride {
+			payoutRecipient = k2.cfg.PayoutRecipient
+		}
+		node.Reason = fmt.Sprintf("new representative wallet derived from the mnemonic at index %d for the fee recipient as no configured wallet is unused, used once it is funded", derivationIndex)
+		node.Data["payoutRecipient"] = payoutRecipient.String()
+		node.Data["derivationIndex"] = derivationIndex
+		return node, representative.Address, payoutRecipient
+	}
+
+	if k2.cfg.Web3SignerUrl == nil {
+		node.Outcome = checkFailed
+		node.Reason = "every configured wallet pays out to a different recipient in the contracts and no web3signer is configured to re-sign the registration"
+		return node, ethcommon.Address{}, ethcommon.Address{}
+	}
+
+	representative, reason, err := k2.explainSelectRepresentative(payloadFeeRecipient, k2.validatorWallets())
+	if err != nil {
+		node.Outcome = checkFailed
+		node.Reason = err.Error()
+		return node, ethcommon.Address{}, ethcommon.Address{}
+	}
+	payoutRecipient := payoutMapping[representative.Address.String()]
+	node.Reason = "every configured wallet pays out to a different recipient in the contracts, " + reason + " used with its payout recipient"
+	node.Data["payoutRecipient"] = payoutRecipient.String()
+	return node, representative.Address, payoutRecipient
+}
+
+// explainSelectRepresentative selects the representative among the candidates with the representative strategy
+func (k2 *K2Service) explainSelectRepresentative(payloadFeeRecipient ethcommon.Address, candidates []k2common.ValidatorWallet) (k2common.ValidatorWallet, string, error) {
+	k2.lock.Lock()
+	defer k2.lock.Unlock()
+
+	return k2.selectRepresentative(payloadFeeRecipient, candidates, false)
+}
+
+// explainSignability checks whether the registration can be used for the selected payout recipient,
+// either as is or by being re-signed by the web3signer
+func (k2 *K2Service) explainSignability(key string, payloadFeeRecipient ethcommon.Address, payoutRecipient ethcommon.Address) (k2common.DecisionNode, bool, error) {
+
+	node := k2common.DecisionNode{
+		Check: "web3Signer",
+		Data: map[string]any{
+			"payloadFeeRecipient":     payloadFeeRecipient.String(),
+			"selectedPayoutRecipient": payoutRecipient.String(),
+		},
+	}
+
+	if payoutRecipient == payloadFeeRecipient {
+		node.Outcome = checkNotApplicable
+		node.Reason = "registration is already signed for the selected payout recipient"
+		return node, true, nil
+	}
+
+	if k2.cfg.Web3SignerUrl == nil {
+		node.Outcome = checkFailed
+		node.Reason = "no web3signer configured to re-sign the registration for the selected payout recipient"
+		return node, false, nil
+	}
+
+	signablePubKeys, err := k2.web3Signer.GetPubkeyList()
+	if err != nil {
+		return node, false, fmt.Errorf("failed to get signable pubkeys: %w", err)
+	}
+
+	for signable := range signablePubKeys {
+		if strings.EqualFold(signable, key) {
+			node.Outcome = checkPassed
+			node.Reason = "registration would be re-signed by the web3signer for the selected payout recipient"
+			return node, true, nil
+		}
+	}
+
+	node.Outcome = checkFailed
+	node.Reason = "validator is not signable by the web3signer"
+	return node, false, nil
+}
+
+// explainNativeDelegation checks a validator already registered in the Proposer Registry for native delegation,
+// returning the action that would be taken
+func (k2 *K2Service) explainNativeDelegation(node *k2common.DecisionNode, pubkey phase0.BLSPubKey, lists explainLists, registeredRepresentative ethcommon.Address, representative ethcommon.Address, signabilityNode k2common.DecisionNode, signable bool) (string, error) {
+
+	if lists.filter != nil && !lists.filter.NativeDelegation {
+		node.Outcome = checkFailed
+		node.Reason = fmt.Sprintf("excluded from native delegation by the %s list entry", lists.list)
+		return actionExcluded, nil
+	}
+
+	if k2.isValidatorKicked(pubkey.String()) {
+		node.Outcome = checkFailed
+		node.Reason = "validator was kicked from the K2 contract"
+		return actionExcluded, nil
+	}
+
+	if k2.isRepresentativeBanned(representative) {
+		node.Outcome = checkFailed
+		node.Reason = "representative is banned by the K2 contract"
+		node.Data = map[string]any{
+			"representative": representative.String(),
+		}
+		return actionUnsupported, nil
+	}
+
+	if registeredRepresentative != representative {
+		node.Outcome = checkFailed
+		node.Reason = "validator is registered in the Proposer Registry under a different representative than the one selected"
+		node.Data = map[string]any{
+			"registeredRepresentative": registeredRepresentative.String(),
+		}
+		return actionUnsupported, nil
+	}
+
+	capacityNode := k2common.DecisionNode{
+		Check:   "capacity",
+		Outcome: checkPassed,
+	}
+
+	globalMax, err := k2.eth1.GlobalMaxNativeDelegation()
+	if err != nil {
+		return "", fmt.Errorf("failed to get global max native delegation: %w", err)
+	}
+	globalCurrent, err := k2.eth1.GetTotalNativeDelegationCapacityConsumed()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current global native delegation: %w", err)
+	}
+
+	prechecks, err := k2.eth1.K2NativeDelegationPrechecks(representative, []phase0.BLSPubKey{pubkey})
+	if err != nil {
+		return "", fmt.Errorf("failed to check native delegation eligibility: %w", err)
+	}
+
+	var individualMax, individualCurrent *big.Int
+	if globalMax.Cmp(globalCurrent) <= 0 {
+		if !prechecks.PartOfInclusionList {
+			capacityNode.Outcome = checkFailed
+			capacityNode.Reason = "global max native delegation reached and representative is not in the inclusion list"
+		} else {
+			individualMax, individualCurrent = prechecks.IndividualMaxNativeDelegation, prechecks.TotalNativeDelegations
+			if individualMax.Cmp(individualCurrent) <= 0 {
+				capacityNode.Outcome = checkFailed
+				capacityNode.Reason = "global and individual max native delegation reached"
+			} else {
+				capacityNode.Reason = "global max native delegation reached, individual capacity available"
+			}
+		}
+	} else {
+		capacityNode.Reason = "global capacity available"
+	}
+	capacityNode.Data = capacityData(globalMax, globalCurrent, individualMax, individualCurrent)
+
+	if capacityNode.Outcome == checkPassed && !prechecks.PermittedValidators[pubkey.String()] {
+		capacityNode.Outcome = checkFailed
+		capacityNode.Reason = "node operator contract does not permit the key to be natively delegated"
+	}
+
+	node.Children = append(node.Children, capacityNode)
+	if capacityNode.Outcome == checkFailed {
+		node.Outcome = checkFailed
+		node.Reason = "no native delegation capacity available"
+		return actionUnsupported, nil
+	}
+
+	node.Children = append(node.Children, signabilityNode)
+	if !signable {
+		node.Outcome = checkFailed
+		node.Reason = "registration cannot be signed for the selected payout recipient"
+		return actionUnsupported, nil
+	}
+
+	node.Outcome = checkPassed
+	node.Reason = "validator would be natively delegated"
+	return actionDelegate, nil
+}
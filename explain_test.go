@@ -0,0 +1,177 @@
+package k2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+var (
+	testExplainPubKey       = phase0.BLSPubKey{0xa1}
+	testExplainFeeRecipient = ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	testExplainWallet       = ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// newTestExplainService returns a configured service that has seen a registration message for testExplainPubKey
+func newTestExplainService() *K2Service {
+	k2 := NewK2Service()
+	k2.configured = true
+	k2.cfg.ValidatorWallets = []k2common.ValidatorWallet{{Address: testExplainWallet}}
+	k2.recentRegistrations[strings.ToLower(testExplainPubKey.String())] = apiv1.SignedValidatorRegistration{
+		Message: &apiv1.ValidatorRegistration{
+			FeeRecipient: bellatrix.ExecutionAddress(testExplainFeeRecipient),
+			GasLimit:     30000000,
+			Pubkey:       testExplainPubKey,
+		},
+	}
+	return k2
+}
+
+func TestExplainRegistration(t *testing.T) {
+
+	otherKey := strings.ToLower(phase0.BLSPubKey{0xb2}.String())
+	unknownWallet := ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	tests := []struct {
+		name                     string
+		setup                    func(k2 *K2Service)
+		wantProposerRegistration string
+		wantDecisions            []k2common.DecisionNode // checks and outcomes only
+	}{
+		{
+			name: "not in the strict inclusion list",
+			setup: func(k2 *K2Service) {
+				k2.strictInclusionList[otherKey] = k2common.ValidatorFilter{ProposerRegistration: true}
+			},
+			wantProposerRegistration: actionExcluded,
+			wantDecisions: []k2common.DecisionNode{
+				{Check: "strictInclusionList", Outcome: checkFailed},
+			},
+		},
+		{
+			name: "mapped to a representative that is not configured",
+			setup: func(k2 *K2Service) {
+				k2.representativeMapping[strings.ToLower(testExplainPubKey.String())] = unknownWallet
+			},
+			wantProposerRegistration: actionUnsupported,
+			wantDecisions: []k2common.DecisionNode{
+				{Check: "strictInclusionList", Outcome: checkNotApplicable},
+				{Check: "listFilter", Outcome: checkNotApplicable},
+				{Check: "representative", Outcome: checkFailed},
+			},
+		},
+		{
+			name: "fee recipient in the strict inclusion list mapped to a representative that is not configured",
+			setup: func(k2 *K2Service) {
+				k2.strictInclusionList[strings.ToLower(testExplainFeeRecipient.String())] = k2common.ValidatorFilter{ProposerRegistration: true}
+				k2.representativeMapping[strings.ToLower(testExplainFeeRecipient.String())] = unknownWallet
+			},
+			wantProposerRegistration: actionUnsupported,
+			wantDecisions: []k2common.DecisionNode{
+				{Check: "strictInclusionList", Outcome: checkPassed},
+				{Check: "listFilter", Outcome: checkPassed},
+				{Check: "representative", Outcome: checkFailed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := newTestExplainService()
+			tt.setup(k2)
+
+			explanation, err := k2.explainRegistration(testExplainPubKey)
+			if err != nil {
+				t.Fatalf("explainRegistration() error = %v", err)
+			}
+
+			if explanation.ProposerRegistration != tt.wantProposerRegistration {
+				t.Errorf("proposer registration = %q, want %q", explanation.ProposerRegistration, tt.wantProposerRegistration)
+			}
+			// native delegation is only decided when K2 is configured
+			if explanation.NativeDelegation != actionNotConfigured {
+				t.Errorf("native delegation = %q, want %q", explanation.NativeDelegation, actionNotConfigured)
+			}
+
+			if len(explanation.Decisions) != len(tt.wantDecisions) {
+				t.Fatalf("decisions = %+v, want %+v", explanation.Decisions, tt.wantDecisions)
+			}
+			for i, want := range tt.wantDecisions {
+				got := explanation.Decisions[i]
+				if got.Check != want.Check || got.Outcome != want.Outcome {
+					t.Errorf("decision %d = %s %s (%s), want %s %s", i, got.Check, got.Outcome, got.Reason, want.Check, want.Outcome)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleExplain(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		notConfigured bool
+		pubkey        string
+		wantStatus    int
+	}{
+		{
+			name:       "explained",
+			pubkey:     testExplainPubKey.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "not configured",
+			notConfigured: true,
+			pubkey:        testExplainPubKey.String(),
+			wantStatus:    http.StatusServiceUnavailable,
+		},
+		{
+			name:       "invalid pubkey",
+			pubkey:     "0x1234",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "registration not seen",
+			pubkey:     phase0.BLSPubKey{0xb2}.String(),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := newTestExplainService()
+			k2.configured = !tt.notConfigured
+			// excluded by the strict inclusion list so that the explanation is complete without the execution node
+			k2.strictInclusionList[strings.ToLower(phase0.BLSPubKey{0xb2}.String())] = k2common.ValidatorFilter{ProposerRegistration: true}
+
+			r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"pubkey": tt.pubkey})
+			w := httptest.NewRecorder()
+			k2.handleExplain(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var explanation k2common.RegistrationExplanation
+			if err := json.NewDecoder(w.Body).Decode(&explanation); err != nil {
+				t.Fatalf("response cannot be decoded: %v", err)
+			}
+			if explanation.ValidatorPubKey != testExplainPubKey || explanation.ProposerRegistration != actionExcluded {
+				t.Errorf("explanation = %+v, want %s excluded", explanation, testExplainPubKey)
+			}
+		})
+	}
+}
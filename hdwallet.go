@@ -0,0 +1,245 @@
+package k2
+
+import (
+	"fmt"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/config"
+	"github.com/restaking-cloud/native-delegation-for-plus/ethservice"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+)
+
+// configureHDWallet prepares the derivation of representative wallets from the mnemonic,
+// deriving the primary wallet from it if no private key is configured
+func (k2 *K2Service) configureHDWallet() error {
+
+	hdWallet, err := k2common.NewHDWallet(k2.cfg.WalletMnemonic, k2.cfg.WalletDerivationPath)
+	if err != nil {
+		return fmt.Errorf("-%s: %w", config.WalletMnemonicFlag.Name, err)
+	}
+	k2.hdWallet = hdWallet
+
+	if len(k2.validatorWallets()) == 0 {
+		wallet, err := k2.hdWallet.Derive(0)
+		if err != nil {
+			return fmt.Errorf("-%s: %w", config.WalletMnemonicFlag.Name, err)
+		}
+		k2.walletLock.Lock()
+		k2.cfg.ValidatorWallets = append(k2.cfg.ValidatorWallets, wallet)
+		k2.walletLock.Unlock()
+		k2.nextDerivationIndex = 1
+	}
+
+	return nil
+}
+
+// derivedWallet is a representative wallet derived from the mnemonic with the index it was derived at
+type derivedWallet struct {
+	wallet k2common.ValidatorWallet
+	index  uint32
+}
+
+// deriveMappedWallets derives the representative wallets recorded with a derivation index in the representative mapping,
+// checking each still derives to the representative address it is recorded with. The wallets are only candidates, they
+// are added to the configured wallets with addDerivedWallets once the mapping is accepted
+func (k2 *K2Service) deriveMappedWallets(representativeMappingList []k2common.CustomPayoutRepresentative) ([]derivedWallet, error) {
+
+	var derived []derivedWallet
+	for _, entry := range representativeMappingList {
+		if entry.DerivationIndex == nil {
+			continue
+		}
+		if k2.hdWallet == nil {
+			return nil, fmt.Errorf("representative address %s in representative mapping has a derivation index but no mnemonic is configured", entry.RepresentativeAddress.String())
+		}
+		wallet, err := k2.hdWallet.Derive(*entry.DerivationIndex)
+		if err != nil {
+			return nil, fmt.Errorf("representative address %s in representative mapping: %w", entry.RepresentativeAddress.String(), err)
+		}
+		if wallet.Address != entry.RepresentativeAddress {
+			return nil, fmt.Errorf("representative address %s in representative mapping does not match the wallet %s derived from the mnemonic at index %d", entry.RepresentativeAddress.String(), wallet.Address.String(), *entry.DerivationIndex)
+		}
+		derived = append(derived, derivedWallet{wallet: wallet, index: *entry.DerivationIndex})
+	}
+
+	return derived, nil
+}
+
+// addDerivedWallets adds the wallets derived for an accepted representative mapping to the configured wallets.
+// k2.lock must be held by the caller
+func (k2 *K2Service) addDerivedWallets(derived []derivedWallet) {
+	for _, d := range derived {
+		k2.addDerivedWallet(d.wallet, d.index)
+	}
+}
+
+// addDerivedWallet adds a wallet derived from the mnemonic to the configured wallets, after the explicitly listed keys.
+// k2.lock must be held by the caller
+func (k2 *K2Service) addDerivedWallet(wallet k2common.ValidatorWallet, index uint32) {
+
+	if index >= k2.nextDerivationIndex {
+		k2.nextDerivationIndex = index + 1
+	}
+
+	k2.walletLock.Lock()
+	for _, configured := range k2.cfg.ValidatorWallets {
+		if configured.Address == wallet.Address {
+			k2.walletLock.Unlock()
+			return
+		}
+	}
+	k2.cfg.ValidatorWallets = append(k2.cfg.ValidatorWallets, wallet)
+	k2.walletLock.Unlock()
+
+	k2.eth1.AddValidatorWallet(wallet)
+
+	k2.statusLock.Lock()
+	k2.derivedWallets[wallet.Address] = index
+	k2.statusLock.Unlock()
+
+	k2.log.WithFields(logrus.Fields{
+		"representativeAddress": wallet.Address.String(),
+		"derivationIndex":       index,
+	}).Info("Representative wallet derived from the mnemonic")
+}
+
+// validatorWallets returns a snapshot of the configured wallets in order of priority, including the wallets
+// derived from the mnemonic so far
+func (k2 *K2Service) validatorWallets() []k2common.ValidatorWallet {
+	k2.walletLock.RLock()
+	defer k2.walletLock.RUnlock()
+	return append([]k2common.ValidatorWallet{}, k2.cfg.ValidatorWallets...)
+}
+
+// walletAddresses returns the addresses of the configured wallets and the derived wallets not yet added to them
+func walletAddresses(wallets []k2common.ValidatorWallet, derived []derivedWallet) map[ethcommon.Address]bool {
+	addresses := make(map[ethcommon.Address]bool, len(wallets)+len(derived))
+	for _, wallet := range wallets {
+		addresses[wallet.Address] = true
+	}
+	for _, d := range derived {
+		addresses[d.wallet.Address] = true
+	}
+	return addresses
+}
+
+// isDerivedWallet returns whether the representative wallet was derived from the mnemonic for a fee recipient
+func (k2 *K2Service) isDerivedWallet(address ethcommon.Address) bool {
+	k2.statusLock.RLock()
+	defer k2.statusLock.RUnlock()
+	_, ok := k2.derivedWallets[address]
+	return ok
+}
+
+// checkDerivedWalletFunded checks a wallet derived from the mnemonic holds enough ETH to fund the runway alert
+// threshold of full batches, and at least one, of the operations sent for a registration. A derived wallet starts
+// without a balance, so it is not used until the operator funds it rather than failing its transactions every epoch
+func (k2 *K2Service) checkDerivedWalletFunded(wallet k2common.ValidatorWallet) error {
+
+	operations := []string{metrics.OperationProposerRegistry}
+	if k2.k2Enabled() {
+		operations = append(operations, metrics.OperationNativeDelegation)
+	}
+
+	balance, runway, err := k2.eth1.BatchRunway(wallet.Address, operations, ethservice.FullBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to check the balance of the derived representative wallet %s: %w", wallet.Address.String(), err)
+	}
+
+	threshold := k2.cfg.RunwayAlertThreshold
+	if threshold == 0 {
+		threshold = 1
+	}
+	for _, operation := range operations {
+		if runway[operation] < threshold {
+			return fmt.Errorf("derived representative wallet %s holds %s wei, which funds less than %d %s batches, fund the wallet to use it", wallet.Address.String(), balance.String(), threshold, operation)
+		}
+	}
+
+	return nil
+}
+
+// deriveRepresentative derives the next unused wallet from the mnemonic as the representative of a new fee recipient,
+// skipping the wallets already configured or set on-chain to pay out to a different recipient. If record is set,
+// the wallet is added to the configured wallets and mapped to the fee recipient with its derivation index in the
+// representative mapping file, so the fee recipient keeps its representative across restarts.
+// k2.lock must be held by the caller
+func (k2 *K2Service) deriveRepresentative(feeRecipient ethcommon.Address, record bool) (k2common.ValidatorWallet, uint32, error) {
+
+	for index := k2.nextDerivationIndex; ; index++ {
+		wallet, err := k2.hdWallet.Derive(index)
+		if err != nil {
+			return wallet, index, err
+		}
+
+		configured := false
+		for _, configuredWallet := range k2.validatorWallets() {
+			if configuredWallet.Address == wallet.Address {
+				configured = true
+				break
+			}
+		}
+		if configured {
+			continue
+		}
+
+		payoutRecipients, err := k2.eth1.K2NodeOperatorToPayoutRecipient([]ethcommon.Address{wallet.Address})
+		if err != nil {
+			return wallet, index, fmt.Errorf("failed to get the payout recipient of the derived wallet %s: %w", wallet.Address.String(), err)
+		}
+		if payoutRecipient := payoutRecipients[wallet.Address.String()]; payoutRecipient != (ethcommon.Address{}) && !strings.EqualFold(payoutRecipient.String(), feeRecipient.String()) {
+			// the wallet was used outside of the representative mapping
+			continue
+		}
+
+		if !record {
+			return wallet, index, nil
+		}
+
+		return wallet, index, k2.recordDerivedRepresentative(feeRecipient, wallet, index)
+	}
+}
+
+// recordDerivedRepresentative maps the fee recipient to the derived wallet in the representative mapping file and then
+// adds the wallet to the configured wallets. The mapping is read, extended and written back while holding the list lock so
+// that changes made through the lists API are not lost.
+// k2.lock must be held by the caller
+func (k2 *K2Service) recordDerivedRepresentative(feeRecipient ethcommon.Address, wallet k2common.ValidatorWallet, index uint32) error {
+
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
+
+	entries := []k2common.CustomPayoutRepresentative{}
+	if err := readListEntries(k2.cfg.RepresentativeMappingFile, &entries); err != nil {
+		return err
+	}
+	derivationIndex := index
+	entries = append(entries, k2common.CustomPayoutRepresentative{
+		RepresentativeAddress: wallet.Address,
+		FeeRecipientAddress:   feeRecipient,
+		DerivationIndex:       &derivationIndex,
+	})
+
+	// the wallet is only used once it is recorded, so that the fee recipient maps to the same index after a restart
+	prepared, err := k2.prepareRepresentativeMapping(entries, derivedWallet{wallet: wallet, index: index})
+	if err != nil {
+		return err
+	}
+	if err := writeListFile(k2.cfg.RepresentativeMappingFile, entries); err != nil {
+		return err
+	}
+	k2.addDerivedWallet(wallet, index)
+	k2.representativeMapping = prepared
+
+	k2.log.WithFields(logrus.Fields{
+		"feeRecipient":          feeRecipient.String(),
+		"representativeAddress": wallet.Address.String(),
+		"derivationIndex":       index,
+	}).Warn("Representative wallet derived for new fee recipient, fund the wallet for its validators to be registered")
+
+	return nil
+}
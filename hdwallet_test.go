@@ -0,0 +1,163 @@
+package k2
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+var (
+	// the wallets derived from testMnemonic at index 0 and 1
+	testDerivedWallet0 = ethcommon.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	testDerivedWallet1 = ethcommon.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+)
+
+func newTestHDWalletService(t *testing.T) *K2Service {
+	t.Helper()
+
+	k2 := NewK2Service()
+	k2.cfg.WalletMnemonic = testMnemonic
+	k2.cfg.WalletDerivationPath = k2common.DefaultDerivationPath
+	if err := k2.configureHDWallet(); err != nil {
+		t.Fatalf("configureHDWallet() error = %v", err)
+	}
+	return k2
+}
+
+func TestConfigureHDWallet(t *testing.T) {
+
+	// the primary wallet is derived from the mnemonic when no private key is configured
+	k2 := newTestHDWalletService(t)
+	if wallets := k2.validatorWallets(); len(wallets) != 1 || wallets[0].Address != testDerivedWallet0 {
+		t.Errorf("wallets = %+v, want only %s", wallets, testDerivedWallet0)
+	}
+	if k2.nextDerivationIndex != 1 {
+		t.Errorf("next derivation index = %d, want 1", k2.nextDerivationIndex)
+	}
+
+	configured := ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	k2 = NewK2Service()
+	k2.cfg.WalletMnemonic = testMnemonic
+	k2.cfg.WalletDerivationPath = k2common.DefaultDerivationPath
+	k2.cfg.ValidatorWallets = []k2common.ValidatorWallet{{Address: configured}}
+	if err := k2.configureHDWallet(); err != nil {
+		t.Fatalf("configureHDWallet() error = %v", err)
+	}
+	if wallets := k2.validatorWallets(); len(wallets) != 1 || wallets[0].Address != configured {
+		t.Errorf("wallets = %+v, want only the configured %s", wallets, configured)
+	}
+	if k2.nextDerivationIndex != 0 {
+		t.Errorf("next derivation index = %d, want 0", k2.nextDerivationIndex)
+	}
+
+	k2 = NewK2Service()
+	k2.cfg.WalletMnemonic = "not a mnemonic"
+	k2.cfg.WalletDerivationPath = k2common.DefaultDerivationPath
+	if err := k2.configureHDWallet(); err == nil {
+		t.Errorf("configureHDWallet() accepted an invalid mnemonic")
+	}
+}
+
+func TestDeriveMappedWallets(t *testing.T) {
+
+	index := uint32(1)
+	feeRecipient := ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	tests := []struct {
+		name          string
+		noMnemonic    bool
+		entry         k2common.CustomPayoutRepresentative
+		wantDerived   bool
+		wantErrSubstr string
+	}{
+		{
+			name:  "no derivation index",
+			entry: k2common.CustomPayoutRepresentative{RepresentativeAddress: testDerivedWallet1, FeeRecipientAddress: feeRecipient},
+		},
+		{
+			name:        "derived",
+			entry:       k2common.CustomPayoutRepresentative{RepresentativeAddress: testDerivedWallet1, FeeRecipientAddress: feeRecipient, DerivationIndex: &index},
+			wantDerived: true,
+		},
+		{
+			name:          "derives to another wallet",
+			entry:         k2common.CustomPayoutRepresentative{RepresentativeAddress: testDerivedWallet0, FeeRecipientAddress: feeRecipient, DerivationIndex: &index},
+			wantErrSubstr: "does not match the wallet",
+		},
+		{
+			name:          "no mnemonic",
+			noMnemonic:    true,
+			entry:         k2common.CustomPayoutRepresentative{RepresentativeAddress: testDerivedWallet1, FeeRecipientAddress: feeRecipient, DerivationIndex: &index},
+			wantErrSubstr: "no mnemonic is configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := newTestHDWalletService(t)
+			if tt.noMnemonic {
+				k2.hdWallet = nil
+			}
+
+			derived, err := k2.deriveMappedWallets([]k2common.CustomPayoutRepresentative{tt.entry})
+			if tt.wantErrSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("deriveMappedWallets() error = %v, want %q", err, tt.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("deriveMappedWallets() error = %v", err)
+			}
+			if got := len(derived) == 1 && derived[0].wallet.Address == testDerivedWallet1 && derived[0].index == index; got != tt.wantDerived {
+				t.Errorf("derived = %+v, want derived %v", derived, tt.wantDerived)
+			}
+			// the derived wallets are only candidates until the mapping is accepted
+			if len(k2.validatorWallets()) != 1 {
+				t.Errorf("wallets = %+v, want only the primary wallet", k2.validatorWallets())
+			}
+		})
+	}
+}
+
+func TestChangeRepresentativeMapping_DerivedWallet(t *testing.T) {
+
+	k2 := newTestHDWalletService(t)
+	k2.cfg.RepresentativeMappingFile = filepath.Join(t.TempDir(), "representatives.json")
+	if err := os.WriteFile(k2.cfg.RepresentativeMappingFile, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// a derived wallet mapped through a change that is refused is not added
+	mismatched := `{"representativeAddress":"` + testDerivedWallet1.String() + `","feeRecipientAddress":"0x1111111111111111111111111111111111111111","derivationIndex":2}`
+	if _, err := k2.changeList(listRepresentatives, http.MethodPost, "", []byte(mismatched)); !errors.Is(err, errInvalidListChange) {
+		t.Fatalf("changeList() error = %v, want %v", err, errInvalidListChange)
+	}
+	if len(k2.validatorWallets()) != 1 || k2.isDerivedWallet(testDerivedWallet1) {
+		t.Fatalf("wallet added for a refused change, wallets = %+v", k2.validatorWallets())
+	}
+
+	entry := `{"representativeAddress":"` + testDerivedWallet1.String() + `","feeRecipientAddress":"0x1111111111111111111111111111111111111111","derivationIndex":1}`
+	if _, err := k2.changeList(listRepresentatives, http.MethodPost, "", []byte(entry)); err != nil {
+		t.Fatalf("changeList() error = %v", err)
+	}
+	wallets := k2.validatorWallets()
+	if len(wallets) != 2 || wallets[1].Address != testDerivedWallet1 || !k2.isDerivedWallet(testDerivedWallet1) {
+		t.Errorf("wallets = %+v, want the derived %s added", wallets, testDerivedWallet1)
+	}
+	if k2.nextDerivationIndex != 2 {
+		t.Errorf("next derivation index = %d, want 2", k2.nextDerivationIndex)
+	}
+	if k2.representativeMapping[strings.ToLower("0x1111111111111111111111111111111111111111")] != testDerivedWallet1 {
+		t.Errorf("representative mapping not applied")
+	}
+}
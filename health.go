@@ -0,0 +1,294 @@
+package k2
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+const (
+	// Pending job kinds reported on the health endpoint
+	jobRegistrations = "registrations"
+	jobClaims        = "claims"
+	jobExits         = "exits"
+	jobPayoutUpdates = "payoutUpdates"
+)
+
+const (
+	// Dependency names reported on the health endpoint
+	dependencyBeacon  = "beaconNode"
+	dependencyEth1    = "executionNode"
+	dependencySwapper = "signatureSwapper"
+	dependencyBalance = "balanceVerifier"
+	dependencyGraph   = "subgraph"
+	dependencySigner  = "web3Signer"
+)
+
+// healthCheckInterval is how often the health monitor checks the dependencies and representative wallets
+const healthCheckInterval = 12 * time.Second
+
+type dependencyCheck struct {
+	name     string
+	required bool
+	check    func(health *k2common.DependencyHealth) error
+}
+
+// trackPendingJobs adds count jobs of the given kind to the pending jobs
+// reported on the health endpoint and returns a function to remove them once done
+func (k2 *K2Service) trackPendingJobs(kind string, count int) func() {
+	k2.statusLock.Lock()
+	k2.pendingJobs[kind] += count
+	k2.statusLock.Unlock()
+
+	return func() {
+		k2.statusLock.Lock()
+		k2.pendingJobs[kind] -= count
+		k2.statusLock.Unlock()
+	}
+}
+
+func (k2 *K2Service) k2Enabled() bool {
+	return (k2.cfg.K2LendingContractAddress != ethcommon.Address{}) && (k2.cfg.K2NodeOperatorContractAddress != ethcommon.Address{})
+}
+
+// monitorHealth periodically checks the health of the module, so that Status and the health endpoints
+// report the last result rather than querying every dependency and wallet on each call
+func (k2 *K2Service) monitorHealth() {
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	k2.refreshHealth()
+
+	for {
+		select {
+		case <-k2.exit:
+			return
+		case <-ticker.C:
+			k2.refreshHealth()
+		}
+	}
+}
+
+// refreshHealth checks the health of the module and caches the result
+func (k2 *K2Service) refreshHealth() k2common.HealthStatus {
+	status := k2.checkHealth()
+
+	k2.healthLock.Lock()
+	k2.health = &status
+	k2.healthLock.Unlock()
+
+	return status
+}
+
+// cachedHealth returns the result of the last health check, checking the health now if none has run yet
+func (k2 *K2Service) cachedHealth() k2common.HealthStatus {
+	k2.healthLock.RLock()
+	health := k2.health
+	k2.healthLock.RUnlock()
+
+	if health == nil {
+		return k2.refreshHealth()
+	}
+	return *health
+}
+
+// checkHealth queries all configured dependencies and representative wallets concurrently.
+// The module is ready when every required dependency is reachable, synced and on the expected chain
+func (k2 *K2Service) checkHealth() k2common.HealthStatus {
+
+	status := k2common.HealthStatus{
+		Live:                true,
+		CheckedAt:           time.Now(),
+		LowBalanceThreshold: k2.cfg.LowBalanceThreshold,
+		PendingJobs:         make(map[string]int),
+	}
+
+	k2.statusLock.RLock()
+	if !k2.lastRegistrationMessageTimestamp.IsZero() {
+		lastTimestamp := k2.lastRegistrationMessageTimestamp
+		status.LastRegistrationMessageTimestamp = &lastTimestamp
+	}
+	for kind, count := range k2.pendingJobs {
+		status.PendingJobs[kind] = count
+	}
+	status.DeferredDelegations = len(k2.deferredDelegations)
+	walletRunway := make(map[ethcommon.Address]map[string]uint64, len(k2.walletRunway))
+	for address, runway := range k2.walletRunway {
+		walletRunway[address] = runway
+	}
+	k2.statusLock.RUnlock()
+
+	if !k2.configured {
+		// module not configured to run so there are no dependencies to report on
+		return status
+	}
+
+	expectedChainId := k2.beacon.ConnectedChainId()
+
+	checks := []dependencyCheck{
+		{dependencyBeacon, true, func(health *k2common.DependencyHealth) error {
+			health.Endpoints = k2.beacon.Nodes()
+			syncStatus, err := k2.beacon.Status()
+			if err != nil {
+				return err
+			}
+			health.Syncing = syncStatus.IsSyncing || syncStatus.ElOffline
+			health.SyncDistance = syncStatus.SyncDistance
+			chainId, err := k2.beacon.NetworkChainId()
+			if err != nil {
+				return err
+			}
+			health.ChainID = chainId.Uint64()
+			return nil
+		}},
+		{dependencyEth1, true, func(health *k2common.DependencyHealth) error {
+			health.Endpoints = k2.eth1.Nodes()
+			syncProgress, err := k2.eth1.Status()
+			if err != nil {
+				return err
+			}
+			if syncProgress != nil {
+				// a nil sync progress means the node is not syncing
+				health.Syncing = true
+				if syncProgress.HighestBlock > syncProgress.CurrentBlock {
+					health.SyncDistance = syncProgress.HighestBlock - syncProgress.CurrentBlock
+				}
+			}
+			chainId, err := k2.eth1.NetworkChainId()
+			if err != nil {
+				return err
+			}
+			health.ChainID = chainId.Uint64()
+			return nil
+		}},
+		{dependencySwapper, true, func(health *k2common.DependencyHealth) error {
+			info, err := k2.signatureSwapper.GetInfo()
+			health.Endpoints = k2.signatureSwapper.Endpoints()
+			if err != nil {
+				return err
+			}
+			health.ChainID = info.ChainID
+			return nil
+		}},
+	}
+
+	if k2.cfg.Web3SignerUrl != nil {
+		checks = append(checks, dependencyCheck{dependencySigner, true, func(health *k2common.DependencyHealth) error {
+			return k2.web3Signer.Status()
+		}})
+	}
+
+	if k2.k2Enabled() {
+		checks = append(checks, dependencyCheck{dependencyBalance, true, func(health *k2common.DependencyHealth) error {
+			info, err := k2.balanceverifier.GetInfo()
+			health.Endpoints = k2.balanceverifier.Endpoints()
+			if err != nil {
+				return err
+			}
+			health.ChainID = info.ChainID
+			return nil
+		}})
+
+		// Subgraph is not essential for operations, so is not required for readiness
+		if k2.cfg.SubgraphUrl != nil {
+			checks = append(checks, dependencyCheck{dependencyGraph, false, func(health *k2common.DependencyHealth) error {
+				metaInfo, err := k2.subgraph.MetaInfo()
+				if err != nil {
+					return err
+				}
+				if metaInfo.Meta.HasIndexingErrors {
+					return fmt.Errorf("subgraph has indexing errors")
+				}
+				if chainId := k2.subgraph.ConnectedChainId(); chainId != nil {
+					health.ChainID = chainId.Uint64()
+				}
+				return nil
+			}})
+		}
+	}
+
+	status.Dependencies = make([]k2common.DependencyHealth, len(checks))
+	wallets := k2.validatorWallets()
+	status.Wallets = make([]k2common.WalletHealth, len(wallets))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c dependencyCheck) {
+			defer wg.Done()
+			health := k2common.DependencyHealth{
+				Name:     c.name,
+				Required: c.required,
+			}
+			start := time.Now()
+			err := c.check(&health)
+			health.LatencyMs = time.Since(start).Milliseconds()
+			if err != nil {
+				health.Error = err.Error()
+			} else {
+				health.Reachable = true
+			}
+			status.Dependencies[i] = health
+		}(i, c)
+	}
+
+	for i, wallet := range wallets {
+		wg.Add(1)
+		go func(i int, address ethcommon.Address) {
+			defer wg.Done()
+			health := k2common.WalletHealth{
+				RepresentativeAddress: address,
+				Runway:                walletRunway[address],
+			}
+			balance, err := k2.eth1.WalletBalance(address)
+			if err != nil {
+				health.Error = err.Error()
+			} else {
+				health.Balance, _ = new(big.Float).Quo(new(big.Float).SetInt(balance), big.NewFloat(1e18)).Float64()
+				health.LowBalance = health.Balance < k2.cfg.LowBalanceThreshold
+			}
+			status.Wallets[i] = health
+		}(i, wallet.Address)
+	}
+
+	wg.Wait()
+
+	status.Ready = k2.readinessError(status, expectedChainId) == nil
+
+	return status
+}
+
+// readinessError returns the reason the module is not ready to process registrations, if any
+func (k2 *K2Service) readinessError(status k2common.HealthStatus, expectedChainId *big.Int) error {
+
+	if !k2.configured {
+		return fmt.Errorf("module not configured")
+	}
+
+	var failures []string
+	for _, dependency := range status.Dependencies {
+		if !dependency.Required {
+			continue
+		}
+		switch {
+		case !dependency.Reachable:
+			failures = append(failures, fmt.Sprintf("%s is down: %s", dependency.Name, dependency.Error))
+		case dependency.Syncing:
+			failures = append(failures, fmt.Sprintf("%s is syncing", dependency.Name))
+		case dependency.ChainID != 0 && expectedChainId != nil && dependency.ChainID != expectedChainId.Uint64():
+			failures = append(failures, fmt.Sprintf("%s reports chain id %v, expected %v", dependency.Name, dependency.ChainID, expectedChainId.Uint64()))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package k2
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+func TestReadinessError(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		notConfigured bool
+		dependencies  []k2common.DependencyHealth
+		wantErr       string // empty if ready
+	}{
+		{
+			name: "ready",
+			dependencies: []k2common.DependencyHealth{
+				{Name: dependencyBeacon, Required: true, Reachable: true, ChainID: 1},
+				{Name: dependencyEth1, Required: true, Reachable: true, ChainID: 1},
+			},
+		},
+		{
+			name:          "not configured",
+			notConfigured: true,
+			wantErr:       "module not configured",
+		},
+		{
+			name: "required dependency down",
+			dependencies: []k2common.DependencyHealth{
+				{Name: dependencyBeacon, Required: true, Reachable: true, ChainID: 1},
+				{Name: dependencySwapper, Required: true, Error: "connection refused"},
+			},
+			wantErr: "signatureSwapper is down: connection refused",
+		},
+		{
+			name: "required dependency syncing",
+			dependencies: []k2common.DependencyHealth{
+				{Name: dependencyEth1, Required: true, Reachable: true, Syncing: true, ChainID: 1},
+			},
+			wantErr: "executionNode is syncing",
+		},
+		{
+			name: "required dependency on another chain",
+			dependencies: []k2common.DependencyHealth{
+				{Name: dependencyBalance, Required: true, Reachable: true, ChainID: 5},
+			},
+			wantErr: "balanceVerifier reports chain id 5, expected 1",
+		},
+		{
+			name: "optional dependency down",
+			dependencies: []k2common.DependencyHealth{
+				{Name: dependencyBeacon, Required: true, Reachable: true, ChainID: 1},
+				{Name: dependencyGraph, Required: false, Error: "timeout"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+			k2.configured = !tt.notConfigured
+
+			err := k2.readinessError(k2common.HealthStatus{Dependencies: tt.dependencies}, big.NewInt(1))
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("readinessError() error = %v, want ready", err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("readinessError() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		ready         bool
+		wantHealth    int
+		wantLiveness  int
+		wantReadiness int
+	}{
+		{
+			name:          "ready",
+			ready:         true,
+			wantHealth:    http.StatusOK,
+			wantLiveness:  http.StatusOK,
+			wantReadiness: http.StatusOK,
+		},
+		{
+			name:          "not ready",
+			wantHealth:    http.StatusServiceUnavailable,
+			wantLiveness:  http.StatusOK,
+			wantReadiness: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+			k2.configured = true
+
+			// the endpoints report the cached result of the last check rather than checking the dependencies
+			status := k2common.HealthStatus{Live: true, Ready: tt.ready, CheckedAt: time.Now()}
+			if !tt.ready {
+				status.Dependencies = []k2common.DependencyHealth{{Name: dependencyBeacon, Required: true, Error: "connection refused"}}
+			}
+			k2.health = &status
+
+			for _, endpoint := range []struct {
+				handler http.HandlerFunc
+				want    int
+			}{
+				{k2.handleHealth, tt.wantHealth},
+				{k2.handleLiveness, tt.wantLiveness},
+				{k2.handleReadiness, tt.wantReadiness},
+			} {
+				w := httptest.NewRecorder()
+				endpoint.handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+				if w.Code != endpoint.want {
+					t.Errorf("status = %d, want %d: %s", w.Code, endpoint.want, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCheckHealth_Status(t *testing.T) {
+
+	k2 := NewK2Service()
+
+	lastRegistration := time.Now().Add(-time.Minute)
+	k2.statusLock.Lock()
+	k2.lastRegistrationMessageTimestamp = lastRegistration
+	k2.statusLock.Unlock()
+
+	done := k2.trackPendingJobs(jobClaims, 2)
+	k2.trackPendingJobs(jobExits, 1)()
+
+	// health is checked while registrations are processed, so it must not wait on the processing lock
+	k2.lock.Lock()
+	defer k2.lock.Unlock()
+
+	result := make(chan k2common.HealthStatus, 1)
+	go func() { result <- k2.checkHealth() }()
+
+	var status k2common.HealthStatus
+	select {
+	case status = <-result:
+	case <-time.After(5 * time.Second):
+		t.Fatal("checkHealth() waited on the processing lock")
+	}
+
+	if !status.Live || status.Ready {
+		t.Errorf("live = %v, ready = %v, want live and not ready while not configured", status.Live, status.Ready)
+	}
+	if status.LastRegistrationMessageTimestamp == nil || !status.LastRegistrationMessageTimestamp.Equal(lastRegistration) {
+		t.Errorf("last registration message timestamp = %v, want %v", status.LastRegistrationMessageTimestamp, lastRegistration)
+	}
+	if status.PendingJobs[jobClaims] != 2 || status.PendingJobs[jobExits] != 0 {
+		t.Errorf("pending jobs = %v, want 2 claims and no exits", status.PendingJobs)
+	}
+
+	done()
+	if pending := k2.checkHealth().PendingJobs[jobClaims]; pending != 0 {
+		t.Errorf("pending claims once done = %d, want 0", pending)
+	}
+}
@@ -12,7 +12,12 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
 	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+	"github.com/restaking-cloud/native-delegation-for-plus/stream"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,8 +29,15 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 		return nil, nil
 	}
 
+	// list changes made while the batch is processed apply from the next batch
+	exclusionList, strictInclusionList, representativeMapping := k2.listSnapshot()
+
+	// gather what the exclusion and inclusion list rules match the validators by
+	rules := k2.ruleSnapshot()
+	k2.gatherRuleFacts(&rules, payload)
+
 	// Default to using the primary representative address for the registrations
-	var representative k2common.ValidatorWallet = k2.cfg.ValidatorWallets[0]
+	var representative k2common.ValidatorWallet = k2.validatorWallets()[0]
 	var setPayoutRecipient common.Address = common.Address(payload[0].Message.FeeRecipient)
 	var nodeOperatorTopayoutRecipientMapping map[string]common.Address = make(map[string]common.Address)
 
@@ -36,6 +48,7 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 	var currentIndividualNativeDelegation *big.Int = big.NewInt(0)
 
 	var isInInclusionList bool
+	var permittedValidators map[string]bool // [Validator pubKey] -> Whether the node operator contract permits the key to be natively delegated
 
 	var preChecksComplete atomic.Bool
 	var preChecksError atomic.Value
@@ -76,8 +89,9 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 			go func() {
 				defer wg.Done()
 				var err error
-				configuredWalletAddresses := make([]common.Address, len(k2.cfg.ValidatorWallets))
-				for i, wallet := range k2.cfg.ValidatorWallets {
+				wallets := k2.validatorWallets()
+				configuredWalletAddresses := make([]common.Address, len(wallets))
+				for i, wallet := range wallets {
 					configuredWalletAddresses[i] = wallet.Address
 				}
 				nodeOperatorTopayoutRecipientMapping, err = k2.eth1.K2NodeOperatorToPayoutRecipient(configuredWalletAddresses)
@@ -106,11 +120,11 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 			var representativeFound bool
 
 			// Check for the first validator in the payload and see if it has a specific representative address set
-			if validatorSpecificRepresentative, ok := k2.representativeMapping[strings.ToLower(payload[0].Message.Pubkey.String())]; ok { // if there is a strict representative address for this set of validators
+			if validatorSpecificRepresentative, ok := representativeMapping[strings.ToLower(payload[0].Message.Pubkey.String())]; ok { // if there is a strict representative address for this set of validators
 				// if the first validator in the payload group has a specific representative address set
 				// check if the rest of the validators in the payload have the same representative address set
 				for _, signedValidatorRegistration := range payload {
-					if rep, ok := k2.representativeMapping[strings.ToLower(signedValidatorRegistration.Message.Pubkey.String())]; !ok || !strings.EqualFold(rep.String(), validatorSpecificRepresentative.String()) {
+					if rep, ok := representativeMapping[strings.ToLower(signedValidatorRegistration.Message.Pubkey.String())]; !ok || !strings.EqualFold(rep.String(), validatorSpecificRepresentative.String()) {
 						// if the rest of the validators in the payload do not have the same representative address set
 						// then throw an error as the module cannot proceed with the registrations
 						// this would not ideally happen as the batchProcessor would have grouped the registrations by representative required
@@ -126,7 +140,7 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 				}
 				// if all the validators in the payload have the same representative address specified for their keys
 				// then use this representative address for the payload and try and find the wallet for this representative address
-				for _, wallet := range k2.cfg.ValidatorWallets {
+				for _, wallet := range k2.validatorWallets() {
 					if strings.EqualFold(wallet.Address.String(), validatorSpecificRepresentative.String()) {
 						representative = wallet
 						representativeFound = true
@@ -205,8 +219,8 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 					}
 
 				}
-			} else if useRepAddress, ok := k2.representativeMapping[strings.ToLower(payloadFeeRecipient.String())]; ok { // check if there is a strict representative address for the payload's fee recipient
-				for _, wallet := range k2.cfg.ValidatorWallets {
+			} else if useRepAddress, ok := representativeMapping[strings.ToLower(payloadFeeRecipient.String())]; ok { // check if there is a strict representative address for the payload's fee recipient
+				for _, wallet := range k2.validatorWallets() {
 					if strings.EqualFold(wallet.Address.String(), useRepAddress.String()) {
 						representative = wallet
 						representativeFound = true
@@ -289,26 +303,51 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 					"representative":      representative.Address.String(),
 				}).Debug("using strict representative address for payload's fee recipient")
 			} else { // if no strict representative address for the payload's fee recipient then determine the right representative address to use
-				var unusedRepresentative k2common.ValidatorWallet = k2common.ValidatorWallet{
-					Address: common.Address{},
-				} // holds the next available unused representative address configured
-				var firstUsedRepresentative k2common.ValidatorWallet = k2.cfg.ValidatorWallets[0] // holds the first used representative address configured
+				var unusedRepresentatives []k2common.ValidatorWallet // holds the representative addresses configured that have not been used
+				var err error
 
-				for _, wallet := range k2.cfg.ValidatorWallets { // Check wallets in order of priority set in the configuration
+				for _, wallet := range k2.validatorWallets() { // Check wallets in order of priority set in the configuration
+					if k2.isRepresentativeBanned(wallet.Address) {
+						// a banned representative cannot be natively delegated to, so is never selected
+						k2.log.WithField("representative", wallet.Address.String()).Debug("skipping representative banned by the K2 contract")
+						continue
+					}
 					payoutRecipient := nodeOperatorTopayoutRecipientMapping[wallet.Address.String()]
 					if strings.EqualFold(payoutRecipient.String(), payloadFeeRecipient.String()) {
 						representative = wallet // representative found for the set feeRecipient continue further native delegation using this representative as the payload fee recipient matches
 						representativeFound = true
 						break
-					} else if (payoutRecipient == common.Address{}) && (unusedRepresentative.Address == common.Address{}) {
+					} else if (payoutRecipient == common.Address{}) && !k2.isDerivedWallet(wallet.Address) {
 						// representative not found for the feeRecipient check if the wallet means its potentially unused
-						unusedRepresentative = wallet
+						// a wallet derived from the mnemonic is kept for the fee recipient it was derived for
+						unusedRepresentatives = append(unusedRepresentatives, wallet)
 					}
 				}
-				if !representativeFound {
+				if !representativeFound && len(unusedRepresentatives) == 0 && k2.hdWallet != nil {
+					// Representative not found in contract for this payload's payout recipient
+					// derive a new representative wallet from the mnemonic for this new fee recipient
+					var derivationIndex uint32
+					representative, derivationIndex, err = k2.deriveRepresentative(payloadFeeRecipient, true)
+					if err != nil {
+						k2.log.WithError(err).Error("failed to derive a representative wallet for this payload's fee recipient")
+						preChecksError.Store(err)
+						return
+					}
+					k2.log.WithFields(logrus.Fields{
+						"representative":  representative.Address.String(),
+						"derivationIndex": derivationIndex,
+					}).Debug("using a new representative wallet derived from the mnemonic for payload's fee recipient as no configured wallet is unused")
+
+					// if a global payout recipient has been set in the configuration overwrite the payload's fee recipient
+					// and use the set global payout recipient for this new representative address
+					if k2.cfg.Web3SignerUrl != nil && k2.cfg.PayoutRecipient != (common.Address{}) {
+						payloadFeeRecipient = k2.cfg.PayoutRecipient
+						k2.log.WithField("payoutRecipient", payloadFeeRecipient.String()).Debug("using the payout recipient set in the configuration to overwrite the payload's fee recipient for K2")
+					}
+				} else if !representativeFound {
 					// Representative not found in contract for this payload's payout recipient
 					// check if there is an avaialable representative that has not been used
-					if unusedRepresentative.Address == (common.Address{}) { // there was no unused wallet
+					if len(unusedRepresentatives) == 0 { // there was no unused wallet
 						// no representative available for this payload's payout recipient
 						// as all wallets configured have a payout recipient in the k2 lending contracts that do not match the payload's fee recipient thus
 						// these validators cannot be registered under this node operator's representative address as they do not pay to the specified payout recipient
@@ -316,10 +355,15 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 						// however check if the module is configured for web3signer operations and thus can overwrite the payload's fee recipient
 						if k2.cfg.Web3SignerUrl != nil {
 							// if the k2 web3signer has been set
-							// then can used the first priority wallet as the representative address as all configured wallets have a payout recipient in the k2 lending contracts
+							// then any configured wallet can be selected as the representative address as all configured wallets have a payout recipient in the k2 lending contracts
 
-							representative = firstUsedRepresentative
-							k2.log.WithField("representative", representative.Address.String()).Debug("using the first priority wallet as the representative address as all configured wallets have a payout recipient in the k2 lending contracts that do not match the payload's fee recipient")
+							representative, _, err = k2.selectRepresentative(payloadFeeRecipient, k2.validatorWallets(), true)
+							if err != nil {
+								k2.log.WithError(err).Error("failed to select a representative for this payload's fee recipient")
+								preChecksError.Store(err)
+								return
+							}
+							k2.log.WithField("representative", representative.Address.String()).Debug("using the selected wallet as the representative address as all configured wallets have a payout recipient in the k2 lending contracts that do not match the payload's fee recipient")
 
 							// since the wallet has already been used we cannot use the global payout recipient to overwrite the payload's fee recipient and would need to use the existing payout recipient for this representative
 							payloadFeeRecipient = nodeOperatorTopayoutRecipientMapping[representative.Address.String()]
@@ -332,8 +376,13 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 							return
 						}
 					} else {
-						// use the next available representative address that has not been used
-						representative = unusedRepresentative
+						// select one of the available representative addresses that have not been used
+						representative, _, err = k2.selectRepresentative(payloadFeeRecipient, unusedRepresentatives, true)
+						if err != nil {
+							k2.log.WithError(err).Error("failed to select a representative for this payload's fee recipient")
+							preChecksError.Store(err)
+							return
+						}
 
 						// if a global payout recipient has been set in the configuration and it doesnt match the
 						// payout recipient overwrite the payload's fee recipient and use the set global payout recipient
@@ -346,76 +395,71 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 				}
 			}
 
+			// a wallet derived from the mnemonic starts without a balance, skip it until it is funded
+			if k2.isDerivedWallet(representative.Address) {
+				if err := k2.checkDerivedWalletFunded(representative); err != nil {
+					k2.log.WithError(err).Warn("skipping registrations for a derived representative wallet that is not funded")
+					preChecksError.Store(err)
+					return
+				}
+			}
+
 			// re-assign the payout recipient if it has been changed
 			// from the seletion of the representative address and payout recipient
 			setPayoutRecipient = payloadFeeRecipient
 
+			if globalMaxNativeDelegation != nil && currentGlobalNativeDelegation != nil {
+				globalRemaining, _ := new(big.Float).SetInt(new(big.Int).Sub(globalMaxNativeDelegation, currentGlobalNativeDelegation)).Float64()
+				metrics.CapacityRemaining.WithLabelValues(metrics.CapacityScopeGlobal, "").Set(globalRemaining)
+				k2.notifyCapacity(metrics.CapacityScopeGlobal, common.Address{}, globalMaxNativeDelegation, currentGlobalNativeDelegation)
+			}
+
+			// check the representative and every key against the node operator contract in a single batch call
+			// so each key has an authoritative verdict on whether it can be natively delegated before a batch is sent
+			validatorKeys := make([]phase0.BLSPubKey, 0, len(payload))
+			for _, signedValidatorRegistration := range payload {
+				validatorKeys = append(validatorKeys, signedValidatorRegistration.Message.Pubkey)
+			}
+			prechecks, err := k2.eth1.K2NativeDelegationPrechecks(representative.Address, validatorKeys)
+			if err != nil {
+				k2.log.WithError(err).Errorf("failed to check native delegation eligibility for representative: %s", representative.Address.String())
+				preChecksError.Store(err)
+				return
+			}
+			isInInclusionList = prechecks.PartOfInclusionList
+			individualMaxNativeDelegation = prechecks.IndividualMaxNativeDelegation
+			currentIndividualNativeDelegation = prechecks.TotalNativeDelegations
+			permittedValidators = prechecks.PermittedValidators
+			k2.log.WithFields(logrus.Fields{
+				"representative":                    representative.Address.String(),
+				"isInInclusionList":                 isInInclusionList,
+				"individualMaxNativeDelegation":     individualMaxNativeDelegation.String(),
+				"currentIndividualNativeDelegation": currentIndividualNativeDelegation.String(),
+			}).Debug("native delegation eligibility of representative")
+
 			if globalMaxNativeDelegation != nil && currentGlobalNativeDelegation != nil && globalMaxNativeDelegation.Cmp(currentGlobalNativeDelegation) <= 0 {
 				// global max native delegation has been reached
-				// so need to check individual max native delegation
+				// so the individual max native delegation applies to inclusion list members
 				k2.log.WithField("globalMaxNativeDelegation", globalMaxNativeDelegation.String()).Debug("global max native delegation has been reached")
 
-				isInInclusionList, err := k2.eth1.K2CheckInclusionList(representative.Address)
-				if err != nil {
-					k2.log.WithError(err).Error("failed to check if validator's representative address is in inclusion list")
-					preChecksError.Store(err)
-					return
-				}
-
 				if !isInInclusionList {
 					// validator representative is not in the inclusion list
 					// so cannot natively delegate this validator
-					k2.log.WithField("validatorRepresentative", representative.Address.String()).Debug("validator's representative is not in the inclusion list")
 					// this is not an error and just means that the validator's representative address is not in the inclusion list to exceed the global max native delegation
-					preChecksComplete.Store(true)
-					return
-				}
-
-				// use a sync wait group to wait for the results of the individual native delegation checks
-				var wg sync.WaitGroup
-				wg.Add(2)
-
-				go func() {
-					defer wg.Done()
-					var err error
-					individualMaxNativeDelegation, err = k2.eth1.IndividualMaxNativeDelegation()
-					if err != nil {
-						k2.log.WithError(err).Errorf("failed to get individual max native delegation for representative: %s", representative.Address.String())
-						preChecksError.Store(err)
-						return
-					}
-					k2.log.WithField("individualMaxNativeDelegation", individualMaxNativeDelegation.String()).Debug("individual max native delegation")
-				}()
-
-				go func() {
-					defer wg.Done()
-					var err error
-					currentIndividualNativeDelegation, err := k2.eth1.K2CheckInclusionListKeysCount(representative.Address)
-					if err != nil {
-						k2.log.WithError(err).Errorf("failed to get current individual native delegation for representative: %s", representative.Address.String())
-						preChecksError.Store(err)
-						return
-					}
-					k2.log.WithField("currentIndividualNativeDelegation", currentIndividualNativeDelegation.String()).Debugf("current individual native delegation for representative: %s", representative.Address.String())
-				}()
-
-				wg.Wait()
-
-				if preChecksError.Load() != nil {
-					// error occurred while getting the individual native delegation
-					// so cannot proceed with the registrations
-					k2.log.WithError(preChecksError.Load().(error)).Error("failed to get individual native delegation")
-					return
+					k2.log.WithField("validatorRepresentative", representative.Address.String()).Debug("validator's representative is not in the inclusion list")
+				} else {
+					individualRemaining, _ := new(big.Float).SetInt(new(big.Int).Sub(individualMaxNativeDelegation, currentIndividualNativeDelegation)).Float64()
+					metrics.CapacityRemaining.WithLabelValues(metrics.CapacityScopeIndividual, representative.Address.String()).Set(individualRemaining)
+					k2.notifyCapacity(metrics.CapacityScopeIndividual, representative.Address, individualMaxNativeDelegation, currentIndividualNativeDelegation)
 				}
-
-				preChecksComplete.Store(true)
 			} else {
 				// global max native delegation has not been reached
 				// so no need to check individual max native delegation
 				k2.log.WithField("globalMaxNativeDelegation", globalMaxNativeDelegation.String()).Debug("global max native delegation has not been reached")
-				preChecksComplete.Store(true)
 			}
 
+			preChecksComplete.Store(true)
+
 		} else {
 			// If the module is configured for Proposer Registry operations only
 
@@ -423,11 +467,11 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 			var representativeFound bool
 
 			// Check for the first validator in the payload and see if it has a specific representative address set
-			if validatorSpecificRepresentative, ok := k2.representativeMapping[strings.ToLower(payload[0].Message.Pubkey.String())]; ok { // if there is a strict representative address for this set of validators
+			if validatorSpecificRepresentative, ok := representativeMapping[strings.ToLower(payload[0].Message.Pubkey.String())]; ok { // if there is a strict representative address for this set of validators
 				// if the first validator in the payload group has a specific representative address set
 				// check if the rest of the validators in the payload have the same representative address set
 				for _, signedValidatorRegistration := range payload {
-					if rep, ok := k2.representativeMapping[strings.ToLower(signedValidatorRegistration.Message.Pubkey.String())]; !ok || !strings.EqualFold(rep.String(), validatorSpecificRepresentative.String()) {
+					if rep, ok := representativeMapping[strings.ToLower(signedValidatorRegistration.Message.Pubkey.String())]; !ok || !strings.EqualFold(rep.String(), validatorSpecificRepresentative.String()) {
 						// if the rest of the validators in the payload do not have the same representative address set
 						// then throw an error as the module cannot proceed with the registrations
 						// this would not ideally happen as the batchProcessor would have grouped the registrations by representative required
@@ -443,7 +487,7 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 				}
 				// if all the validators in the payload have the same representative address specified for their keys
 				// then use this representative address for the payload and try and find the wallet for this representative address
-				for _, wallet := range k2.cfg.ValidatorWallets {
+				for _, wallet := range k2.validatorWallets() {
 					if strings.EqualFold(wallet.Address.String(), validatorSpecificRepresentative.String()) {
 						representative = wallet
 						representativeFound = true
@@ -471,8 +515,8 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 					}
 
 				}
-			} else if useRepAddress, ok := k2.representativeMapping[strings.ToLower(payloadFeeRecipient.String())]; ok { // check if there is a strict representative address for the payload's fee recipient
-				for _, wallet := range k2.cfg.ValidatorWallets {
+			} else if useRepAddress, ok := representativeMapping[strings.ToLower(payloadFeeRecipient.String())]; ok { // check if there is a strict representative address for the payload's fee recipient
+				for _, wallet := range k2.validatorWallets() {
 					if strings.EqualFold(wallet.Address.String(), useRepAddress.String()) {
 						representative = wallet
 						representativeFound = true
@@ -504,10 +548,16 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 					"payloadFeeRecipient": payloadFeeRecipient.String(),
 					"representative":      representative.Address.String(),
 				}).Debug("using strict representative address for payload's fee recipient")
-			} else { // if no strict representative address for the payload's fee recipient then use the first wallet for the payload Proposer Registration
+			} else { // if no strict representative address for the payload's fee recipient then select a wallet for the payload Proposer Registration
 
-				// use the primary representative address for the payload to process the Proposer Registry registrations
-				representative = k2.cfg.ValidatorWallets[0]
+				// select the representative address for the payload to process the Proposer Registry registrations
+				var err error
+				representative, _, err = k2.selectRepresentative(payloadFeeRecipient, k2.validatorWallets(), true)
+				if err != nil {
+					k2.log.WithError(err).Error("failed to select a representative for this payload's fee recipient")
+					preChecksError.Store(err)
+					return
+				}
 
 				// if a global payout recipient has been set in the configuration and it doesnt match the
 				// payout recipient overwrite the payload's fee recipient and use the set global payout recipient
@@ -519,6 +569,15 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 
 			}
 
+			// a wallet derived from the mnemonic starts without a balance, skip it until it is funded
+			if k2.isDerivedWallet(representative.Address) {
+				if err := k2.checkDerivedWalletFunded(representative); err != nil {
+					k2.log.WithError(err).Warn("skipping registrations for a derived representative wallet that is not funded")
+					preChecksError.Store(err)
+					return
+				}
+			}
+
 			setPayoutRecipient = payloadFeeRecipient
 
 			preChecksComplete.Store(true)
@@ -547,37 +606,55 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 			// if there is a strict inclusion list and the validator is not found in it then skip the registration
 			// this is already ensured in the batch processing of the registrations but as a double check incase other
 			// methods use this function directly
-			if len(k2.strictInclusionList) > 0 {
-				if _, ok := k2.strictInclusionList[strings.ToLower(validator)]; !ok {
-					if _, ok := k2.strictInclusionList[strings.ToLower(payloadFeeRecipient)]; !ok {
+			if len(strictInclusionList) > 0 {
+				if _, ok := strictInclusionList[strings.ToLower(validator)]; !ok {
+					if _, ok := strictInclusionList[strings.ToLower(payloadFeeRecipient)]; !ok && !rules.included(validator) {
 						// if the validator or the fee recipient is not in the strict inclusion list
 						k2.log.WithField("validatorPubKey", validator).Debug("validator/fee recipient is not in the strict inclusion list")
+						k2.auditListCheck(validator, metrics.OperationProposerRegistry, audit.OutcomeSkipped, audit.ListInclusion, "", "validator/fee recipient is not in the strict inclusion list")
 						continue
 					}
 				}
 			}
 
-			if excludedValidator, ok := k2.exclusionList[strings.ToLower(validator)]; ok {
+			var list, match string
+			if excludedValidator, ok := exclusionList[strings.ToLower(validator)]; ok {
+				list, match = audit.ListExclusion, audit.MatchPubKey
 				if !excludedValidator.ProposerRegistration { // If the excluded validator is not allowed to be registered in the Proposer Registry
 					k2.log.WithField("validatorPubKey", validator).Debug("exclusion list check: validator is excluded from Proposer Registry registration by its BLS key")
+					k2.auditListCheck(validator, metrics.OperationProposerRegistry, audit.OutcomeSkipped, list, match, "excluded from Proposer Registry registration by its BLS key")
 					continue
 				}
-			} else if excludedValidator, ok := k2.exclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+			} else if excludedValidator, ok := exclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+				list, match = audit.ListExclusion, audit.MatchFeeRecipient
 				if !excludedValidator.ProposerRegistration { // If the excluded fee recipient group is not allowed to be registered in the Proposer Registry
 					k2.log.WithField("validatorPubKey", validator).Debug("exclusion list check; validator is excluded from Proposer Registry registration by its fee recipient")
+					k2.auditListCheck(validator, metrics.OperationProposerRegistry, audit.OutcomeSkipped, list, match, "excluded from Proposer Registry registration by its fee recipient")
 					continue
 				}
-			} else if includedValidator, ok := k2.strictInclusionList[strings.ToLower(validator)]; ok {
+			} else if includedValidator, ok := strictInclusionList[strings.ToLower(validator)]; ok {
+				list, match = audit.ListInclusion, audit.MatchPubKey
 				if !includedValidator.ProposerRegistration { // If the included validator is not allowed to be registered in the Proposer Registry
 					k2.log.WithField("validatorPubKey", validator).Debug("inclusion list check: validator is excluded from Proposer Registry registration by its BLS key")
+					k2.auditListCheck(validator, metrics.OperationProposerRegistry, audit.OutcomeSkipped, list, match, "excluded from Proposer Registry registration by its BLS key")
 					continue
 				}
-			} else if includedValidator, ok := k2.strictInclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+			} else if includedValidator, ok := strictInclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+				list, match = audit.ListInclusion, audit.MatchFeeRecipient
 				if !includedValidator.ProposerRegistration { // If the included fee recipient group is not allowed to be registered in the Proposer Registry
 					k2.log.WithField("validatorPubKey", validator).Debug("inclusion list check; validator is excluded from Proposer Registry registration by its fee recipient")
+					k2.auditListCheck(validator, metrics.OperationProposerRegistry, audit.OutcomeSkipped, list, match, "excluded from Proposer Registry registration by its fee recipient")
+					continue
+				}
+			} else if rule, ok := rules.match(validator); ok {
+				list, match = rule.list, audit.MatchRule
+				if !rule.filter.ProposerRegistration { // If the validators matched by the rule are not allowed to be registered in the Proposer Registry
+					k2.log.WithFields(logrus.Fields{"validatorPubKey": validator, "rule": rule.filter.Rule}).Debug("list rule check: validator is excluded from Proposer Registry registration by a rule")
+					k2.auditListCheck(validator, metrics.OperationProposerRegistry, audit.OutcomeSkipped, list, match, fmt.Sprintf("excluded from Proposer Registry registration by the %s list rule %s", rule.list, rule.filter.Rule))
 					continue
 				}
 			}
+			k2.auditListCheck(validator, metrics.OperationProposerRegistry, audit.OutcomeAllowed, list, match, "")
 			registrationsToProcess[validator] = payloadMap[validator]
 		} else {
 			alreadyRegisteredMap[validator] = k2common.K2ValidatorRegistration{
@@ -655,9 +732,9 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 						payloadFeeRecipient := payloadMap[validator].Message.FeeRecipient.String()
 
 						// if there is a strict inclusion list and the validator is not found in it then ignore this error
-						if len(k2.strictInclusionList) > 0 {
-							if _, ok := k2.strictInclusionList[strings.ToLower(validator)]; !ok {
-								if _, ok := k2.strictInclusionList[strings.ToLower(payloadFeeRecipient)]; !ok {
+						if len(strictInclusionList) > 0 {
+							if _, ok := strictInclusionList[strings.ToLower(validator)]; !ok {
+								if _, ok := strictInclusionList[strings.ToLower(payloadFeeRecipient)]; !ok && !rules.included(validator) {
 									// if the validator or the fee recipient is not in the strict inclusion list
 									k2.log.WithField("validatorPubKey", validator).Debug("validator/fee recipient is not in the strict inclusion list")
 									continue
@@ -665,22 +742,26 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 							}
 						}
 
-						if excludedValidator, ok := k2.exclusionList[strings.ToLower(validator)]; ok {
+						if excludedValidator, ok := exclusionList[strings.ToLower(validator)]; ok {
 							if excludedValidator.ProposerRegistration { // If the excluded validator is allowed to be registered in the Proposer Registry
 								k2.log.WithField("validatorPubKey", validator).Errorf("exclusion list check: validator is not registered in the Proposer Registry and is not being handled by the registrationToProcess")
 							} // else validator is excluded from Proposer Registry registration
-						} else if excludedValidator, ok := k2.exclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+						} else if excludedValidator, ok := exclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
 							if excludedValidator.ProposerRegistration { // If the excluded fee recipient group is allowed to be registered in the Proposer Registry
 								k2.log.WithField("validatorPubKey", validator).Errorf("exclusion list check: validator is not registered in the Proposer Registry and is not being handled by the registrationToProcess")
 							} // else fee recipient group is excluded from Proposer Registry registration
-						} else if includedValidator, ok := k2.strictInclusionList[strings.ToLower(validator)]; ok {
+						} else if includedValidator, ok := strictInclusionList[strings.ToLower(validator)]; ok {
 							if includedValidator.ProposerRegistration { // If the included validator is allowed to be registered in the Proposer Registry
 								k2.log.WithField("validatorPubKey", validator).Errorf("inclusion list check: validator is not registered in the Proposer Registry and is not being handled by the registrationToProcess")
 							} // else validator is excluded from Proposer Registry registration
-						} else if includedValidator, ok := k2.strictInclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+						} else if includedValidator, ok := strictInclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
 							if includedValidator.ProposerRegistration { // If the included fee recipient group is allowed to be registered in the Proposer Registry
 								k2.log.WithField("validatorPubKey", validator).Errorf("inclusion list check: validator is not registered in the Proposer Registry and is not being handled by the registrationToProcess")
 							} // else fee recipient group is excluded from Proposer Registry registration
+						} else if rule, ok := rules.match(validator); ok {
+							if rule.filter.ProposerRegistration { // If the validators matched by the rule are allowed to be registered in the Proposer Registry
+								k2.log.WithField("validatorPubKey", validator).Errorf("list rule check: validator is not registered in the Proposer Registry and is not being handled by the registrationToProcess")
+							} // else validators matched by the rule are excluded from Proposer Registry registration
 						} else {
 							if (!strings.EqualFold(setPayoutRecipient.String(), payload[0].Message.FeeRecipient.String()) && setPayoutRecipient != common.Address{} && k2.cfg.Web3SignerUrl != nil) {
 								// if the payout recipient has been set and is different from the one in the payload
@@ -715,38 +796,56 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 					payloadFeeRecipient := payloadMap[validator].Message.FeeRecipient.String()
 
 					// if there is a strict inclusion list and the validator is not found in it then skip the native delegation
-					if len(k2.strictInclusionList) > 0 {
-						if _, ok := k2.strictInclusionList[strings.ToLower(validator)]; !ok {
-							if _, ok := k2.strictInclusionList[strings.ToLower(payloadFeeRecipient)]; !ok {
+					if len(strictInclusionList) > 0 {
+						if _, ok := strictInclusionList[strings.ToLower(validator)]; !ok {
+							if _, ok := strictInclusionList[strings.ToLower(payloadFeeRecipient)]; !ok && !rules.included(validator) {
 								// if the validator or the fee recipient is not in the strict inclusion list
 								k2.log.WithField("validatorPubKey", validator).Debug("validator/fee recipient is not in the strict inclusion list")
+								k2.auditListCheck(validator, metrics.OperationNativeDelegation, audit.OutcomeSkipped, audit.ListInclusion, "", "validator/fee recipient is not in the strict inclusion list")
 								continue
 							}
 						}
 					}
 
 					// check if the validator is excluded from native delegation, before adding it as a registration to process
-					if excludedValidator, ok := k2.exclusionList[strings.ToLower(validator)]; ok {
+					var list, match string
+					if excludedValidator, ok := exclusionList[strings.ToLower(validator)]; ok {
+						list, match = audit.ListExclusion, audit.MatchPubKey
 						if !excludedValidator.NativeDelegation { // If the excluded validator is not allowed to be natively delegated
 							k2.log.WithField("validatorPubKey", validator).Debug("exclusion list check: validator is excluded from native delegation")
+							k2.auditListCheck(validator, metrics.OperationNativeDelegation, audit.OutcomeSkipped, list, match, "excluded from native delegation by its BLS key")
 							continue
 						}
-					} else if excludedValidator, ok := k2.exclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+					} else if excludedValidator, ok := exclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+						list, match = audit.ListExclusion, audit.MatchFeeRecipient
 						if !excludedValidator.NativeDelegation { // If the excluded fee recipient group is not allowed to be natively delegated
 							k2.log.WithField("validatorPubKey", validator).Debug("exclusion list check: validator is excluded from native delegation")
+							k2.auditListCheck(validator, metrics.OperationNativeDelegation, audit.OutcomeSkipped, list, match, "excluded from native delegation by its fee recipient")
 							continue
 						}
-					} else if includedValidator, ok := k2.strictInclusionList[strings.ToLower(validator)]; ok {
+					} else if includedValidator, ok := strictInclusionList[strings.ToLower(validator)]; ok {
+						list, match = audit.ListInclusion, audit.MatchPubKey
 						if !includedValidator.NativeDelegation { // If the included validator is not allowed to be natively delegated
 							k2.log.WithField("validatorPubKey", validator).Debug("inclusion list check: validator is excluded from native delegation")
+							k2.auditListCheck(validator, metrics.OperationNativeDelegation, audit.OutcomeSkipped, list, match, "excluded from native delegation by its BLS key")
 							continue
 						}
-					} else if includedValidator, ok := k2.strictInclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+					} else if includedValidator, ok := strictInclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+						list, match = audit.ListInclusion, audit.MatchFeeRecipient
 						if !includedValidator.NativeDelegation { // If the included fee recipient group is not allowed to be natively delegated
 							k2.log.WithField("validatorPubKey", validator).Debug("inclusion list check: validator is excluded from native delegation")
+							k2.auditListCheck(validator, metrics.OperationNativeDelegation, audit.OutcomeSkipped, list, match, "excluded from native delegation by its fee recipient")
+							continue
+						}
+					} else if rule, ok := rules.match(validator); ok {
+						list, match = rule.list, audit.MatchRule
+						if !rule.filter.NativeDelegation { // If the validators matched by the rule are not allowed to be natively delegated
+							k2.log.WithFields(logrus.Fields{"validatorPubKey": validator, "rule": rule.filter.Rule}).Debug("list rule check: validator is excluded from native delegation by a rule")
+							k2.auditListCheck(validator, metrics.OperationNativeDelegation, audit.OutcomeSkipped, list, match, fmt.Sprintf("excluded from native delegation by the %s list rule %s", rule.list, rule.filter.Rule))
 							continue
 						}
 					}
+					k2.auditListCheck(validator, metrics.OperationNativeDelegation, audit.OutcomeAllowed, list, match, "")
 
 					// check if the representative address from the proposrRegistry for this validator is the same as the one selected
 					if registration.RepresentativeAddress != representative.Address {
@@ -759,6 +858,9 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 								"representativeAddress":    registration.RepresentativeAddress.String(),
 								"configuredRepresentative": representative.Address.String(),
 							}).Debugf("validator is already registered in the Proposer Registry, but the representative address is not the same as the one configured for this registration")
+						k2.auditValidators([]string{validator}, representative.Address, audit.DecisionRepresentative, audit.OutcomeSkipped, "registered in the Proposer Registry under a different representative", map[string]any{
+							"registeredRepresentative": registration.RepresentativeAddress.String(),
+						})
 						k2UnsuppportedCount++
 						continue
 					}
@@ -771,6 +873,14 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 					// the only field not set is the ecdsa sig for this already registered validator that would be needed
 					// for further k2 native delegation
 
+					// Check if the node operator contract permits this validator to be natively delegated
+					if permitted, ok := permittedValidators[validator]; ok && !permitted {
+						k2.log.WithField("validatorPubKey", validator).Debug("validator is already registered in the Proposer Registry, but the node operator contract does not permit it to be natively delegated")
+						k2.auditValidators([]string{validator}, representative.Address, audit.DecisionCapacityCheck, audit.OutcomeSkipped, "node operator contract does not permit the key to be natively delegated", capacityData(globalMaxNativeDelegation, currentGlobalNativeDelegation, individualMaxNativeDelegation, currentIndividualNativeDelegation))
+						k2UnsuppportedCount++
+						continue
+					}
+
 					// Check if there is capacity for this validator to be natively delegated
 					// if the global max native delegation has been reached then check the individual max native delegation
 					if globalMaxNativeDelegation != nil && currentGlobalNativeDelegation != nil && globalMaxNativeDelegation.Cmp(currentGlobalNativeDelegation) <= 0 {
@@ -785,6 +895,7 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 									"individualMax":     individualMaxNativeDelegation.String(),
 									"currentIndividual": currentIndividualNativeDelegation.String(),
 								}).Debugf("validator is already registered in the Proposer Registry, but the global and individual max native delegation has been reached")
+								k2.auditValidators([]string{validator}, representative.Address, audit.DecisionCapacityCheck, audit.OutcomeSkipped, "global and individual max native delegation reached", capacityData(globalMaxNativeDelegation, currentGlobalNativeDelegation, individualMaxNativeDelegation, currentIndividualNativeDelegation))
 								k2UnsuppportedCount++
 								continue
 							}
@@ -792,10 +903,12 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 							// validator representative address is not in the inclusion list
 							// so cannot natively delegate this validator since the max has been reached
 							k2.log.WithField("validatorPubKey", validator).Debug("validator representative address is not in the inclusion list")
+							k2.auditValidators([]string{validator}, representative.Address, audit.DecisionCapacityCheck, audit.OutcomeSkipped, "global max native delegation reached and representative is not in the inclusion list", capacityData(globalMaxNativeDelegation, currentGlobalNativeDelegation, nil, nil))
 							k2UnsuppportedCount++
 							continue
 						}
 					}
+					k2.auditValidators([]string{validator}, representative.Address, audit.DecisionCapacityCheck, audit.OutcomeAllowed, "", capacityData(globalMaxNativeDelegation, currentGlobalNativeDelegation, individualMaxNativeDelegation, currentIndividualNativeDelegation))
 
 					// Once here, means we can natively delegate this validator from the already registered map for Proposer Registry
 
@@ -832,6 +945,7 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 								k2.log.WithFields(logrus.Fields{
 									"validatorPubKey": validator,
 								}).Debug("could not sign the registration message for the validator for the new payout recipient")
+								k2.auditValidators([]string{validator}, representative.Address, audit.DecisionWeb3SignerResign, audit.OutcomeSkipped, "web3signer not configured to re-sign for the selected payout recipient", resignAuditData(validPayload.Message.FeeRecipient.String(), setPayoutRecipient))
 								k2UnsuppportedCount++
 								continue
 							}
@@ -850,6 +964,7 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 								k2.log.WithFields(logrus.Fields{
 									"validatorPubKey": validator,
 								}).Debug("could not sign the registration message for the validator for the new payout recipient")
+								k2.auditValidators([]string{validator}, representative.Address, audit.DecisionWeb3SignerResign, audit.OutcomeSkipped, "validator is not signable by the web3signer", resignAuditData(validPayload.Message.FeeRecipient.String(), setPayoutRecipient))
 								k2UnsuppportedCount++
 								continue
 							} else {
@@ -964,22 +1079,26 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 			proposerRegistrations = append(proposerRegistrations, processingDetails)
 			if k2.cfg.K2LendingContractAddress != (common.Address{}) {
 
-				if excludedValidator, ok := k2.exclusionList[strings.ToLower(validator)]; ok {
+				if excludedValidator, ok := exclusionList[strings.ToLower(validator)]; ok {
 					if excludedValidator.NativeDelegation { // If the excluded validator is allowed to be natively delegated
 						k2Registrations = append(k2Registrations, processingDetails)
 					}
-				} else if excludedValidator, ok := k2.exclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+				} else if excludedValidator, ok := exclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
 					if excludedValidator.NativeDelegation { // If the excluded fee recipient group is allowed to be natively delegated
 						k2Registrations = append(k2Registrations, processingDetails)
 					}
-				} else if includedValidator, ok := k2.strictInclusionList[strings.ToLower(validator)]; ok {
+				} else if includedValidator, ok := strictInclusionList[strings.ToLower(validator)]; ok {
 					if includedValidator.NativeDelegation { // If the included validator is allowed to be natively delegated
 						k2Registrations = append(k2Registrations, processingDetails)
 					}
-				} else if includedValidator, ok := k2.strictInclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
+				} else if includedValidator, ok := strictInclusionList[strings.ToLower(payloadFeeRecipient)]; ok {
 					if includedValidator.NativeDelegation { // If the included fee recipient group is allowed to be natively delegated
 						k2Registrations = append(k2Registrations, processingDetails)
 					}
+				} else if rule, ok := rules.match(validator); ok {
+					if rule.filter.NativeDelegation { // If the validators matched by the rule are allowed to be natively delegated
+						k2Registrations = append(k2Registrations, processingDetails)
+					}
 				}
 			}
 		}
@@ -993,12 +1112,25 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 		tx, err := k2.eth1.BatchRegisterValidators(proposerRegistrations)
 		if err != nil {
 			k2.log.WithError(err).Error("failed to register validators in the Proposer Registry")
+			k2.auditTransaction(metrics.OperationProposerRegistry, representative.Address, registrationPubKeys(proposerRegistrations), nil, err)
+			k2.notify(notifier.EventTransactionFailed, []common.Address{representative.Address}, map[string]any{
+				"operation":  metrics.OperationProposerRegistry,
+				"validators": registrationPubKeys(proposerRegistrations),
+				"error":      err.Error(),
+			})
 			return nil, err
 		}
 		k2.log.WithFields(logrus.Fields{
 			"newRegistrations": len(proposerRegistrations),
 			"txHash":           tx.Hash().String(),
 		}).Info("Proposer Registry registration transaction completed")
+		k2.auditTransaction(metrics.OperationProposerRegistry, representative.Address, registrationPubKeys(proposerRegistrations), tx, nil)
+		metrics.BatchesSent.WithLabelValues(representative.Address.String(), metrics.OperationProposerRegistry).Inc()
+		metrics.RegistrationsProcessed.WithLabelValues(representative.Address.String(), metrics.OperationProposerRegistry).Add(float64(len(proposerRegistrations)))
+		k2.notify(notifier.EventRegistration, []common.Address{representative.Address}, map[string]any{
+			"validators": registrationPubKeys(proposerRegistrations),
+			"txHash":     tx.Hash().String(),
+		})
 		// update the proposerRegistrySuccess status here as no error was returned from execution
 		for _, registration := range proposerRegistrations {
 			r := processValidators[registration.SignedValidatorRegistration.Message.Pubkey.String()]
@@ -1009,6 +1141,22 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 		k2.log.WithField("alreadyRegistered", proposerRegistryAlreadyRegisteredCount).Info("No new validators to register in the Proposer Registry")
 	}
 
+	if k2.cfg.K2LendingContractAddress != (common.Address{}) {
+		// defer the native delegations the delegation policy of the representative does not allow now,
+		// queueing them in place of any earlier deferral of the validators in this batch
+		var deferredRegistrations []k2common.K2ValidatorRegistration
+		var deferReason string
+		if len(k2Registrations) > 0 {
+			// never natively delegate keys the node operator contract does not permit, kicked validators or to a banned representative
+			k2Registrations = k2.filterBlockedDelegations(representative.Address, k2Registrations, permittedValidators)
+			k2Registrations, deferredRegistrations, deferReason = k2.applyDelegationPolicy(representative.Address, k2Registrations)
+			if len(deferredRegistrations) > 0 {
+				k2.deferDelegations(representative.Address, deferredRegistrations, deferReason)
+			}
+		}
+		k2.updateDeferredDelegations(validators, representative.Address, deferredRegistrations, deferReason)
+	}
+
 	if len(k2Registrations) > 0 && k2.cfg.K2LendingContractAddress != (common.Address{}) {
 		k2.log.WithFields(logrus.Fields{
 			"k2Registrations":   len(k2Registrations),
@@ -1018,12 +1166,26 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 		tx, err := k2.eth1.K2BatchNativeDelegation(k2Registrations)
 		if err != nil {
 			k2.log.WithError(err).Error("failed to register validators in the K2 contract")
+			k2.auditTransaction(metrics.OperationNativeDelegation, representative.Address, registrationPubKeys(k2Registrations), nil, err)
+			k2.notify(notifier.EventTransactionFailed, []common.Address{representative.Address}, map[string]any{
+				"operation":  metrics.OperationNativeDelegation,
+				"validators": registrationPubKeys(k2Registrations),
+				"error":      err.Error(),
+			})
 			return nil, err
 		}
 		k2.log.WithFields(logrus.Fields{
 			"newRegistrations": len(k2Registrations),
 			"txHash":           tx.Hash().String(),
 		}).Info("K2 registration transaction completed")
+		k2.auditTransaction(metrics.OperationNativeDelegation, representative.Address, registrationPubKeys(k2Registrations), tx, nil)
+		k2.recordDelegations(representative.Address, registrationPubKeys(k2Registrations))
+		metrics.BatchesSent.WithLabelValues(representative.Address.String(), metrics.OperationNativeDelegation).Inc()
+		metrics.RegistrationsProcessed.WithLabelValues(representative.Address.String(), metrics.OperationNativeDelegation).Add(float64(len(k2Registrations)))
+		k2.notify(notifier.EventDelegation, []common.Address{representative.Address}, map[string]any{
+			"validators": registrationPubKeys(k2Registrations),
+			"txHash":     tx.Hash().String(),
+		})
 		// update the k2Register status here as no error was returned from execution
 		for _, registration := range k2Registrations {
 			r := processValidators[registration.SignedValidatorRegistration.Message.Pubkey.String()]
@@ -1052,6 +1214,11 @@ func (k2 *K2Service) processValidatorRegistrations(payload []apiv1.SignedValidat
 				"newRegistrationsInK2":               len(k2Registrations),
 			},
 		).Info("Validator registrations successfully processed")
+		k2.publish(stream.TopicRegistrations, stream.EventRegistrationsProcessed, []common.Address{representative.Address}, map[string]any{
+			"registrations":     results,
+			"alreadyRegistered": proposerRegistryAlreadyRegisteredCount,
+			"unsupported":       k2UnsuppportedCount,
+		})
 	}
 
 	return results, nil
@@ -1064,14 +1231,14 @@ func (k2 *K2Service) processClaim(represenatives []common.Address) ([]k2common.K
 	if k2.cfg.K2LendingContractAddress == (common.Address{}) || k2.cfg.K2NodeOperatorContractAddress == (common.Address{}) {
 		// module not configured to run
 		return nil, fmt.Errorf("module not configured to run K2 contract operations")
-	} else if k2.cfg.BalanceVerificationUrl == nil {
+	} else if len(k2.cfg.BalanceVerificationUrls) == 0 {
 		// module not configured to run
 		return nil, fmt.Errorf("module not configured to run balance verification operations for claims")
 	}
 
 	var nodeRunnersInfo map[common.Address]k2common.NodeRunnerInfo = make(map[common.Address]k2common.NodeRunnerInfo)
 	var totalClaimed *big.Float = big.NewFloat(0)
-	var claimsToProcess []k2common.K2Claim
+	var claimsToProcess, failedClaims []k2common.K2Claim
 
 	// Get the claimmable rewards for the provided node runners
 	k2.log.WithField("representatives", len(represenatives)).Info("Checking K2 claims")
@@ -1085,7 +1252,8 @@ func (k2 *K2Service) processClaim(represenatives []common.Address) ([]k2common.K
 	var delegatedValidators map[phase0.BLSPubKey]k2common.DelegatedValidator = make(map[phase0.BLSPubKey]k2common.DelegatedValidator)
 	var blsKeys []phase0.BLSPubKey
 
-	allNodeRunnersData, err := k2.subgraph.GetValidatorsByRepresentative(represenatives, 1)
+	// fetch enough keys that each representative has one that was not kicked, if it still has any delegated
+	allNodeRunnersData, err := k2.subgraph.GetValidatorsByRepresentative(represenatives, 1+k2.maxKickedValidators(represenatives))
 	if err != nil {
 		k2.log.WithError(err).Error("failed to get delegated validators")
 		return nil, err
@@ -1101,6 +1269,24 @@ func (k2 *K2Service) processClaim(represenatives []common.Address) ([]k2common.K
 			continue
 		}
 
+		if k2.isRepresentativeBanned(rep) {
+			k2.log.WithField("representative", rep.String()).Warn("representative is banned by the K2 contract, skipping claim")
+			continue
+		}
+
+		// kicked validators are no longer delegated so cannot report the effective balance for the claim,
+		// the first validator that was not kicked reports it
+		validators := nodeRunnerData.BlsPublicKeys[:0]
+		for _, validator := range nodeRunnerData.BlsPublicKeys {
+			if k2.isValidatorKicked(validator.Id.String()) {
+				k2.log.WithField("validatorPubKey", validator.Id.String()).Debug("validator was kicked from the K2 contract, not using it for the claim")
+				continue
+			}
+			validators = append(validators, validator)
+			break
+		}
+		nodeRunnerData.BlsPublicKeys = validators
+
 		if len(nodeRunnerData.BlsPublicKeys) == 0 {
 			k2.log.WithField("representative", rep.String()).Debug("representative has no delegated validators")
 			continue
@@ -1137,6 +1323,16 @@ func (k2 *K2Service) processClaim(represenatives []common.Address) ([]k2common.K
 		return nil, err
 	}
 
+	// reject the reports with an invalid signature before they are submitted in a claim
+	invalidReports, err := k2.verifyEffectiveBalanceReports(effectiveBalances, verifiedEffectiveBalances)
+	if err != nil {
+		k2.log.WithError(err).Error("failed to verify the effective balance reports")
+		return nil, err
+	}
+	for _, validator := range invalidReports {
+		delete(delegatedValidators, validator)
+	}
+
 	for _, validator := range blsKeys {
 		if delegatedValidator, ok := delegatedValidators[validator]; ok {
 			delegatedValidator.EffectiveBalance = effectiveBalances[validator]
@@ -1147,6 +1343,14 @@ func (k2 *K2Service) processClaim(represenatives []common.Address) ([]k2common.K
 		}
 	}
 
+	for representative, info := range nodeRunnersInfo {
+		if len(info.DelegatedValidators) == 0 {
+			// every effective balance report of the representative was rejected
+			k2.log.WithField("representative", representative.String()).Warn("representative has no valid effective balance report, skipping claim")
+			delete(nodeRunnersInfo, representative)
+		}
+	}
+
 	if len(nodeRunnersInfo) > 0 { // if there are validators with at least 1 delegated validator and claimable rewards
 
 		for _, info := range nodeRunnersInfo {
@@ -1164,16 +1368,59 @@ func (k2 *K2Service) processClaim(represenatives []common.Address) ([]k2common.K
 			"amount": totalClaimed.String() + " KETH",
 		}).Infof("Processing %v claims through K2 module", len(claimsToProcess))
 
-		tx, err := k2.eth1.BatchK2ClaimRewards(claimsToProcess)
+		// representatives that delegated their claims to a recipient claim to it themselves, the rest are claimed in a batch
+		claimRecipients, err := k2.eth1.K2DelegatedClaimRecipients(claimRepresentatives(claimsToProcess))
 		if err != nil {
-			k2.log.WithError(err).Error("failed to claim rewards from the K2 contract")
+			k2.log.WithError(err).Error("failed to get the delegated claim recipients of the representatives")
 			return nil, err
 		}
-		k2.log.WithFields(logrus.Fields{
-			"claims": len(claimsToProcess),
-			"amount": totalClaimed.String() + " KETH",
-			"txHash": tx.Hash().String(),
-		}).Info("K2 claim transaction completed")
+		var batchClaims, delegatedClaims []k2common.K2Claim
+		for _, claim := range claimsToProcess {
+			if recipient := claimRecipients[claim.RepresentativeAddress]; recipient != (common.Address{}) {
+				claim.ClaimRecipient = &recipient
+				delegatedClaims = append(delegatedClaims, claim)
+			} else {
+				batchClaims = append(batchClaims, claim)
+			}
+		}
+		claimsToProcess = nil
+
+		if len(batchClaims) > 0 {
+			batchAmount := claimedKETH(batchClaims)
+			tx, err := k2.eth1.BatchK2ClaimRewards(batchClaims)
+			if err != nil {
+				k2.log.WithError(err).Error("failed to claim rewards from the K2 contract")
+				for _, claim := range batchClaims {
+					k2.auditTransaction(metrics.OperationClaim, claim.RepresentativeAddress, []string{claim.ValidatorPubKey.String()}, nil, err)
+				}
+				k2.notify(notifier.EventTransactionFailed, claimRepresentatives(batchClaims), map[string]any{
+					"operation": metrics.OperationClaim,
+					"claims":    batchClaims,
+					"error":     err.Error(),
+				})
+				return nil, err
+			}
+			k2.log.WithFields(logrus.Fields{
+				"claims": len(batchClaims),
+				"amount": batchAmount.String() + " KETH",
+				"txHash": tx.Hash().String(),
+			}).Info("K2 claim transaction completed")
+			k2.recordClaims(batchClaims, tx)
+			k2.notify(notifier.EventClaim, claimRepresentatives(batchClaims), map[string]any{
+				"claims": batchClaims,
+				"amount": batchAmount.String() + " KETH",
+				"txHash": tx.Hash().String(),
+			})
+			claimsToProcess = append(claimsToProcess, batchClaims...)
+		}
+
+		claimed, failed := k2.claimDelegated(delegatedClaims)
+		claimsToProcess = append(claimsToProcess, claimed...)
+		failedClaims = failed
+
+		totalClaimed = claimedKETH(claimsToProcess)
+
+		k2.attachKETHBalances(claimsToProcess)
 	} else {
 		k2.log.Info("No node runners with claimable rewards")
 		return nil, nil
@@ -1182,20 +1429,87 @@ func (k2 *K2Service) processClaim(represenatives []common.Address) ([]k2common.K
 	k2.log.WithFields(logrus.Fields{
 		"claimsRequested:":          len(represenatives),
 		"qualifiedClaimsProcessed:": len(claimsToProcess),
+		"failedClaims":              len(failedClaims),
 		"amount":                    totalClaimed.String() + " KETH",
 	}).Info("K2 claims successfully processed")
 
-	return claimsToProcess, nil
+	return append(claimsToProcess, failedClaims...), nil
+}
+
+// claimDelegated claims each claim to its delegated recipient in a transaction of its own, a failed claim does not
+// stop the others and is returned with the reason it failed
+func (k2 *K2Service) claimDelegated(delegatedClaims []k2common.K2Claim) (claimed, failed []k2common.K2Claim) {
+
+	for _, claim := range delegatedClaims {
+		claimAmount := claimedKETH([]k2common.K2Claim{claim})
+		tx, err := k2.eth1.K2DelegatedClaim(claim)
+		if err != nil {
+			k2.log.WithError(err).WithField("representative", claim.RepresentativeAddress.String()).Error("failed to claim rewards to the delegated claim recipient")
+			k2.auditTransaction(metrics.OperationClaim, claim.RepresentativeAddress, []string{claim.ValidatorPubKey.String()}, nil, err)
+			k2.notify(notifier.EventTransactionFailed, []common.Address{claim.RepresentativeAddress}, map[string]any{
+				"operation": metrics.OperationClaim,
+				"claims":    []k2common.K2Claim{claim},
+				"error":     err.Error(),
+			})
+			claim.Error = err.Error()
+			failed = append(failed, claim)
+			continue
+		}
+		k2.log.WithFields(logrus.Fields{
+			"representative": claim.RepresentativeAddress.String(),
+			"claimRecipient": claim.ClaimRecipient.String(),
+			"amount":         claimAmount.String() + " KETH",
+			"txHash":         tx.Hash().String(),
+		}).Info("K2 delegated claim transaction completed")
+		k2.recordClaims([]k2common.K2Claim{claim}, tx)
+		k2.notify(notifier.EventClaim, []common.Address{claim.RepresentativeAddress}, map[string]any{
+			"claims": []k2common.K2Claim{claim},
+			"amount": claimAmount.String() + " KETH",
+			"txHash": tx.Hash().String(),
+		})
+		claimed = append(claimed, claim)
+	}
+
+	return claimed, failed
+}
+
+// recordClaims audits the claims sent in the transaction and adds them to the claim metrics, the batch is labelled
+// with the wallet that sent it rather than the representatives claimed for
+func (k2 *K2Service) recordClaims(claims []k2common.K2Claim, tx *types.Transaction) {
+	sender, err := k2.eth1.TransactionSender(tx)
+	if err != nil {
+		k2.log.WithError(err).Warn("failed to recover the sender of the claim transaction")
+	} else {
+		metrics.BatchesSent.WithLabelValues(sender.String(), metrics.OperationClaim).Inc()
+	}
+	for _, claim := range claims {
+		k2.auditTransaction(metrics.OperationClaim, claim.RepresentativeAddress, []string{claim.ValidatorPubKey.String()}, tx, nil)
+		claimedAmount, _ := big.NewFloat(0).Quo(big.NewFloat(float64(claim.ClaimAmount)), big.NewFloat(math.Pow(10, float64(k2common.KETHDecimals)))).Float64()
+		metrics.ClaimsExecuted.WithLabelValues(claim.RepresentativeAddress.String()).Inc()
+		metrics.KETHClaimed.WithLabelValues(claim.RepresentativeAddress.String()).Add(claimedAmount)
+	}
+}
+
+// claimedKETH returns the total KETH of the claims
+func claimedKETH(claims []k2common.K2Claim) *big.Float {
+	total := big.NewFloat(0)
+	for _, claim := range claims {
+		total.Add(total, big.NewFloat(0).Quo(big.NewFloat(float64(claim.ClaimAmount)), big.NewFloat(math.Pow(10, float64(k2common.KETHDecimals)))))
+	}
+	return total
 }
 
 func (k2 *K2Service) processExit(blsKey phase0.BLSPubKey) (res k2common.K2Exit, err error) {
+
+	defer k2.trackPendingJobs(jobExits, 1)()
+
 	k2.lock.Lock()
 	defer k2.lock.Unlock()
 
 	if k2.cfg.K2LendingContractAddress == (common.Address{}) || k2.cfg.K2NodeOperatorContractAddress == (common.Address{}) {
 		// module not configured to run
 		return res, fmt.Errorf("module not configured to run K2 contract operations")
-	} else if k2.cfg.BalanceVerificationUrl == nil {
+	} else if len(k2.cfg.BalanceVerificationUrls) == 0 {
 		// module not configured to run
 		return res, fmt.Errorf("module not configured to run balance verification operations for exits")
 	}
@@ -1214,8 +1528,9 @@ func (k2 *K2Service) processExit(blsKey phase0.BLSPubKey) (res k2common.K2Exit,
 		return res, fmt.Errorf("validator is not registered in the K2 contract")
 	}
 
-	var representative k2common.ValidatorWallet = k2.cfg.ValidatorWallets[0]
-	for _, wallet := range k2.cfg.ValidatorWallets {
+	wallets := k2.validatorWallets()
+	var representative k2common.ValidatorWallet = wallets[0]
+	for _, wallet := range wallets {
 		if strings.EqualFold(wallet.Address.String(), representativeAddress) {
 			representative = wallet
 			break
@@ -1252,6 +1567,16 @@ func (k2 *K2Service) processExit(blsKey phase0.BLSPubKey) (res k2common.K2Exit,
 		return res, fmt.Errorf("failed to get verified effective balance for validator")
 	}
 
+	// reject an invalid report before it is submitted in the exit
+	invalidReports, err := k2.verifyEffectiveBalanceReports(report, verifiedEffectiveBalances)
+	if err != nil {
+		k2.log.WithError(err).Error("failed to verify the effective balance report for validator")
+		return res, fmt.Errorf("failed to verify the effective balance report for validator: %w", err)
+	}
+	if len(invalidReports) > 0 {
+		return res, fmt.Errorf("balance verifier returned an invalid effective balance report signature for validator")
+	}
+
 	res.ECDSASignature = verifiedEffectiveBalances[blsKey]
 
 	k2.log.WithFields(logrus.Fields{
@@ -1261,12 +1586,24 @@ func (k2 *K2Service) processExit(blsKey phase0.BLSPubKey) (res k2common.K2Exit,
 	tx, err := k2.eth1.K2Exit(res)
 	if err != nil {
 		k2.log.WithError(err).Error("failed to exit the validator from the K2 contract")
+		k2.auditTransaction(metrics.OperationExit, representative.Address, []string{blsKey.String()}, nil, err)
+		k2.notify(notifier.EventTransactionFailed, []common.Address{representative.Address}, map[string]any{
+			"operation": metrics.OperationExit,
+			"validator": blsKey.String(),
+			"error":     err.Error(),
+		})
 		return res, fmt.Errorf("failed to exit the validator from the K2 contract: %w", err)
 	}
 	k2.log.WithFields(logrus.Fields{
 		"validator": blsKey.String(),
 		"txHash":    tx.Hash().String(),
 	}).Info("K2 validator exit transaction completed")
+	k2.auditTransaction(metrics.OperationExit, representative.Address, []string{blsKey.String()}, tx, nil)
+	metrics.BatchesSent.WithLabelValues(representative.Address.String(), metrics.OperationExit).Inc()
+	k2.notify(notifier.EventExit, []common.Address{representative.Address}, map[string]any{
+		"validator": blsKey.String(),
+		"txHash":    tx.Hash().String(),
+	})
 	// update the exit status here as no error was returned from execution
 	res.ExitSuccess = true
 
@@ -1382,6 +1719,9 @@ func (k2 *K2Service) getDelegatedValidators(representativeAddresses []common.Add
 }
 
 func (k2 *K2Service) changeK2NodeOperatorPayout(represenative common.Address, newPayoutAddress common.Address) (k2common.ChangedK2PayoutRepresentative, error) {
+
+	defer k2.trackPendingJobs(jobPayoutUpdates, 1)()
+
 	k2.lock.Lock()
 	defer k2.lock.Unlock()
 
@@ -1392,7 +1732,7 @@ func (k2 *K2Service) changeK2NodeOperatorPayout(represenative common.Address, ne
 
 	// check if the representative is a configured wallet
 	var walletFound bool
-	for _, wallet := range k2.cfg.ValidatorWallets {
+	for _, wallet := range k2.validatorWallets() {
 		if strings.EqualFold(wallet.Address.String(), represenative.String()) {
 			walletFound = true
 			break
@@ -1460,6 +1800,12 @@ func (k2 *K2Service) changeK2NodeOperatorPayout(represenative common.Address, ne
 	tx, err := k2.eth1.K2ChangeNodeOperatorPayoutAddress(represenative, newPayoutAddress)
 	if err != nil {
 		k2.log.WithError(err).Error("failed to change the K2 node operator payout address")
+		k2.auditTransaction(metrics.OperationPayoutUpdate, represenative, nil, nil, err)
+		k2.notify(notifier.EventTransactionFailed, []common.Address{represenative}, map[string]any{
+			"operation":       metrics.OperationPayoutUpdate,
+			"payoutRecipient": newPayoutAddress.String(),
+			"error":           err.Error(),
+		})
 		return k2common.ChangedK2PayoutRepresentative{}, fmt.Errorf("failed to change the K2 node operator payout address: %w", err)
 	}
 	k2.log.WithFields(logrus.Fields{
@@ -1467,6 +1813,13 @@ func (k2 *K2Service) changeK2NodeOperatorPayout(represenative common.Address, ne
 		"newPayout":      newPayoutAddress.String(),
 		"txHash":         tx.Hash().String(),
 	}).Info("K2 node operator payout address change transaction completed")
+	k2.auditTransaction(metrics.OperationPayoutUpdate, represenative, nil, tx, nil)
+	metrics.BatchesSent.WithLabelValues(represenative.String(), metrics.OperationPayoutUpdate).Inc()
+	k2.notify(notifier.EventPayoutChange, []common.Address{represenative}, map[string]any{
+		"previousPayoutRecipient": oldPayoutAddress.String(),
+		"newPayoutRecipient":      newPayoutAddress.String(),
+		"txHash":                  tx.Hash().String(),
+	})
 
 	return k2common.ChangedK2PayoutRepresentative{
 		RepresentativeAddress: represenative,
@@ -1480,10 +1833,12 @@ func (k2 *K2Service) changeK2NodeOperatorPayout(represenative common.Address, ne
 
 func (k2 *K2Service) batchProcessClaims(representativeAddresses []common.Address) ([]k2common.K2Claim, error) {
 
+	defer k2.trackPendingJobs(jobClaims, len(representativeAddresses))()
+
 	if k2.cfg.K2LendingContractAddress == (common.Address{}) {
 		// module not configured to run
 		return nil, fmt.Errorf("module not configured to run K2 contract operations")
-	} else if k2.cfg.BalanceVerificationUrl == nil {
+	} else if len(k2.cfg.BalanceVerificationUrls) == 0 {
 		// module not configured to run
 		return nil, fmt.Errorf("module not configured to run balance verification operations for claims")
 	}
@@ -1520,11 +1875,22 @@ func (k2 *K2Service) batchProcessValidatorRegistrations(payload []apiv1.SignedVa
 		return nil, nil
 	}
 
+	defer k2.trackPendingJobs(jobRegistrations, len(payload))()
+
+	_, strictInclusionList, representativeMapping := k2.listSnapshot()
+
 	strictProcessing := false
-	if len(k2.strictInclusionList) > 0 {
+	if len(strictInclusionList) > 0 {
 		strictProcessing = true
 	}
 
+	// gather what the strict inclusion list rules match the validators by
+	var rules ruleContext
+	if strictProcessing {
+		rules = k2.ruleSnapshot()
+		k2.gatherRuleFacts(&rules, payload)
+	}
+
 	var feeRecipientMapping = make(map[string][]apiv1.SignedValidatorRegistration)
 	var repSpecificBatches = make(map[string][]apiv1.SignedValidatorRegistration)
 	for _, reg := range payload {
@@ -1532,8 +1898,8 @@ func (k2 *K2Service) batchProcessValidatorRegistrations(payload []apiv1.SignedVa
 		// check if there is a strict inclusion list and if the validator is in the inclusion list
 		if strictProcessing {
 
-			if _, ok := k2.strictInclusionList[strings.ToLower(reg.Message.Pubkey.String())]; !ok {
-				if _, ok := k2.strictInclusionList[strings.ToLower(reg.Message.FeeRecipient.String())]; !ok {
+			if _, ok := strictInclusionList[strings.ToLower(reg.Message.Pubkey.String())]; !ok {
+				if _, ok := strictInclusionList[strings.ToLower(reg.Message.FeeRecipient.String())]; !ok && !rules.included(reg.Message.Pubkey.String()) {
 					// validator is not in the strict inclusion list and their fee recipient is not in the strict inclusion list
 					k2.log.WithFields(
 						logrus.Fields{
@@ -1545,21 +1911,18 @@ func (k2 *K2Service) batchProcessValidatorRegistrations(payload []apiv1.SignedVa
 			}
 		}
 
-		k2.lock.Lock()
-		if len(k2.representativeMapping) > 0 {
+		if len(representativeMapping) > 0 {
 			// check if the validator is in the representative mapping
 			// ignore fee recipient maping as the batch processing already groups
 			// by fee recipient
 			// need to group by representative address for specific validators if specified
-			if rep, ok := k2.representativeMapping[strings.ToLower(reg.Message.Pubkey.String())]; ok {
+			if rep, ok := representativeMapping[strings.ToLower(reg.Message.Pubkey.String())]; ok {
 				// if the validator is in the representative mapping then add to the repSpecificBatches
 				repSpecificBatches[rep.String()] = append(repSpecificBatches[rep.String()], reg)
-				k2.lock.Unlock()
 				continue
 				// ignore from the feeRecipientMapping
 			}
 		}
-		k2.lock.Unlock()
 
 		feeRecipientMapping[reg.Message.FeeRecipient.String()] = append(feeRecipientMapping[reg.Message.FeeRecipient.String()], reg)
 	}
@@ -1697,6 +2060,7 @@ func (k2 *K2Service) prepareRegistrations(toProcess map[string]apiv1.SignedValid
 					"payloadPayoutRecipient":  registration.Message.FeeRecipient.String(),
 					"selectedPayoutRecipient": payoutRecipient.String(),
 				}).Error("validator is not in the signable list so cannot generate a new signature, skipping validator")
+				k2.auditValidators([]string{validator}, representative, audit.DecisionWeb3SignerResign, audit.OutcomeSkipped, "validator is not signable by the web3signer", resignAuditData(registration.Message.FeeRecipient.String(), payoutRecipient))
 				continue
 			} else {
 				// validator is in the signable list so can generate a new signature
@@ -1721,8 +2085,12 @@ func (k2 *K2Service) prepareRegistrations(toProcess map[string]apiv1.SignedValid
 					)
 					if err != nil {
 						k2.log.WithError(err).Error("failed to sign registration with custom payout recipient")
+						k2.auditValidators([]string{validator}, representative, audit.DecisionWeb3SignerResign, audit.OutcomeFailed, err.Error(), resignAuditData(registration.Message.FeeRecipient.String(), payoutRecipient))
 						return nil, fmt.Errorf("failed to sign registration with custom payout recipient: %w", err)
 					}
+					data := resignAuditData(registration.Message.FeeRecipient.String(), payoutRecipient)
+					data["signature"] = signedRegistration.Signature.String()
+					k2.auditValidators([]string{validator}, representative, audit.DecisionWeb3SignerResign, audit.OutcomeSigned, "", data)
 				}
 			}
 		} // else the payout recipient is the same as the payload so signed registration has already been set
@@ -1740,6 +2108,7 @@ func (k2 *K2Service) prepareRegistrations(toProcess map[string]apiv1.SignedValid
 	ecdsaSignatures, err := k2.signatureSwapper.BatchGenerateSignature(signedRegistrations, representative)
 	if err != nil {
 		k2.log.WithError(err).Error("failed to generate signatures from signature swapper for proposer registration")
+		k2.auditValidators(registrationKeys(registrations), representative, audit.DecisionSignatureSwapper, audit.OutcomeFailed, err.Error(), nil)
 		return nil, err
 	}
 
@@ -1748,5 +2117,24 @@ func (k2 *K2Service) prepareRegistrations(toProcess map[string]apiv1.SignedValid
 		registrations[validatorPubKey] = reg
 	}
 
+	// Reject any signature that would not be accepted by the proposer registry before spending gas on it
+	invalidSignatures, err := k2.verifyRegistrationSignatures(registrations)
+	if err != nil {
+		k2.log.WithError(err).Error("failed to verify signatures from signature swapper for proposer registration")
+		k2.auditValidators(registrationKeys(registrations), representative, audit.DecisionSignatureSwapper, audit.OutcomeFailed, err.Error(), nil)
+		return nil, err
+	}
+	for _, validatorPubKey := range invalidSignatures {
+		k2.auditValidators([]string{validatorPubKey}, representative, audit.DecisionSignatureSwapper, audit.OutcomeFailed, "signature swapper returned an invalid signature", nil)
+		delete(registrations, validatorPubKey)
+	}
+
+	for validatorPubKey, reg := range registrations {
+		k2.auditValidators([]string{validatorPubKey}, representative, audit.DecisionSignatureSwapper, audit.OutcomeSigned, "", map[string]any{
+			"feeRecipient":   reg.SignedValidatorRegistration.Message.FeeRecipient.String(),
+			"ecdsaSignature": reg.ECDSASignature,
+		})
+	}
+
 	return registrations, nil
 }
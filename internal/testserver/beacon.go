@@ -0,0 +1,49 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/beacon"
+)
+
+// Beacon is the state of a beacon node serving the spec and sync status
+type Beacon struct {
+	Down         bool
+	ChainID      string
+	ForkVersion  string
+	HeadSlot     uint64
+	IsSyncing    bool
+	SyncDistance uint64
+	ELOffline    bool
+}
+
+// NewBeacon starts a synced mainnet beacon node
+func NewBeacon(t testing.TB) *Server[Beacon] {
+	t.Helper()
+
+	return New(t, Beacon{ChainID: "1", ForkVersion: "0x00000000", HeadSlot: 1000}, func(node *Beacon, w http.ResponseWriter, r *http.Request) {
+		if node.Down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case beacon.SpecPath:
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{
+				"DEPOSIT_CHAIN_ID":     node.ChainID,
+				"GENESIS_FORK_VERSION": node.ForkVersion,
+			}})
+		case beacon.SyncPath:
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"head_slot":     fmt.Sprint(node.HeadSlot),
+				"sync_distance": fmt.Sprint(node.SyncDistance),
+				"is_syncing":    node.IsSyncing,
+				"el_offline":    node.ELOffline,
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
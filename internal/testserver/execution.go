@@ -0,0 +1,71 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// Execution is the state of an execution node answering JSON-RPC calls
+type Execution struct {
+	Down         bool
+	NetworkID    string
+	CurrentBlock uint64
+	HighestBlock uint64 // not syncing if zero
+
+	// further methods the node answers, a method returning an error answers with a JSON-RPC error
+	Methods map[string]func(params []json.RawMessage) (any, error)
+}
+
+// NewExecution starts a synced mainnet execution node
+func NewExecution(t testing.TB) *Server[Execution] {
+	t.Helper()
+
+	return New(t, Execution{NetworkID: "1"}, func(node *Execution, w http.ResponseWriter, r *http.Request) {
+		if node.Down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var request struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		response := map[string]any{"jsonrpc": "2.0", "id": request.ID}
+		switch request.Method {
+		case "net_version":
+			response["result"] = node.NetworkID
+		case "eth_syncing":
+			response["result"] = false
+			if node.HighestBlock > 0 {
+				response["result"] = map[string]string{
+					"startingBlock": "0x0",
+					"currentBlock":  fmt.Sprintf("%#x", node.CurrentBlock),
+					"highestBlock":  fmt.Sprintf("%#x", node.HighestBlock),
+				}
+			}
+		default:
+			method, ok := node.Methods[request.Method]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			result, err := method(request.Params)
+			if err != nil {
+				response["error"] = map[string]any{"code": -32000, "message": err.Error()}
+			} else {
+				response["result"] = result
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+}
@@ -0,0 +1,55 @@
+// Package testserver provides fake HTTP dependencies for the module's tests, answering from a state the test changes
+// between requests
+package testserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// Server is a test HTTP server answering each request from its current state
+type Server[T any] struct {
+	URL *url.URL
+
+	mu    sync.Mutex
+	state T
+}
+
+// New starts a server answering each request with handle from the state, closed when the test ends. Requests are
+// handled one at a time so handle can read and change the state freely
+func New[T any](t testing.TB, state T, handle func(state *T, w http.ResponseWriter, r *http.Request)) *Server[T] {
+	t.Helper()
+
+	s := &Server[T]{state: state}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		handle(&s.state, w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.URL = u
+
+	return s
+}
+
+// Set changes the state the server answers from
+func (s *Server[T]) Set(update func(*T)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	update(&s.state)
+}
+
+// State returns a copy of the current state
+func (s *Server[T]) State() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
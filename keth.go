@@ -0,0 +1,212 @@
+package k2
+
+import (
+	"fmt"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+)
+
+// getRewardsWallets returns the rewards of each representative at every stage, the node operator rewards
+// still to claim, the KETH claimable from the K2 lending contract, claimed by the module and held by the wallet
+func (k2 *K2Service) getRewardsWallets(representatives []ethcommon.Address) ([]k2common.RewardsWallet, error) {
+
+	if !k2.k2Enabled() {
+		// module not configured to run
+		return nil, fmt.Errorf("module not configured to run K2 contract operations")
+	}
+
+	symbol, decimals, err := k2.eth1.KETHToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the KETH token details: %w", err)
+	}
+
+	claimableRewards, err := k2.eth1.BatchK2CheckClaimableRewards(representatives)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the claimable node operator rewards: %w", err)
+	}
+
+	claimable, held, err := k2.eth1.KETHBalances(representatives)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the KETH balances: %w", err)
+	}
+
+	k2.statusLock.RLock()
+	defer k2.statusLock.RUnlock()
+
+	wallets := make([]k2common.RewardsWallet, 0, len(representatives))
+	for _, representative := range representatives {
+		claimed := new(big.Int)
+		if amount, ok := k2.claimedKETH[representative]; ok {
+			claimed.Set(amount)
+		}
+		wallets = append(wallets, k2common.RewardsWallet{
+			RepresentativeAddress: representative,
+			Symbol:                symbol,
+			Decimals:              decimals,
+			ClaimableRewards:      claimableRewards[representative],
+			ClaimableKETH:         claimable[representative],
+			ClaimedKETH:           claimed,
+			HeldKETH:              held[representative],
+		})
+	}
+
+	return wallets, nil
+}
+
+// claimKETH claims the KETH claimable by each configured representative to its wallet, then transfers the KETH
+// just claimed to the treasury if one is configured, leaving any KETH the wallet held before in place. Failed transactions are notified and do not stop the
+// other representatives from claiming, the resulting balances of every representative are returned
+func (k2 *K2Service) claimKETH(representatives []ethcommon.Address) ([]k2common.K2Claim, error) {
+
+	defer k2.trackPendingJobs(jobClaims, len(representatives))()
+
+	k2.lock.Lock()
+	defer k2.lock.Unlock()
+
+	if !k2.k2Enabled() {
+		// module not configured to run
+		return nil, fmt.Errorf("module not configured to run K2 contract operations")
+	}
+
+	for _, representative := range representatives {
+		walletFound := false
+		for _, wallet := range k2.validatorWallets() {
+			if wallet.Address == representative {
+				walletFound = true
+				break
+			}
+		}
+		if !walletFound {
+			return nil, fmt.Errorf("representative [%v] is not a configured wallet; cannot claim KETH on behalf", representative.String())
+		}
+	}
+
+	claimable, _, err := k2.eth1.KETHBalances(representatives)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the KETH balances: %w", err)
+	}
+
+	claims := make([]k2common.K2Claim, len(representatives))
+	var sent, failed int
+	for i, representative := range representatives {
+		claims[i].RepresentativeAddress = representative
+
+		amount := claimable[representative]
+		if amount.Sign() <= 0 {
+			continue
+		}
+
+		tx, err := k2.eth1.ClaimKETH(representative)
+		if !k2.recordKETHTransaction(metrics.OperationKETHClaim, &claims[i], amount, tx, err) {
+			failed++
+			continue
+		}
+		sent++
+		claims[i].ClaimedKETH = amount
+
+		k2.statusLock.Lock()
+		if _, ok := k2.claimedKETH[representative]; !ok {
+			k2.claimedKETH[representative] = new(big.Int)
+		}
+		k2.claimedKETH[representative].Add(k2.claimedKETH[representative], amount)
+		k2.statusLock.Unlock()
+	}
+
+	if k2.cfg.KETHTreasury != (ethcommon.Address{}) {
+		_, held, err := k2.eth1.KETHBalances(representatives)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the KETH balances: %w", err)
+		}
+
+		for i, representative := range representatives {
+			if claims[i].ClaimedKETH == nil {
+				continue
+			}
+			// only the KETH just claimed, as far as the wallet still holds it
+			amount := new(big.Int).Set(claims[i].ClaimedKETH)
+			if balance := held[representative]; balance == nil {
+				continue
+			} else if amount.Cmp(balance) > 0 {
+				amount.Set(balance)
+			}
+			if amount.Sign() <= 0 {
+				continue
+			}
+
+			tx, err := k2.eth1.TransferKETH(representative, k2.cfg.KETHTreasury, amount)
+			if !k2.recordKETHTransaction(metrics.OperationKETHTransfer, &claims[i], amount, tx, err) {
+				failed++
+				continue
+			}
+			sent++
+			claims[i].TransferredKETH = amount
+		}
+	}
+
+	if sent == 0 && failed > 0 {
+		return nil, fmt.Errorf("failed to claim KETH for any of the representatives")
+	}
+
+	k2.attachKETHBalances(claims)
+
+	return claims, nil
+}
+
+// recordKETHTransaction logs, audits and notifies the outcome of a KETH claim or transfer of the representative,
+// reporting whether the transaction was executed
+func (k2 *K2Service) recordKETHTransaction(operation string, claim *k2common.K2Claim, amount *big.Int, tx *types.Transaction, txErr error) bool {
+
+	representative := claim.RepresentativeAddress
+
+	k2.auditTransaction(operation, representative, nil, tx, txErr)
+
+	data := map[string]any{
+		"operation": operation,
+		"amount":    amount.String(),
+	}
+	if operation == metrics.OperationKETHTransfer {
+		data["treasury"] = k2.cfg.KETHTreasury.String()
+	}
+
+	if txErr != nil {
+		k2.log.WithError(txErr).WithField("representative", representative.String()).Errorf("failed to send %s transaction", operation)
+		data["error"] = txErr.Error()
+		k2.notify(notifier.EventTransactionFailed, []ethcommon.Address{representative}, data)
+		return false
+	}
+
+	k2.log.WithFields(logrus.Fields{
+		"representative": representative.String(),
+		"operation":      operation,
+		"amount":         amount.String(),
+		"txHash":         tx.Hash().String(),
+	}).Info("KETH transaction completed")
+	metrics.BatchesSent.WithLabelValues(representative.String(), operation).Inc()
+	claim.TxHashes = append(claim.TxHashes, tx.Hash())
+	data["txHash"] = tx.Hash().String()
+	k2.notify(notifier.EventClaim, []ethcommon.Address{representative}, data)
+
+	return true
+}
+
+// attachKETHBalances sets the resulting KETH balances of the representatives on their claims
+func (k2 *K2Service) attachKETHBalances(claims []k2common.K2Claim) {
+
+	claimable, held, err := k2.eth1.KETHBalances(claimRepresentatives(claims))
+	if err != nil {
+		k2.log.WithError(err).Warn("failed to get the resulting KETH balances of the claims")
+		return
+	}
+
+	for i := range claims {
+		claims[i].ClaimableKETH = claimable[claims[i].RepresentativeAddress]
+		claims[i].KETHBalance = held[claims[i].RepresentativeAddress]
+	}
+}
@@ -0,0 +1,112 @@
+package k2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	mevcommon "github.com/pon-network/mev-plus/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/config"
+)
+
+func TestParseConfig_KETHTreasury(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		treasury string
+		want     ethcommon.Address
+		wantErr  bool
+	}{
+		{
+			name:     "treasury",
+			treasury: "0x4444444444444444444444444444444444444444",
+			want:     ethcommon.HexToAddress("0x4444444444444444444444444444444444444444"),
+		},
+		{
+			name:     "invalid address",
+			treasury: "treasury",
+			wantErr:  true,
+		},
+		{
+			name:     "null address",
+			treasury: "0x0000000000000000000000000000000000000000",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+			err := k2.parseConfig(testModuleFlags(mevcommon.ModuleFlags{config.KETHTreasuryFlag.Name: tt.treasury}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && k2.cfg.KETHTreasury != tt.want {
+				t.Errorf("treasury = %s, want %s", k2.cfg.KETHTreasury, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimKETH_Refused(t *testing.T) {
+
+	wallet := ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	tests := []struct {
+		name            string
+		k2Enabled       bool
+		representatives []ethcommon.Address
+		wantErr         string
+	}{
+		{
+			name:            "K2 not configured",
+			representatives: []ethcommon.Address{wallet},
+			wantErr:         "module not configured to run K2 contract operations",
+		},
+		{
+			name:            "representative not configured",
+			k2Enabled:       true,
+			representatives: []ethcommon.Address{wallet, other},
+			wantErr:         "is not a configured wallet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+			k2.cfg.ValidatorWallets = []k2common.ValidatorWallet{{Address: wallet}}
+			if tt.k2Enabled {
+				k2.cfg.K2LendingContractAddress = ethcommon.HexToAddress("0x000000000000000000000000000000000000a001")
+				k2.cfg.K2NodeOperatorContractAddress = ethcommon.HexToAddress("0x000000000000000000000000000000000000a002")
+			}
+
+			_, err := k2.claimKETH(tt.representatives)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("claimKETH() error = %v, want %q", err, tt.wantErr)
+			}
+
+			if tt.k2Enabled {
+				return
+			}
+			if _, err := k2.getRewardsWallets(tt.representatives); err == nil {
+				t.Errorf("getRewardsWallets() returned rewards without K2 configured")
+			}
+		})
+	}
+}
+
+func TestHandleGetRewardsWallets_InvalidAddress(t *testing.T) {
+
+	k2 := NewK2Service()
+
+	w := httptest.NewRecorder()
+	k2.handleGetRewardsWallets(w, httptest.NewRequest(http.MethodGet, "/?representativeAddresses=0x2222222222222222222222222222222222222222,wallet", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,407 @@
+package k2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+const (
+	// Lists managed through the lists API
+	listExclusion       = "exclusion"
+	listInclusion       = "inclusion"
+	listRepresentatives = "representatives"
+)
+
+var (
+	errListNotConfigured  = errors.New("list file not configured")
+	errUnknownList        = errors.New("unknown list")
+	errListEntryExists    = errors.New("list entry already exists")
+	errListEntryNotFound  = errors.New("list entry not found")
+	errListEntryKeyChange = errors.New("list entry key cannot be changed, remove the entry and add a new one")
+	errInvalidListChange  = errors.New("invalid list change")
+)
+
+func validatorFilterKey(entry k2common.ValidatorFilter) string {
+	if entry.Rule != "" {
+		return filterRuleKey(entry.Rule)
+	}
+	if entry.PublicKey != (phase0.BLSPubKey{}) {
+		return strings.ToLower(entry.PublicKey.String())
+	}
+	return strings.ToLower(entry.FeeRecipient.String())
+}
+
+func representativeMappingKey(entry k2common.CustomPayoutRepresentative) string {
+	if entry.PublicKey != (phase0.BLSPubKey{}) {
+		return strings.ToLower(entry.PublicKey.String())
+	}
+	return strings.ToLower(entry.FeeRecipientAddress.String())
+}
+
+// applyListChange adds (POST), replaces (PUT) or removes (DELETE) the entry with the given key
+func applyListChange[T any](entries []T, keyOf func(T) string, method string, key string, entry T) ([]T, error) {
+
+	index := -1
+	for i, existing := range entries {
+		if keyOf(existing) == key {
+			index = i
+			break
+		}
+	}
+
+	switch method {
+	case http.MethodPost:
+		if index >= 0 {
+			return nil, errListEntryExists
+		}
+		return append(entries, entry), nil
+	case http.MethodPut:
+		if index < 0 {
+			return nil, errListEntryNotFound
+		}
+		if keyOf(entry) != key {
+			return nil, errListEntryKeyChange
+		}
+		updated := append([]T{}, entries...)
+		updated[index] = entry
+		return updated, nil
+	case http.MethodDelete:
+		if index < 0 {
+			return nil, errListEntryNotFound
+		}
+		updated := append([]T{}, entries[:index]...)
+		return append(updated, entries[index+1:]...), nil
+	default:
+		return nil, fmt.Errorf("unsupported list operation %s", method)
+	}
+}
+
+func (k2 *K2Service) listFile(list string) (string, error) {
+	var filePath string
+	switch list {
+	case listExclusion:
+		filePath = k2.cfg.ExclusionListFile
+	case listInclusion:
+		filePath = k2.cfg.StrictInclusionListFile
+	case listRepresentatives:
+		filePath = k2.cfg.RepresentativeMappingFile
+	default:
+		return "", errUnknownList
+	}
+	if filePath == "" {
+		return "", errListNotConfigured
+	}
+	return filePath, nil
+}
+
+// readListEntries decodes the entries of a list file in any of the list formats
+func readListEntries(filePath string, entries any) error {
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read list file: %w", err)
+	}
+	return decodeListEntries(filePath, fileContent, entries)
+}
+
+func decodeListEntries(source string, content []byte, entries any) error {
+	var err error
+	switch entries := entries.(type) {
+	case *[]k2common.ValidatorFilter:
+		err = k2common.DecodeList(source, content, entries)
+	case *[]k2common.CustomPayoutRepresentative:
+		err = k2common.DecodeList(source, content, entries)
+	default:
+		err = fmt.Errorf("unsupported list entries %T", entries)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse list file: %w", err)
+	}
+	return nil
+}
+
+// writeListFile replaces the list file with the entries, in the format of the existing file, by renaming
+// a fully written temporary file over it, so the file watcher never reads a partially written list
+func writeListFile[T any](filePath string, entries []T) error {
+
+	existingContent, _ := os.ReadFile(filePath)
+	fileContent, err := k2common.EncodeList(entries, k2common.DetectListFormat(filePath, existingContent))
+	if err != nil {
+		return fmt.Errorf("failed to encode list: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary list file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(fileContent); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write temporary list file: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write temporary list file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary list file: %w", err)
+	}
+
+	if info, err := os.Stat(filePath); err == nil {
+		// keep the permissions of the existing list file
+		if err := os.Chmod(tmpFile.Name(), info.Mode()); err != nil {
+			return fmt.Errorf("failed to set list file permissions: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpFile.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to replace list file: %w", err)
+	}
+
+	return nil
+}
+
+// getListEntries returns the entries of the list as persisted in its file, or as last applied from its url
+func (k2 *K2Service) getListEntries(list string) (any, error) {
+
+	filePath, err := k2.listFile(list)
+	if err != nil {
+		return nil, err
+	}
+
+	if list == listRepresentatives {
+		entries := []k2common.CustomPayoutRepresentative{}
+		err = k2.readSourceEntries(filePath, &entries)
+		return entries, err
+	}
+
+	entries := []k2common.ValidatorFilter{}
+	err = k2.readSourceEntries(filePath, &entries)
+	return entries, err
+}
+
+// listSnapshot returns the lists in use. The lists are replaced rather than modified when they change, so the
+// maps returned can be read after the list lock is released
+func (k2 *K2Service) listSnapshot() (exclusionList map[string]k2common.ValidatorFilter, strictInclusionList map[string]k2common.ValidatorFilter, representativeMapping map[string]ethcommon.Address) {
+	k2.listLock.RLock()
+	defer k2.listLock.RUnlock()
+
+	return k2.exclusionList, k2.strictInclusionList, k2.representativeMapping
+}
+
+// readSourceEntries decodes the entries of a list file, or of the list document last applied from a url
+func (k2 *K2Service) readSourceEntries(source string, entries any) error {
+
+	if !isRemoteList(source) {
+		return readListEntries(source, entries)
+	}
+
+	k2.remoteListLock.Lock()
+	content := k2.remoteLists[source].content
+	k2.remoteListLock.Unlock()
+
+	return decodeListEntries(source, content, entries)
+}
+
+// changeList applies a change to a list, validates the resulting list the same way as a file reload,
+// then persists it to the list file and applies it to the module
+func (k2 *K2Service) changeList(list string, method string, key string, body []byte) (k2common.ListChange, error) {
+
+	filePath, err := k2.listFile(list)
+	if err != nil {
+		return k2common.ListChange{}, err
+	}
+	if isRemoteList(filePath) {
+		return k2common.ListChange{}, errRemoteList
+	}
+
+	change, rule, err := k2.applyListEntryChange(list, filePath, method, key, body)
+	if err != nil {
+		return change, err
+	}
+
+	change.AffectedValidators = k2.recentlySeenValidators(change.Key, rule)
+
+	k2.log.WithField("list", list).Infof("List entry %s changed through the API", change.Key)
+
+	return change, nil
+}
+
+// applyListEntryChange changes the entry of the list file and applies the list while holding the list lock, so the
+// file watcher reload waits for the new file to be applied. Only a representative entry with a derivation index waits on
+// in-flight processing, to add its wallet once the change is accepted. The rule of a rule entry added, updated or removed
+// is returned to preview the validators it matches
+func (k2 *K2Service) applyListEntryChange(list string, filePath string, method string, key string, body []byte) (change k2common.ListChange, rule *k2common.ValidatorFilter, err error) {
+
+	change = k2common.ListChange{
+		List: list,
+		Key:  strings.ToLower(key),
+	}
+
+	switch list {
+	case listRepresentatives:
+		var entry k2common.CustomPayoutRepresentative
+		var derived []derivedWallet
+		if method != http.MethodDelete {
+			if err := decodeListEntry(body, &entry); err != nil {
+				return change, nil, err
+			}
+			if method == http.MethodPost {
+				change.Key = representativeMappingKey(entry)
+			}
+			derived, err = k2.deriveMappedWallets([]k2common.CustomPayoutRepresentative{entry})
+			if err != nil {
+				return change, nil, fmt.Errorf("%w: %v", errInvalidListChange, err)
+			}
+			if len(derived) > 0 {
+				// adding the wallet derived for the entry waits on in-flight processing, so is locked before the list lock
+				k2.lock.Lock()
+				defer k2.lock.Unlock()
+			}
+		}
+
+		k2.listLock.Lock()
+		defer k2.listLock.Unlock()
+
+		entries := []k2common.CustomPayoutRepresentative{}
+		if err := readListEntries(filePath, &entries); err != nil {
+			return change, nil, err
+		}
+		entries, err = applyListChange(entries, representativeMappingKey, method, change.Key, entry)
+		if err != nil {
+			return change, nil, err
+		}
+		prepared, err := k2.prepareRepresentativeMapping(entries, derived...)
+		if err != nil {
+			return change, nil, fmt.Errorf("%w: %v", errInvalidListChange, err)
+		}
+		if err := k2.listValidationError(k2.exclusionList, k2.strictInclusionList, prepared, derived...); err != nil {
+			return change, nil, fmt.Errorf("%w: %v", errInvalidListChange, err)
+		}
+		if err := writeListFile(filePath, entries); err != nil {
+			return change, nil, err
+		}
+		k2.addDerivedWallets(derived)
+		k2.representativeMapping = prepared
+		change.Entries = len(entries)
+	case listExclusion, listInclusion:
+		var entry k2common.ValidatorFilter
+		if method != http.MethodDelete {
+			if err := decodeListEntry(body, &entry); err != nil {
+				return change, nil, err
+			}
+			if method == http.MethodPost {
+				change.Key = validatorFilterKey(entry)
+			}
+		}
+		k2.listLock.Lock()
+		defer k2.listLock.Unlock()
+
+		entries := []k2common.ValidatorFilter{}
+		if err := readListEntries(filePath, &entries); err != nil {
+			return change, nil, err
+		}
+		for _, existing := range entries {
+			if validatorFilterKey(existing) == change.Key && existing.Rule != "" && method == http.MethodDelete {
+				removed := existing
+				rule = &removed
+			}
+		}
+		if entry.Rule != "" {
+			rule = &entry
+		}
+		entries, err = applyListChange(entries, validatorFilterKey, method, change.Key, entry)
+		if err != nil {
+			return change, nil, err
+		}
+		var prepared map[string]k2common.ValidatorFilter
+		if list == listExclusion {
+			prepared, err = k2.prepareExclusionList(entries)
+		} else {
+			prepared, err = k2.prepareInclusionList(entries)
+		}
+		if err != nil {
+			return change, nil, fmt.Errorf("%w: %v", errInvalidListChange, err)
+		}
+		exclusionList, inclusionList := k2.exclusionList, k2.strictInclusionList
+		if list == listExclusion {
+			exclusionList = prepared
+		} else {
+			inclusionList = prepared
+		}
+		if err := k2.listValidationError(exclusionList, inclusionList, k2.representativeMapping); err != nil {
+			return change, nil, fmt.Errorf("%w: %v", errInvalidListChange, err)
+		}
+		if err := writeListFile(filePath, entries); err != nil {
+			return change, nil, err
+		}
+		if list == listExclusion {
+			k2.exclusionList = prepared
+		} else {
+			k2.strictInclusionList = prepared
+		}
+		change.Entries = len(entries)
+	}
+
+	return change, rule, nil
+}
+
+func decodeListEntry(body []byte, entry any) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(entry); err != nil {
+		return fmt.Errorf("%w: invalid list entry: %v", errInvalidListChange, err)
+	}
+	return nil
+}
+
+// recentlySeenValidators returns the validators seen in recent registrations whose BLS key or fee recipient matches
+// the list key, or for a rule entry the validators the rule matches
+func (k2 *K2Service) recentlySeenValidators(key string, rule *k2common.ValidatorFilter) []string {
+
+	k2.statusLock.RLock()
+	registrations := make([]apiv1.SignedValidatorRegistration, 0, len(k2.recentRegistrations))
+	for _, registration := range k2.recentRegistrations {
+		registrations = append(registrations, registration)
+	}
+	k2.statusLock.RUnlock()
+
+	var rules ruleContext
+	if rule != nil {
+		k2.listLock.RLock()
+		rules = ruleContext{
+			rules: []listRule{{filter: *rule}},
+			tags:  k2.validatorTags,
+		}
+		k2.listLock.RUnlock()
+		k2.gatherRuleFacts(&rules, registrations)
+	}
+
+	validators := []string{}
+	for _, registration := range registrations {
+		pubkey := strings.ToLower(registration.Message.Pubkey.String())
+		if rule != nil {
+			if rules.matches(*rule, pubkey) {
+				validators = append(validators, registration.Message.Pubkey.String())
+			}
+		} else if pubkey == key || strings.EqualFold(ethcommon.Address(registration.Message.FeeRecipient).String(), key) {
+			validators = append(validators, registration.Message.Pubkey.String())
+		}
+	}
+	sort.Strings(validators)
+
+	return validators
+}
@@ -0,0 +1,188 @@
+package k2
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+func TestApplyListChange(t *testing.T) {
+
+	keyOf := func(entry string) string { return strings.SplitN(entry, "=", 2)[0] }
+	entries := []string{"a=1", "b=2"}
+
+	tests := []struct {
+		name    string
+		method  string
+		key     string
+		entry   string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "add",
+			method: http.MethodPost,
+			key:    "c",
+			entry:  "c=3",
+			want:   []string{"a=1", "b=2", "c=3"},
+		},
+		{
+			name:    "add existing",
+			method:  http.MethodPost,
+			key:     "a",
+			entry:   "a=3",
+			wantErr: errListEntryExists,
+		},
+		{
+			name:   "update",
+			method: http.MethodPut,
+			key:    "b",
+			entry:  "b=3",
+			want:   []string{"a=1", "b=3"},
+		},
+		{
+			name:    "update missing",
+			method:  http.MethodPut,
+			key:     "c",
+			entry:   "c=3",
+			wantErr: errListEntryNotFound,
+		},
+		{
+			name:    "update key",
+			method:  http.MethodPut,
+			key:     "b",
+			entry:   "c=3",
+			wantErr: errListEntryKeyChange,
+		},
+		{
+			name:   "remove",
+			method: http.MethodDelete,
+			key:    "a",
+			want:   []string{"b=2"},
+		},
+		{
+			name:    "remove missing",
+			method:  http.MethodDelete,
+			key:     "c",
+			wantErr: errListEntryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyListChange(entries, keyOf, tt.method, tt.key, tt.entry)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyListChange() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyListChange() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(entries, []string{"a=1", "b=2"}) {
+				t.Errorf("entries changed to %v", entries)
+			}
+		})
+	}
+}
+
+func TestListsAPI(t *testing.T) {
+
+	pubkey := phase0.BLSPubKey{0xa1}
+	feeRecipient := ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	feeRecipientKey := strings.ToLower(feeRecipient.String())
+
+	k2 := NewK2Service()
+	k2.cfg.ExclusionListFile = filepath.Join(t.TempDir(), "exclusion.json")
+	if err := os.WriteFile(k2.cfg.ExclusionListFile, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	k2.recentRegistrations[strings.ToLower(pubkey.String())] = apiv1.SignedValidatorRegistration{
+		Message: &apiv1.ValidatorRegistration{FeeRecipient: bellatrix.ExecutionAddress(feeRecipient), Pubkey: pubkey},
+	}
+
+	call := func(handler http.HandlerFunc, method string, vars map[string]string, body string) *httptest.ResponseRecorder {
+		r := mux.SetURLVars(httptest.NewRequest(method, "/", strings.NewReader(body)), vars)
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+	exclusion := map[string]string{"list": listExclusion}
+	exclusionEntry := map[string]string{"list": listExclusion, "key": feeRecipient.String()}
+	entry := `{"feeRecipientAddress":"` + feeRecipient.String() + `","allowProposerRegistration":false,"allowNativeDelegation":false}`
+
+	// add
+	w := call(k2.handleChangeList, http.MethodPost, exclusion, entry)
+	if w.Code != http.StatusOK {
+		t.Fatalf("add status = %d: %s", w.Code, w.Body.String())
+	}
+	var change k2common.ListChange
+	if err := json.NewDecoder(w.Body).Decode(&change); err != nil {
+		t.Fatalf("change cannot be decoded: %v", err)
+	}
+	if change.Key != feeRecipientKey || change.Entries != 1 || !reflect.DeepEqual(change.AffectedValidators, []string{pubkey.String()}) {
+		t.Errorf("change = %+v, want %s with 1 entry affecting %s", change, feeRecipientKey, pubkey)
+	}
+	if _, ok := k2.exclusionList[feeRecipientKey]; !ok {
+		t.Errorf("exclusion list not applied after adding the entry")
+	}
+
+	if w := call(k2.handleChangeList, http.MethodPost, exclusion, entry); w.Code != http.StatusConflict {
+		t.Errorf("add existing status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	// update
+	updated := strings.Replace(entry, `"allowNativeDelegation":false`, `"allowNativeDelegation":true`, 1)
+	if w := call(k2.handleChangeList, http.MethodPut, exclusionEntry, updated); w.Code != http.StatusOK {
+		t.Fatalf("update status = %d: %s", w.Code, w.Body.String())
+	}
+	if !k2.exclusionList[feeRecipientKey].NativeDelegation {
+		t.Errorf("exclusion list not applied after updating the entry")
+	}
+
+	otherEntry := `{"feeRecipientAddress":"0x2222222222222222222222222222222222222222"}`
+	if w := call(k2.handleChangeList, http.MethodPut, exclusionEntry, otherEntry); w.Code != http.StatusBadRequest {
+		t.Errorf("update key status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// the file persists the change
+	w = call(k2.handleGetList, http.MethodGet, exclusion, "")
+	var entries []k2common.ValidatorFilter
+	if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+		t.Fatalf("list cannot be decoded: %v", err)
+	}
+	if len(entries) != 1 || entries[0].FeeRecipient != feeRecipient || !entries[0].NativeDelegation {
+		t.Errorf("list = %+v, want the updated entry", entries)
+	}
+
+	// remove
+	if w := call(k2.handleChangeList, http.MethodDelete, exclusionEntry, ""); w.Code != http.StatusOK {
+		t.Fatalf("remove status = %d: %s", w.Code, w.Body.String())
+	}
+	if _, ok := k2.exclusionList[feeRecipientKey]; ok {
+		t.Errorf("exclusion list not applied after removing the entry")
+	}
+	if w := call(k2.handleChangeList, http.MethodDelete, exclusionEntry, ""); w.Code != http.StatusNotFound {
+		t.Errorf("remove missing status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// lists that cannot be changed
+	if w := call(k2.handleGetList, http.MethodGet, map[string]string{"list": "allowed"}, ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown list status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := call(k2.handleChangeList, http.MethodPost, map[string]string{"list": listInclusion}, entry); w.Code != http.StatusNotFound {
+		t.Errorf("list not configured status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,173 @@
+package k2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+const (
+	issueError   = "error"
+	issueWarning = "warning"
+
+	issueContradiction          = "contradiction"
+	issueUnreachable            = "unreachable"
+	issueUnknownRepresentative  = "unknown_representative"
+	issueShadowedRepresentative = "shadowed_representative"
+)
+
+var filterPrecedence = []string{
+	"exclusion list by BLS key",
+	"exclusion list by fee recipient",
+	"strict inclusion list by BLS key",
+	"strict inclusion list by fee recipient",
+	"exclusion and strict inclusion list rules by descending priority, exclusion rules first for equal priority",
+}
+
+var representativePrecedence = []string{
+	"representative mapping by BLS key",
+	"representative mapping by fee recipient",
+	"configured wallets in order of priority",
+}
+
+type filterMatch struct {
+	description string
+	filter      k2common.ValidatorFilter
+}
+
+// validateLists checks the exclusion list, strict inclusion list and representative mapping together for entries that
+// contradict each other for recently seen validators, entries that can never take effect and unknown representatives
+func (k2 *K2Service) validateLists(exclusionList map[string]k2common.ValidatorFilter, inclusionList map[string]k2common.ValidatorFilter, representativeMapping map[string]ethcommon.Address, derived ...derivedWallet) k2common.ListValidation {
+
+	validation := k2common.ListValidation{
+		Valid:                    true,
+		FilterPrecedence:         filterPrecedence,
+		RepresentativePrecedence: representativePrecedence,
+		Issues:                   []k2common.ListIssue{},
+	}
+
+	addIssue := func(severity string, kind string, key string, message string) {
+		if severity == issueError {
+			validation.Valid = false
+		}
+		validation.Issues = append(validation.Issues, k2common.ListIssue{
+			Severity: severity,
+			Kind:     kind,
+			Key:      key,
+			Message:  message,
+		})
+	}
+
+	configuredWallets := walletAddresses(k2.validatorWallets(), derived)
+
+	for key, representative := range representativeMapping {
+		if !configuredWallets[representative] {
+			addIssue(issueError, issueUnknownRepresentative, key, fmt.Sprintf("representative %s is not a configured wallet", representative.String()))
+		}
+		if filter, ok := exclusionList[key]; ok && !filter.ProposerRegistration && !filter.NativeDelegation {
+			addIssue(issueWarning, issueUnreachable, key, "representative mapping never applies as the exclusion list excludes it from both proposer registration and native delegation")
+		}
+	}
+
+	k2.statusLock.RLock()
+	seen := make(map[string]string, len(k2.recentRegistrations)) // [Validator pubKey] -> Fee recipient
+	for pubkey, registration := range k2.recentRegistrations {
+		seen[pubkey] = strings.ToLower(ethcommon.Address(registration.Message.FeeRecipient).String())
+	}
+	k2.statusLock.RUnlock()
+
+	// rules match validators by facts fetched when registrations are processed, so validators
+	// that are not in the strict inclusion list by key or fee recipient may still be included by a rule
+	inclusionRules := false
+	for _, filter := range inclusionList {
+		inclusionRules = inclusionRules || filter.Rule != ""
+	}
+
+	for pubkey, feeRecipient := range seen {
+
+		if len(inclusionList) > 0 && !inclusionRules {
+			_, keyIncluded := inclusionList[pubkey]
+			_, feeRecipientIncluded := inclusionList[feeRecipient]
+			if !keyIncluded && !feeRecipientIncluded {
+				// validator is dropped by the strict inclusion list before any other entry is checked
+				if _, ok := exclusionList[pubkey]; ok {
+					addIssue(issueWarning, issueUnreachable, pubkey, "exclusion list entry never applies as neither the validator nor its fee recipient is in the strict inclusion list")
+				}
+				if _, ok := representativeMapping[pubkey]; ok {
+					addIssue(issueWarning, issueUnreachable, pubkey, "representative mapping never applies as neither the validator nor its fee recipient is in the strict inclusion list")
+				}
+				continue
+			}
+		}
+
+		var matches []filterMatch
+		if filter, ok := exclusionList[pubkey]; ok {
+			matches = append(matches, filterMatch{"exclusion list entry for its BLS key", filter})
+		}
+		if filter, ok := exclusionList[feeRecipient]; ok {
+			matches = append(matches, filterMatch{"exclusion list entry for its fee recipient", filter})
+		}
+		if filter, ok := inclusionList[pubkey]; ok {
+			matches = append(matches, filterMatch{"strict inclusion list entry for its BLS key", filter})
+		}
+		if filter, ok := inclusionList[feeRecipient]; ok {
+			matches = append(matches, filterMatch{"strict inclusion list entry for its fee recipient", filter})
+		}
+
+		for i := 1; i < len(matches); i++ {
+			applied, shadowed := matches[0], matches[i]
+			if applied.filter.ProposerRegistration != shadowed.filter.ProposerRegistration || applied.filter.NativeDelegation != shadowed.filter.NativeDelegation {
+				addIssue(issueError, issueContradiction, pubkey, fmt.Sprintf(
+					"%s (proposer registration %v, native delegation %v) takes precedence over the contradicting %s (proposer registration %v, native delegation %v)",
+					applied.description, applied.filter.ProposerRegistration, applied.filter.NativeDelegation,
+					shadowed.description, shadowed.filter.ProposerRegistration, shadowed.filter.NativeDelegation,
+				))
+			}
+		}
+
+		keyRepresentative, keyMapped := representativeMapping[pubkey]
+		feeRecipientRepresentative, feeRecipientMapped := representativeMapping[feeRecipient]
+		if keyMapped && feeRecipientMapped && keyRepresentative != feeRecipientRepresentative {
+			addIssue(issueWarning, issueShadowedRepresentative, pubkey, fmt.Sprintf(
+				"representative %s mapped to its BLS key takes precedence over representative %s mapped to its fee recipient",
+				keyRepresentative.String(), feeRecipientRepresentative.String(),
+			))
+		}
+	}
+
+	sort.Slice(validation.Issues, func(i, j int) bool {
+		if validation.Issues[i].Key != validation.Issues[j].Key {
+			return validation.Issues[i].Key < validation.Issues[j].Key
+		}
+		return validation.Issues[i].Kind < validation.Issues[j].Kind
+	})
+
+	return validation
+}
+
+// listValidationError validates the lists as they would be after a change or reload, logging any warnings,
+// and returns an error describing the issues that should cause the change to be refused.
+// k2.listLock must be held by the caller
+func (k2 *K2Service) listValidationError(exclusionList map[string]k2common.ValidatorFilter, inclusionList map[string]k2common.ValidatorFilter, representativeMapping map[string]ethcommon.Address, derived ...derivedWallet) error {
+
+	validation := k2.validateLists(exclusionList, inclusionList, representativeMapping, derived...)
+
+	var failures []string
+	for _, issue := range validation.Issues {
+		if issue.Severity == issueError {
+			failures = append(failures, fmt.Sprintf("%s: %s", issue.Key, issue.Message))
+			continue
+		}
+		k2.log.WithField("key", issue.Key).Warnf("List validation: %s", issue.Message)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("list validation failed: %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package k2
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+func TestValidateLists(t *testing.T) {
+
+	pubkey := strings.ToLower(phase0.BLSPubKey{0xa1}.String())
+	feeRecipient := strings.ToLower(ethcommon.HexToAddress("0x1111111111111111111111111111111111111111").String())
+	otherKey := strings.ToLower(phase0.BLSPubKey{0xb2}.String())
+	wallet := ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	otherWallet := ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	allowed := k2common.ValidatorFilter{ProposerRegistration: true, NativeDelegation: true}
+	denied := k2common.ValidatorFilter{}
+
+	type issue struct{ severity, kind, key string }
+
+	tests := []struct {
+		name                  string
+		exclusionList         map[string]k2common.ValidatorFilter
+		inclusionList         map[string]k2common.ValidatorFilter
+		representativeMapping map[string]ethcommon.Address
+		wantIssues            []issue
+	}{
+		{
+			name:                  "consistent",
+			exclusionList:         map[string]k2common.ValidatorFilter{pubkey: allowed, feeRecipient: allowed},
+			inclusionList:         map[string]k2common.ValidatorFilter{feeRecipient: allowed},
+			representativeMapping: map[string]ethcommon.Address{pubkey: wallet},
+		},
+		{
+			name:                  "unknown representative",
+			representativeMapping: map[string]ethcommon.Address{feeRecipient: otherWallet},
+			wantIssues:            []issue{{issueError, issueUnknownRepresentative, feeRecipient}},
+		},
+		{
+			name:                  "representative of an excluded validator",
+			exclusionList:         map[string]k2common.ValidatorFilter{pubkey: denied},
+			representativeMapping: map[string]ethcommon.Address{pubkey: wallet},
+			wantIssues:            []issue{{issueWarning, issueUnreachable, pubkey}},
+		},
+		{
+			name:          "BLS key entry contradicts the fee recipient entry",
+			exclusionList: map[string]k2common.ValidatorFilter{pubkey: denied, feeRecipient: allowed},
+			wantIssues:    []issue{{issueError, issueContradiction, pubkey}},
+		},
+		{
+			name:          "exclusion entry contradicts the strict inclusion entry",
+			exclusionList: map[string]k2common.ValidatorFilter{feeRecipient: denied},
+			inclusionList: map[string]k2common.ValidatorFilter{pubkey: allowed},
+			wantIssues:    []issue{{issueError, issueContradiction, pubkey}},
+		},
+		{
+			name:                  "entries for a validator outside the strict inclusion list",
+			exclusionList:         map[string]k2common.ValidatorFilter{pubkey: allowed},
+			inclusionList:         map[string]k2common.ValidatorFilter{otherKey: allowed},
+			representativeMapping: map[string]ethcommon.Address{pubkey: wallet},
+			wantIssues: []issue{
+				{issueWarning, issueUnreachable, pubkey},
+				{issueWarning, issueUnreachable, pubkey},
+			},
+		},
+		{
+			name:                  "BLS key representative shadows the fee recipient representative",
+			representativeMapping: map[string]ethcommon.Address{pubkey: wallet, feeRecipient: otherWallet},
+			wantIssues: []issue{
+				{issueError, issueUnknownRepresentative, feeRecipient},
+				{issueWarning, issueShadowedRepresentative, pubkey},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+			k2.cfg.ValidatorWallets = []k2common.ValidatorWallet{{Address: wallet}}
+			k2.recentRegistrations[pubkey] = apiv1.SignedValidatorRegistration{
+				Message: &apiv1.ValidatorRegistration{
+					FeeRecipient: bellatrix.ExecutionAddress(ethcommon.HexToAddress(feeRecipient)),
+					Pubkey:       phase0.BLSPubKey{0xa1},
+				},
+			}
+
+			validation := k2.validateLists(tt.exclusionList, tt.inclusionList, tt.representativeMapping)
+
+			var gotIssues []issue
+			wantValid := true
+			for _, got := range validation.Issues {
+				gotIssues = append(gotIssues, issue{got.Severity, got.Kind, got.Key})
+			}
+			for _, want := range tt.wantIssues {
+				wantValid = wantValid && want.severity != issueError
+			}
+			if !reflect.DeepEqual(gotIssues, tt.wantIssues) {
+				t.Errorf("issues = %+v, want %+v", validation.Issues, tt.wantIssues)
+			}
+			if validation.Valid != wantValid {
+				t.Errorf("valid = %v, want %v", validation.Valid, wantValid)
+			}
+
+			err := k2.listValidationError(tt.exclusionList, tt.inclusionList, tt.representativeMapping)
+			if (err == nil) != wantValid {
+				t.Errorf("listValidationError() error = %v, want error %v", err, !wantValid)
+			}
+		})
+	}
+}
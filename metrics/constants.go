@@ -0,0 +1,30 @@
+package metrics
+
+const (
+	Namespace = "k2"
+
+	// Dependency labels
+	DependencyBeaconNode       = "beacon_node"
+	DependencyExecutionNode    = "execution_node"
+	DependencySignatureSwapper = "signature_swapper"
+	DependencyBalanceVerifier  = "balance_verifier"
+	DependencySubgraph         = "subgraph"
+	DependencyWeb3Signer       = "web3signer"
+
+	// Signature source labels for signatures that are not returned by a dependency
+	SignatureSourceRegistration = "registration"
+
+	// Operation labels
+	OperationProposerRegistry     = "proposer_registry"
+	OperationNativeDelegation     = "native_delegation"
+	OperationClaim                = "claim"
+	OperationExit                 = "exit"
+	OperationPayoutUpdate         = "payout_update"
+	OperationClaimRecipientUpdate = "claim_recipient_update"
+	OperationKETHClaim            = "keth_claim"
+	OperationKETHTransfer         = "keth_transfer"
+
+	// Capacity scope labels
+	CapacityScopeGlobal     = "global"
+	CapacityScopeIndividual = "individual"
+)
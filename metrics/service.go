@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The module keeps its own registry so that it does not collide with
+// any other module plugged into MEV Plus that uses the default registry
+var registry = prometheus.NewRegistry()
+
+var (
+	RegistrationsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "registrations_processed_total",
+		Help:      "Number of validator registrations successfully processed on-chain",
+	}, []string{"representative", "operation"})
+
+	BatchesSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "batches_sent_total",
+		Help:      "Number of batch transactions sent",
+	}, []string{"representative", "operation"})
+
+	TransactionFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "transaction_failures_total",
+		Help:      "Number of transactions that failed to be sent or executed",
+	}, []string{"representative", "operation"})
+
+	GasUsed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "gas_used_total",
+		Help:      "Gas used by executed transactions",
+	}, []string{"representative", "operation"})
+
+	GasSpent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "gas_spent_eth_total",
+		Help:      "Transaction fees paid by executed transactions, in ETH",
+	}, []string{"representative", "operation"})
+
+	ClaimsExecuted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "claims_executed_total",
+		Help:      "Number of reward claims executed",
+	}, []string{"representative"})
+
+	KETHClaimed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "keth_claimed_total",
+		Help:      "Amount of KETH claimed, in KETH",
+	}, []string{"representative"})
+
+	CapacityRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "native_delegation_capacity_remaining",
+		Help:      "Remaining native delegation capacity as last seen on-chain",
+	}, []string{"scope", "representative"})
+
+	WalletBalance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "wallet_balance_eth",
+		Help:      "Representative wallet balance as last checked, in ETH",
+	}, []string{"representative"})
+
+	WalletRunway = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "wallet_runway_batches",
+		Help:      "Estimated number of batches a representative wallet can still fund at the current gas price",
+	}, []string{"representative", "operation"})
+
+	DependencyRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "dependency_request_duration_seconds",
+		Help:      "Latency of requests made to external dependencies",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"dependency", "operation"})
+
+	DependencyErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "dependency_errors_total",
+		Help:      "Number of failed requests made to external dependencies",
+	}, []string{"dependency", "operation"})
+
+	InvalidSignatures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "invalid_signatures_total",
+		Help:      "Number of signatures returned by external dependencies or received in registrations that failed verification and were rejected",
+	}, []string{"dependency"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RegistrationsProcessed,
+		BatchesSent,
+		TransactionFailures,
+		GasUsed,
+		GasSpent,
+		ClaimsExecuted,
+		KETHClaimed,
+		CapacityRemaining,
+		WalletBalance,
+		WalletRunway,
+		DependencyRequestDuration,
+		DependencyErrors,
+		InvalidSignatures,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type instrumentedTransport struct {
+	dependency string
+	next       http.RoundTripper
+}
+
+// NewTransport returns a http.RoundTripper that records the latency and errors
+// of every request made to the given dependency
+func NewTransport(dependency string) http.RoundTripper {
+	return &instrumentedTransport{
+		dependency: dependency,
+		next:       http.DefaultTransport,
+	}
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation := requestOperation(req)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	DependencyRequestDuration.WithLabelValues(t.dependency, operation).Observe(time.Since(start).Seconds())
+
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		DependencyErrors.WithLabelValues(t.dependency, operation).Inc()
+	}
+
+	return resp, err
+}
+
+func requestOperation(req *http.Request) string {
+	// JSON-RPC requests all share the same path so label them by method instead
+	if req.Method == http.MethodPost && req.Body != nil && strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		if method := jsonRPCMethod(req); method != "" {
+			return method
+		}
+	}
+
+	// replace hex identifiers (public keys, addresses) in the path to keep label cardinality bounded
+	segments := strings.Split(req.URL.Path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "0x") {
+			segments[i] = ":id"
+		}
+	}
+	path := strings.Join(segments, "/")
+	if path == "" {
+		path = "/"
+	}
+
+	return fmt.Sprintf("%s %s", req.Method, path)
+}
+
+func jsonRPCMethod(req *http.Request) string {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var call struct {
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+	}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return "batch"
+	}
+	if err := json.Unmarshal(trimmed, &call); err != nil || call.JSONRPC == "" {
+		return ""
+	}
+
+	return call.Method
+}
@@ -0,0 +1,33 @@
+package k2
+
+import (
+	"time"
+)
+
+// monitorNodes periodically checks the health of the configured beacon and execution nodes, so that the module
+// fails over to the next healthy node when the one in use goes down, falls behind or is restarted
+func (k2 *K2Service) monitorNodes() {
+
+	ticker := time.NewTicker(k2.cfg.NodeHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-k2.exit:
+			return
+		case <-ticker.C:
+			k2.checkNodes()
+		}
+	}
+}
+
+func (k2 *K2Service) checkNodes() {
+
+	if err := k2.beacon.CheckNodes(); err != nil {
+		k2.log.WithError(err).Warn("No healthy beacon node to fail over to")
+	}
+
+	if err := k2.eth1.CheckNodes(); err != nil {
+		k2.log.WithError(err).Warn("No healthy execution node to fail over to")
+	}
+}
@@ -0,0 +1,237 @@
+package k2
+
+import (
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+)
+
+// monitorNodeOperators periodically checks whether the K2 contract banned any representative or kicked any of
+// their validators, so that the module stops using them for native delegations and claims and operators are alerted
+func (k2 *K2Service) monitorNodeOperators() {
+
+	ticker := time.NewTicker(k2.cfg.NodeOperatorCheckInterval)
+	defer ticker.Stop()
+
+	k2.checkNodeOperators()
+
+	for {
+		select {
+		case <-k2.exit:
+			return
+		case <-ticker.C:
+			k2.checkNodeOperators()
+		}
+	}
+}
+
+func (k2 *K2Service) checkNodeOperators() {
+
+	wallets := k2.validatorWallets()
+	representatives := make([]ethcommon.Address, 0, len(wallets))
+	for _, wallet := range wallets {
+		representatives = append(representatives, wallet.Address)
+	}
+
+	var kicked []k2common.KickedValidator
+
+	// the logs name the kick transaction, so are checked before the contract state
+	head, err := k2.eth1.BlockNumber()
+	if err != nil {
+		k2.log.WithError(err).Warn("Failed to get the latest block to check for kicked validators")
+	} else {
+		k2.statusLock.RLock()
+		fromBlock := k2.nodeOperatorCheckBlock + 1
+		if k2.nodeOperatorCheckBlock == 0 {
+			// the contract state covers the kicks before the module started
+			fromBlock = head
+		}
+		k2.statusLock.RUnlock()
+
+		if fromBlock <= head {
+			kickedLogs, err := k2.eth1.K2KickedValidatorLogs(representatives, fromBlock, head)
+			if err != nil {
+				k2.log.WithError(err).Warn("Failed to get the kicked validator logs from the K2 lending contract")
+			} else {
+				kicked = append(kicked, kickedLogs...)
+				k2.statusLock.Lock()
+				k2.nodeOperatorCheckBlock = head
+				k2.statusLock.Unlock()
+			}
+		}
+	}
+
+	delegatedValidators, err := k2.delegatedValidatorKeys(representatives)
+	if err != nil {
+		k2.log.WithError(err).Warn("Failed to get the delegated validators to check for kicks")
+	}
+
+	statuses, err := k2.eth1.K2NodeOperatorStatuses(representatives, delegatedValidators)
+	if err != nil {
+		k2.log.WithError(err).Warn("Failed to get the node operator statuses from the K2 contract")
+		k2.markKickedValidators(kicked)
+		return
+	}
+
+	for _, representative := range representatives {
+		for _, validator := range statuses[representative].KickedValidators {
+			kicked = append(kicked, k2common.KickedValidator{
+				ValidatorPubKey:       validator,
+				RepresentativeAddress: representative,
+			})
+		}
+	}
+
+	k2.markKickedValidators(kicked)
+	k2.markBannedRepresentatives(representatives, statuses)
+}
+
+// markKickedValidators records the validators kicked from the K2 contract and alerts on those not seen before
+func (k2 *K2Service) markKickedValidators(kicked []k2common.KickedValidator) {
+
+	var representatives []ethcommon.Address
+	newlyKicked := make(map[ethcommon.Address][]k2common.KickedValidator)
+
+	k2.statusLock.Lock()
+	for _, validator := range kicked {
+		key := strings.ToLower(validator.ValidatorPubKey.String())
+		if existing, ok := k2.kickedValidators[key]; ok {
+			if existing.TxHash == (ethcommon.Hash{}) {
+				existing.TxHash = validator.TxHash
+				k2.kickedValidators[key] = existing
+			}
+			continue
+		}
+		k2.kickedValidators[key] = validator
+		if _, ok := newlyKicked[validator.RepresentativeAddress]; !ok {
+			representatives = append(representatives, validator.RepresentativeAddress)
+		}
+		newlyKicked[validator.RepresentativeAddress] = append(newlyKicked[validator.RepresentativeAddress], validator)
+	}
+	k2.statusLock.Unlock()
+
+	for _, representative := range representatives {
+		k2.log.WithFields(logrus.Fields{
+			"representative": representative.String(),
+			"validators":     len(newlyKicked[representative]),
+		}).Error("Validators kicked from the K2 contract, they will no longer be natively delegated or used for claims")
+		k2.notify(notifier.EventValidatorKicked, []ethcommon.Address{representative}, map[string]any{
+			"validators": newlyKicked[representative],
+			"reason":     "the K2 lending contract kicked the validators of the representative (blsPublicKeyToKicked), so the module will no longer natively delegate them or use them for claims",
+		})
+	}
+}
+
+// markBannedRepresentatives records which representatives the K2 contract banned, alerting when a representative is
+// banned and logging when a ban is lifted
+func (k2 *K2Service) markBannedRepresentatives(representatives []ethcommon.Address, statuses map[ethcommon.Address]k2common.NodeOperatorStatus) {
+
+	var banned, lifted []ethcommon.Address
+
+	k2.statusLock.Lock()
+	for _, representative := range representatives {
+		status, ok := statuses[representative]
+		if !ok {
+			continue
+		}
+		if status.Banned && !k2.bannedRepresentatives[representative] {
+			k2.bannedRepresentatives[representative] = true
+			banned = append(banned, representative)
+		} else if !status.Banned && k2.bannedRepresentatives[representative] {
+			delete(k2.bannedRepresentatives, representative)
+			lifted = append(lifted, representative)
+		}
+	}
+	k2.statusLock.Unlock()
+
+	for _, representative := range banned {
+		k2.log.WithField("representative", representative.String()).Error("Representative banned by the K2 contract, it will no longer be used for native delegations or claims")
+		k2.notify(notifier.EventNodeOperatorBanned, []ethcommon.Address{representative}, map[string]any{
+			"banned": true,
+			"reason": "the K2 lending contract banned the representative (isNodeOperatorBanned), so the module will no longer natively delegate validators to it or claim its rewards",
+		})
+	}
+
+	for _, representative := range lifted {
+		k2.log.WithField("representative", representative.String()).Info("Representative ban lifted by the K2 contract, it will be used for native delegations and claims again")
+		k2.notify(notifier.EventNodeOperatorBanned, []ethcommon.Address{representative}, map[string]any{
+			"banned": false,
+			"reason": "the K2 lending contract no longer reports the representative as banned",
+		})
+	}
+}
+
+// isValidatorKicked reports whether the K2 contract kicked the validator
+func (k2 *K2Service) isValidatorKicked(validator string) bool {
+	k2.statusLock.RLock()
+	defer k2.statusLock.RUnlock()
+
+	_, ok := k2.kickedValidators[strings.ToLower(validator)]
+	return ok
+}
+
+// maxKickedValidators returns the most validators kicked from the K2 contract for any one of the representatives
+func (k2 *K2Service) maxKickedValidators(representatives []ethcommon.Address) uint64 {
+	k2.statusLock.RLock()
+	defer k2.statusLock.RUnlock()
+
+	kicked := make(map[ethcommon.Address]uint64)
+	for _, validator := range k2.kickedValidators {
+		kicked[validator.RepresentativeAddress]++
+	}
+
+	var max uint64
+	for _, representative := range representatives {
+		if kicked[representative] > max {
+			max = kicked[representative]
+		}
+	}
+	return max
+}
+
+// isRepresentativeBanned reports whether the K2 contract banned the representative
+func (k2 *K2Service) isRepresentativeBanned(representative ethcommon.Address) bool {
+	k2.statusLock.RLock()
+	defer k2.statusLock.RUnlock()
+
+	return k2.bannedRepresentatives[representative]
+}
+
+// filterBlockedDelegations removes the native delegations of the keys the node operator contract does not permit and of
+// kicked validators, or all of them if the representative is banned, from the registrations to natively delegate,
+// auditing each removal
+func (k2 *K2Service) filterBlockedDelegations(representative ethcommon.Address, registrations []k2common.K2ValidatorRegistration, permittedValidators map[string]bool) []k2common.K2ValidatorRegistration {
+
+	if k2.isRepresentativeBanned(representative) {
+		k2.log.WithFields(logrus.Fields{
+			"representative": representative.String(),
+			"validators":     len(registrations),
+		}).Warn("Representative is banned by the K2 contract, skipping native delegation")
+		k2.auditValidators(registrationPubKeys(registrations), representative, audit.DecisionNodeOperator, audit.OutcomeSkipped, "representative is banned by the K2 contract", nil)
+		return nil
+	}
+
+	allowed := make([]k2common.K2ValidatorRegistration, 0, len(registrations))
+	for _, registration := range registrations {
+		validator := registration.SignedValidatorRegistration.Message.Pubkey.String()
+		if permitted, ok := permittedValidators[validator]; ok && !permitted {
+			k2.log.WithField("validatorPubKey", validator).Debug("node operator contract does not permit the validator to be natively delegated, skipping native delegation")
+			k2.auditValidators([]string{validator}, representative, audit.DecisionCapacityCheck, audit.OutcomeSkipped, "node operator contract does not permit the key to be natively delegated", nil)
+			continue
+		}
+		if k2.isValidatorKicked(validator) {
+			k2.log.WithField("validatorPubKey", validator).Warn("Validator was kicked from the K2 contract, skipping native delegation")
+			k2.auditValidators([]string{validator}, representative, audit.DecisionNodeOperator, audit.OutcomeSkipped, "validator was kicked from the K2 contract", nil)
+			continue
+		}
+		allowed = append(allowed, registration)
+	}
+
+	return allowed
+}
@@ -0,0 +1,222 @@
+package k2
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	mevcommon "github.com/pon-network/mev-plus/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/config"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+)
+
+// recordingNotifier records the events the module notifies
+type recordingNotifier struct {
+	lock   sync.Mutex
+	events []notifier.Event
+}
+
+func (n *recordingNotifier) Notify(event notifier.Event) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.events = append(n.events, event)
+}
+
+func (n *recordingNotifier) eventTypes() []string {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	types := make([]string, len(n.events))
+	for i, event := range n.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func testK2Registration(pubkey phase0.BLSPubKey) k2common.K2ValidatorRegistration {
+	return k2common.K2ValidatorRegistration{
+		SignedValidatorRegistration: &apiv1.SignedValidatorRegistration{
+			Message: &apiv1.ValidatorRegistration{Pubkey: pubkey},
+		},
+	}
+}
+
+func TestParseConfig_NodeOperatorCheckInterval(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		interval string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "interval",
+			interval: "90s",
+			want:     90 * time.Second,
+		},
+		{
+			name:     "invalid interval",
+			interval: "often",
+			wantErr:  true,
+		},
+		{
+			name:     "zero interval",
+			interval: "0s",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+			err := k2.parseConfig(testModuleFlags(mevcommon.ModuleFlags{config.NodeOperatorCheckIntervalFlag.Name: tt.interval}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && k2.cfg.NodeOperatorCheckInterval != tt.want {
+				t.Errorf("interval = %s, want %s", k2.cfg.NodeOperatorCheckInterval, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkKickedValidators(t *testing.T) {
+
+	representative := ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+	kickTx := ethcommon.HexToHash("0x01")
+
+	k2 := NewK2Service()
+	events := &recordingNotifier{}
+	k2.RegisterNotifier(events)
+
+	// first seen in the contract state, then in the kick logs
+	k2.markKickedValidators([]k2common.KickedValidator{
+		{ValidatorPubKey: phase0.BLSPubKey{0xa1}, RepresentativeAddress: representative},
+		{ValidatorPubKey: phase0.BLSPubKey{0xa2}, RepresentativeAddress: representative},
+		{ValidatorPubKey: phase0.BLSPubKey{0xb1}, RepresentativeAddress: other},
+	})
+	k2.markKickedValidators([]k2common.KickedValidator{
+		{ValidatorPubKey: phase0.BLSPubKey{0xa1}, RepresentativeAddress: representative, TxHash: kickTx},
+	})
+
+	if !k2.isValidatorKicked(phase0.BLSPubKey{0xa1}.String()) || k2.isValidatorKicked(phase0.BLSPubKey{0xc1}.String()) {
+		t.Errorf("kicked validators = %v, want 0xa1 kicked and 0xc1 not", k2.kickedValidators)
+	}
+	if got := k2.kickedValidators[phase0.BLSPubKey{0xa1}.String()].TxHash; got != kickTx {
+		t.Errorf("kick tx = %s, want %s", got, kickTx)
+	}
+
+	// an alert for each representative with newly kicked validators, none for validators already known
+	if got := events.eventTypes(); len(got) != 2 || got[0] != notifier.EventValidatorKicked || got[1] != notifier.EventValidatorKicked {
+		t.Errorf("events = %v, want two %s", got, notifier.EventValidatorKicked)
+	}
+
+	if got := k2.maxKickedValidators([]ethcommon.Address{representative, other}); got != 2 {
+		t.Errorf("maxKickedValidators() = %d, want 2", got)
+	}
+	if got := k2.maxKickedValidators([]ethcommon.Address{other}); got != 1 {
+		t.Errorf("maxKickedValidators() = %d, want 1", got)
+	}
+}
+
+func TestMarkBannedRepresentatives(t *testing.T) {
+
+	representative := ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	k2 := NewK2Service()
+	events := &recordingNotifier{}
+	k2.RegisterNotifier(events)
+
+	representatives := []ethcommon.Address{representative, other}
+
+	k2.markBannedRepresentatives(representatives, map[ethcommon.Address]k2common.NodeOperatorStatus{
+		representative: {Banned: true},
+		other:          {},
+	})
+	if !k2.isRepresentativeBanned(representative) || k2.isRepresentativeBanned(other) {
+		t.Fatalf("banned representatives = %v, want only %s", k2.bannedRepresentatives, representative)
+	}
+
+	// still banned, or without a status, is not alerted again
+	k2.markBannedRepresentatives(representatives, map[ethcommon.Address]k2common.NodeOperatorStatus{
+		representative: {Banned: true},
+	})
+	k2.markBannedRepresentatives(representatives, nil)
+
+	k2.markBannedRepresentatives(representatives, map[ethcommon.Address]k2common.NodeOperatorStatus{
+		representative: {},
+		other:          {},
+	})
+	if k2.isRepresentativeBanned(representative) {
+		t.Errorf("ban not lifted")
+	}
+
+	if len(events.events) != 2 || events.events[0].Data["banned"] != true || events.events[1].Data["banned"] != false {
+		t.Errorf("events = %+v, want the ban and its lifting", events.events)
+	}
+}
+
+func TestFilterBlockedDelegations(t *testing.T) {
+
+	representative := ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	registrations := []k2common.K2ValidatorRegistration{
+		testK2Registration(phase0.BLSPubKey{0xa1}),
+		testK2Registration(phase0.BLSPubKey{0xa2}),
+	}
+
+	tests := []struct {
+		name      string
+		banned    bool
+		kicked    []phase0.BLSPubKey
+		permitted map[string]bool
+		want      []phase0.BLSPubKey
+	}{
+		{
+			name: "none blocked",
+			want: []phase0.BLSPubKey{{0xa1}, {0xa2}},
+		},
+		{
+			name:   "kicked validator",
+			kicked: []phase0.BLSPubKey{{0xa2}},
+			want:   []phase0.BLSPubKey{{0xa1}},
+		},
+		{
+			name:      "key not permitted",
+			permitted: map[string]bool{phase0.BLSPubKey{0xa1}.String(): false, phase0.BLSPubKey{0xa2}.String(): true},
+			want:      []phase0.BLSPubKey{{0xa2}},
+		},
+		{
+			name:   "banned representative",
+			banned: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+			if tt.banned {
+				k2.bannedRepresentatives[representative] = true
+			}
+			for _, validator := range tt.kicked {
+				k2.kickedValidators[validator.String()] = k2common.KickedValidator{ValidatorPubKey: validator, RepresentativeAddress: representative}
+			}
+
+			allowed := k2.filterBlockedDelegations(representative, registrations, tt.permitted)
+
+			if len(allowed) != len(tt.want) {
+				t.Fatalf("%d allowed, want %d", len(allowed), len(tt.want))
+			}
+			for i, registration := range allowed {
+				if registration.SignedValidatorRegistration.Message.Pubkey != tt.want[i] {
+					t.Errorf("allowed %s, want %s", registration.SignedValidatorRegistration.Message.Pubkey, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package k2
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+// getNodeOperators returns the on-chain status of each representative as a node operator of the K2 contract.
+// The delegated validators are checked for kicks when the subgraph is configured to list them
+func (k2 *K2Service) getNodeOperators(representatives []ethcommon.Address) ([]k2common.NodeOperatorStatus, error) {
+
+	if !k2.k2Enabled() {
+		// module not configured to run
+		return nil, fmt.Errorf("module not configured to run K2 contract operations")
+	}
+
+	delegatedValidators, err := k2.delegatedValidatorKeys(representatives)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := k2.eth1.K2NodeOperatorStatuses(representatives, delegatedValidators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the node operator statuses: %w", err)
+	}
+
+	result := make([]k2common.NodeOperatorStatus, 0, len(representatives))
+	for _, representative := range representatives {
+		status := statuses[representative]
+		if status.DelegatedValidators == nil {
+			// force return an empty array instead of null
+			status.DelegatedValidators = []phase0.BLSPubKey{}
+		}
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+// delegatedValidatorKeys returns the validators delegated by each representative according to the subgraph,
+// none if the subgraph is not configured
+func (k2 *K2Service) delegatedValidatorKeys(representatives []ethcommon.Address) (map[ethcommon.Address][]phase0.BLSPubKey, error) {
+
+	delegatedValidators := make(map[ethcommon.Address][]phase0.BLSPubKey)
+	if k2.cfg.SubgraphUrl == nil {
+		return delegatedValidators, nil
+	}
+
+	nodeRunnersData, err := k2.subgraph.GetValidatorsByRepresentative(representatives, 0) // set to 0 means return all available data
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegated validators: %w", err)
+	}
+	for _, nodeRunnerData := range nodeRunnersData.NodeRunners {
+		for _, validator := range nodeRunnerData.BlsPublicKeys {
+			delegatedValidators[nodeRunnerData.Id] = append(delegatedValidators[nodeRunnerData.Id], validator.Id)
+		}
+	}
+
+	return delegatedValidators, nil
+}
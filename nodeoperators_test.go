@@ -0,0 +1,30 @@
+package k2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetNodeOperators_Refused(t *testing.T) {
+
+	k2 := NewK2Service()
+
+	if _, err := k2.getNodeOperators([]ethcommon.Address{ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")}); err == nil {
+		t.Errorf("getNodeOperators() returned node operators without K2 configured")
+	}
+
+	// delegated validators are only listed from the subgraph
+	delegated, err := k2.delegatedValidatorKeys([]ethcommon.Address{ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")})
+	if err != nil || len(delegated) != 0 {
+		t.Errorf("delegatedValidatorKeys() = %v, %v, want none without a subgraph", delegated, err)
+	}
+
+	w := httptest.NewRecorder()
+	k2.handleGetNodeOperators(w, httptest.NewRequest(http.MethodGet, "/?representativeAddresses=operator", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type NotifierConfig struct {
+	WebhookUrls     []*url.URL
+	Secret          string                  // used to sign the webhook payloads
+	Events          map[string]bool         // only notify these event types, all if empty
+	Representatives map[common.Address]bool // only notify events for these representatives, all if empty
+	MaxRetries      uint64
+	DeadLetterFile  string // to append events that could not be delivered to
+}
@@ -0,0 +1,38 @@
+package notifier
+
+const (
+	EventRegistration         = "registration"
+	EventDelegation           = "delegation"
+	EventClaim                = "claim"
+	EventExit                 = "exit"
+	EventPayoutChange         = "payout_change"
+	EventTransactionFailed    = "transaction_failed"
+	EventCapacityExhausted    = "capacity_exhausted"
+	EventNoRegistrations      = "no_registrations"
+	EventDelegationDeferred   = "delegation_deferred"
+	EventClaimRecipientChange = "claim_recipient_change"
+	EventValidatorKicked      = "validator_kicked"
+	EventNodeOperatorBanned   = "node_operator_banned"
+)
+
+var EventTypes = []string{
+	EventRegistration,
+	EventDelegation,
+	EventClaim,
+	EventExit,
+	EventPayoutChange,
+	EventTransactionFailed,
+	EventCapacityExhausted,
+	EventNoRegistrations,
+	EventDelegationDeferred,
+	EventClaimRecipientChange,
+	EventValidatorKicked,
+	EventNodeOperatorBanned,
+}
+
+const (
+	SignatureHeader = "X-K2-Signature"
+	EventHeader     = "X-K2-Event"
+
+	queueSize = 256
+)
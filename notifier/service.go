@@ -0,0 +1,235 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier/config"
+)
+
+// NotifierService posts signed JSON payloads of module events to the configured webhooks
+type NotifierService struct {
+	cfg    config.NotifierConfig
+	client *http.Client
+	log    *logrus.Entry
+
+	queue   chan Event
+	done    chan struct{}
+	wg      sync.WaitGroup
+	lock    sync.RWMutex
+	running bool
+
+	deadLetterLock sync.Mutex
+}
+
+func NewNotifierService() *NotifierService {
+	return &NotifierService{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		queue: make(chan Event, queueSize),
+		done:  make(chan struct{}),
+	}
+}
+
+func (s *NotifierService) Configure(cfg config.NotifierConfig, logger *logrus.Entry) error {
+
+	if len(cfg.WebhookUrls) == 0 {
+		return fmt.Errorf("notifierservice: no webhook urls set, cannot configure service")
+	}
+
+	if cfg.Secret == "" {
+		return fmt.Errorf("notifierservice: secret not set, cannot sign webhook payloads")
+	}
+
+	s.cfg = cfg
+	s.log = logger.WithField("service", "notifier")
+
+	s.lock.Lock()
+	s.running = true
+	s.lock.Unlock()
+
+	go s.run()
+
+	return nil
+}
+
+// Notify queues the event for delivery without blocking the caller
+func (s *NotifierService) Notify(event Event) {
+
+	if !s.shouldNotify(event) {
+		return
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.running {
+		return
+	}
+
+	select {
+	case s.queue <- event:
+	default:
+		for _, webhookUrl := range s.cfg.WebhookUrls {
+			s.writeDeadLetter(event, webhookUrl, fmt.Errorf("notification queue is full"))
+		}
+	}
+}
+
+// Stop waits for queued events to be delivered
+func (s *NotifierService) Stop() {
+	s.lock.Lock()
+	if !s.running {
+		s.lock.Unlock()
+		return
+	}
+	s.running = false
+	close(s.queue)
+	s.lock.Unlock()
+
+	<-s.done
+	s.wg.Wait()
+}
+
+func (s *NotifierService) shouldNotify(event Event) bool {
+
+	if len(s.cfg.Events) > 0 && !s.cfg.Events[event.Type] {
+		return false
+	}
+
+	// events not specific to a representative are always sent
+	if len(s.cfg.Representatives) == 0 || len(event.RepresentativeAddresses) == 0 {
+		return true
+	}
+
+	for _, representative := range event.RepresentativeAddresses {
+		if s.cfg.Representatives[representative] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *NotifierService) run() {
+	defer close(s.done)
+
+	for event := range s.queue {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			s.log.WithError(err).WithField("event", event.Type).Error("Failed to encode webhook payload")
+			continue
+		}
+
+		for _, webhookUrl := range s.cfg.WebhookUrls {
+			s.wg.Add(1)
+			go func(event Event, webhookUrl *url.URL) {
+				defer s.wg.Done()
+				s.deliver(event, webhookUrl, payload)
+			}(event, webhookUrl)
+		}
+	}
+}
+
+func (s *NotifierService) deliver(event Event, webhookUrl *url.URL, payload []byte) {
+
+	logger := s.log.WithFields(logrus.Fields{
+		"event": event.Type,
+		"url":   webhookUrl.String(),
+	})
+
+	var err error
+	backoff := time.Second
+	for attempt := uint64(0); attempt <= s.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		err = s.post(event, webhookUrl, payload)
+		if err == nil {
+			logger.Debug("Webhook notification delivered")
+			return
+		}
+
+		logger.WithError(err).WithField("attempt", attempt+1).Debug("Webhook notification failed")
+	}
+
+	s.writeDeadLetter(event, webhookUrl, err)
+}
+
+func (s *NotifierService) post(event Event, webhookUrl *url.URL, payload []byte) error {
+
+	req, err := http.NewRequest(http.MethodPost, webhookUrl.String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	mac.Write(payload)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("invalid response (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *NotifierService) writeDeadLetter(event Event, webhookUrl *url.URL, deliveryErr error) {
+
+	s.log.WithError(deliveryErr).WithFields(logrus.Fields{
+		"event": event.Type,
+		"url":   webhookUrl.String(),
+	}).Error("Failed to deliver webhook notification")
+
+	if s.cfg.DeadLetterFile == "" {
+		return
+	}
+
+	entry, err := json.Marshal(deadLetter{
+		Event:    event,
+		Url:      webhookUrl.String(),
+		Error:    deliveryErr.Error(),
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		s.log.WithError(err).Error("Failed to encode dead letter")
+		return
+	}
+
+	s.deadLetterLock.Lock()
+	defer s.deadLetterLock.Unlock()
+
+	file, err := os.OpenFile(s.cfg.DeadLetterFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		s.log.WithError(err).Error("Failed to open dead letter file")
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(entry, '\n')); err != nil {
+		s.log.WithError(err).Error("Failed to write dead letter")
+	}
+}
@@ -0,0 +1,246 @@
+package notifier_test
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/internal/testserver"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier/config"
+)
+
+const testSecret = "webhook-secret"
+
+type webhookRequest struct {
+	event     string
+	signature string
+	body      []byte
+}
+
+// testWebhook records the notifications it receives and answers with status
+type testWebhook struct {
+	status   int
+	requests []webhookRequest
+}
+
+func newTestWebhook(t *testing.T, status int) *testserver.Server[testWebhook] {
+	return testserver.New(t, testWebhook{status: status}, func(webhook *testWebhook, w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		webhook.requests = append(webhook.requests, webhookRequest{
+			event:     r.Header.Get(notifier.EventHeader),
+			signature: r.Header.Get(notifier.SignatureHeader),
+			body:      body,
+		})
+		w.WriteHeader(webhook.status)
+	})
+}
+
+func newTestNotifier(t *testing.T, cfg config.NotifierConfig) *notifier.NotifierService {
+	t.Helper()
+
+	cfg.Secret = testSecret
+	s := notifier.NewNotifierService()
+	if err := s.Configure(cfg, logrus.NewEntry(logrus.New())); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	return s
+}
+
+func TestNotifierService_Configure(t *testing.T) {
+
+	webhookUrl, _ := url.Parse("http://localhost:9000/hook")
+
+	tests := []struct {
+		name    string
+		cfg     config.NotifierConfig
+		wantErr bool
+	}{
+		{
+			name: "configured",
+			cfg:  config.NotifierConfig{WebhookUrls: []*url.URL{webhookUrl}, Secret: testSecret},
+		},
+		{
+			name:    "no webhook urls",
+			cfg:     config.NotifierConfig{Secret: testSecret},
+			wantErr: true,
+		},
+		{
+			name:    "no secret",
+			cfg:     config.NotifierConfig{WebhookUrls: []*url.URL{webhookUrl}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := notifier.NewNotifierService()
+			err := s.Configure(tt.cfg, logrus.NewEntry(logrus.New()))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Configure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				s.Stop()
+			}
+		})
+	}
+}
+
+func TestNotifierService_Delivery(t *testing.T) {
+
+	webhooks := []*testserver.Server[testWebhook]{newTestWebhook(t, http.StatusOK), newTestWebhook(t, http.StatusNoContent)}
+
+	s := newTestNotifier(t, config.NotifierConfig{WebhookUrls: []*url.URL{webhooks[0].URL, webhooks[1].URL}})
+
+	event := notifier.Event{
+		Type:      notifier.EventRegistration,
+		Timestamp: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC),
+		Data:      map[string]any{"validators": float64(2)},
+	}
+	s.Notify(event)
+	s.Stop()
+
+	for i, webhook := range webhooks {
+		requests := webhook.State().requests
+		if len(requests) != 1 {
+			t.Fatalf("webhook %d received %d notifications, want 1", i, len(requests))
+		}
+		request := requests[0]
+
+		if request.event != notifier.EventRegistration {
+			t.Errorf("webhook %d event header = %q, want %q", i, request.event, notifier.EventRegistration)
+		}
+
+		mac := hmac.New(sha256.New, []byte(testSecret))
+		mac.Write(request.body)
+		if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); request.signature != want {
+			t.Errorf("webhook %d signature = %q, want %q", i, request.signature, want)
+		}
+
+		var got notifier.Event
+		if err := json.Unmarshal(request.body, &got); err != nil {
+			t.Fatalf("webhook %d payload cannot be decoded: %v", i, err)
+		}
+		if got.Type != event.Type || !got.Timestamp.Equal(event.Timestamp) || got.Data["validators"] != event.Data["validators"] {
+			t.Errorf("webhook %d payload = %+v, want %+v", i, got, event)
+		}
+	}
+}
+
+func TestNotifierService_Filters(t *testing.T) {
+
+	representative := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	tests := []struct {
+		name       string
+		cfg        config.NotifierConfig
+		event      notifier.Event
+		wantNotify bool
+	}{
+		{
+			name:       "no filters",
+			event:      notifier.Event{Type: notifier.EventClaim},
+			wantNotify: true,
+		},
+		{
+			name:       "event type selected",
+			cfg:        config.NotifierConfig{Events: map[string]bool{notifier.EventClaim: true}},
+			event:      notifier.Event{Type: notifier.EventClaim},
+			wantNotify: true,
+		},
+		{
+			name:  "event type not selected",
+			cfg:   config.NotifierConfig{Events: map[string]bool{notifier.EventClaim: true}},
+			event: notifier.Event{Type: notifier.EventDelegation},
+		},
+		{
+			name:       "representative selected",
+			cfg:        config.NotifierConfig{Representatives: map[common.Address]bool{representative: true}},
+			event:      notifier.Event{Type: notifier.EventDelegation, RepresentativeAddresses: []common.Address{other, representative}},
+			wantNotify: true,
+		},
+		{
+			name:  "representative not selected",
+			cfg:   config.NotifierConfig{Representatives: map[common.Address]bool{representative: true}},
+			event: notifier.Event{Type: notifier.EventDelegation, RepresentativeAddresses: []common.Address{other}},
+		},
+		{
+			name:       "event for no representative",
+			cfg:        config.NotifierConfig{Representatives: map[common.Address]bool{representative: true}},
+			event:      notifier.Event{Type: notifier.EventCapacityExhausted},
+			wantNotify: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := newTestWebhook(t, http.StatusOK)
+
+			tt.cfg.WebhookUrls = []*url.URL{webhook.URL}
+			s := newTestNotifier(t, tt.cfg)
+			s.Notify(tt.event)
+			s.Stop()
+
+			if got := len(webhook.State().requests) == 1; got != tt.wantNotify {
+				t.Errorf("notified = %v, want %v", got, tt.wantNotify)
+			}
+		})
+	}
+}
+
+func TestNotifierService_DeadLetter(t *testing.T) {
+
+	webhook := newTestWebhook(t, http.StatusInternalServerError)
+	deadLetterFile := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+
+	s := newTestNotifier(t, config.NotifierConfig{
+		WebhookUrls:    []*url.URL{webhook.URL},
+		MaxRetries:     1,
+		DeadLetterFile: deadLetterFile,
+	})
+	s.Notify(notifier.Event{Type: notifier.EventTransactionFailed})
+	s.Stop()
+
+	if got := len(webhook.State().requests); got != 2 {
+		t.Errorf("webhook received %d notifications, want 2", got)
+	}
+
+	file, err := os.Open(deadLetterFile)
+	if err != nil {
+		t.Fatalf("dead letter file not written: %v", err)
+	}
+	defer file.Close()
+
+	var deadLetters []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var deadLetter map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &deadLetter); err != nil {
+			t.Fatalf("dead letter cannot be decoded: %v", err)
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	if len(deadLetters) != 1 {
+		t.Fatalf("%d dead letters written, want 1", len(deadLetters))
+	}
+	if deadLetters[0]["url"] != webhook.URL.String() {
+		t.Errorf("dead letter url = %v, want %v", deadLetters[0]["url"], webhook.URL.String())
+	}
+	if event, _ := deadLetters[0]["event"].(map[string]any); event["type"] != notifier.EventTransactionFailed {
+		t.Errorf("dead letter event = %v, want type %s", deadLetters[0]["event"], notifier.EventTransactionFailed)
+	}
+}
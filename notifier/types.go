@@ -0,0 +1,26 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Notifier is implemented by anything that wants to be informed of module events
+type Notifier interface {
+	Notify(event Event)
+}
+
+type Event struct {
+	Type                    string           `json:"type"`
+	Timestamp               time.Time        `json:"timestamp"`
+	RepresentativeAddresses []common.Address `json:"representativeAddresses,omitempty"`
+	Data                    map[string]any   `json:"data,omitempty"`
+}
+
+type deadLetter struct {
+	Event    Event     `json:"event"`
+	Url      string    `json:"url"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
@@ -0,0 +1,146 @@
+package k2
+
+import (
+	"math/big"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/ethservice"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+	notifierConfig "github.com/restaking-cloud/native-delegation-for-plus/notifier/config"
+	"github.com/restaking-cloud/native-delegation-for-plus/stream"
+)
+
+var transactionEventTypes = map[string]string{
+	ethservice.TransactionStatusSent:     stream.EventTransactionSent,
+	ethservice.TransactionStatusMined:    stream.EventTransactionMined,
+	ethservice.TransactionStatusReverted: stream.EventTransactionMined,
+}
+
+// RegisterNotifier adds a notifier to be informed of module events
+// in addition to any webhooks configured for the module
+func (k2 *K2Service) RegisterNotifier(n notifier.Notifier) {
+	k2.statusLock.Lock()
+	defer k2.statusLock.Unlock()
+
+	k2.notifiers = append(k2.notifiers, n)
+}
+
+func (k2 *K2Service) configureNotifier() error {
+
+	if len(k2.cfg.WebhookUrls) == 0 {
+		return nil
+	}
+
+	events := make(map[string]bool)
+	for _, event := range k2.cfg.WebhookEvents {
+		events[event] = true
+	}
+
+	representatives := make(map[ethcommon.Address]bool)
+	for _, representative := range k2.cfg.WebhookRepresentatives {
+		representatives[representative] = true
+	}
+
+	err := k2.notifierService.Configure(notifierConfig.NotifierConfig{
+		WebhookUrls:     k2.cfg.WebhookUrls,
+		Secret:          k2.cfg.WebhookSecret,
+		Events:          events,
+		Representatives: representatives,
+		MaxRetries:      k2.cfg.WebhookMaxRetries,
+		DeadLetterFile:  k2.cfg.WebhookDeadLetterFile,
+	}, k2.log)
+	if err != nil {
+		return err
+	}
+
+	k2.RegisterNotifier(k2.notifierService)
+
+	return nil
+}
+
+func (k2 *K2Service) notify(eventType string, representatives []ethcommon.Address, data map[string]any) {
+
+	event := notifier.Event{
+		Type:                    eventType,
+		Timestamp:               time.Now().UTC(),
+		RepresentativeAddresses: representatives,
+		Data:                    data,
+	}
+
+	k2.statusLock.RLock()
+	defer k2.statusLock.RUnlock()
+
+	for _, n := range k2.notifiers {
+		n.Notify(event)
+	}
+}
+
+// publish sends an event to the stream subscribers of the topic only
+func (k2 *K2Service) publish(topic string, eventType string, representatives []ethcommon.Address, data map[string]any) {
+	k2.stream.Publish(topic, notifier.Event{
+		Type:                    eventType,
+		Timestamp:               time.Now().UTC(),
+		RepresentativeAddresses: representatives,
+		Data:                    data,
+	})
+}
+
+func (k2 *K2Service) publishTransactionUpdate(update ethservice.TransactionUpdate) {
+	k2.publish(stream.TopicTransactions, transactionEventTypes[update.Status], []ethcommon.Address{update.Representative}, map[string]any{
+		"transaction": update,
+	})
+}
+
+// notifyCapacity publishes the native delegation capacity of the given scope to stream subscribers, and notifies
+// when it becomes exhausted, once per exhaustion rather than on every batch processed while exhausted
+func (k2 *K2Service) notifyCapacity(scope string, representative ethcommon.Address, max *big.Int, current *big.Int) {
+
+	var representatives []ethcommon.Address
+	if scope == metrics.CapacityScopeIndividual {
+		representatives = append(representatives, representative)
+	}
+
+	k2.publish(stream.TopicCapacity, stream.EventCapacityUpdated, representatives, map[string]any{
+		"scope":    scope,
+		"max":      max.String(),
+		"consumed": current.String(),
+	})
+
+	exhausted := max.Cmp(current) <= 0
+	key := scope + representative.String()
+
+	k2.statusLock.Lock()
+	wasExhausted := k2.capacityExhausted[key]
+	k2.capacityExhausted[key] = exhausted
+	k2.statusLock.Unlock()
+
+	if !exhausted || wasExhausted {
+		return
+	}
+
+	k2.notify(notifier.EventCapacityExhausted, representatives, map[string]any{
+		"scope":    scope,
+		"max":      max.String(),
+		"consumed": current.String(),
+	})
+}
+
+func registrationPubKeys(registrations []k2common.K2ValidatorRegistration) []string {
+	pubkeys := make([]string, 0, len(registrations))
+	for _, registration := range registrations {
+		pubkeys = append(pubkeys, registration.SignedValidatorRegistration.Message.Pubkey.String())
+	}
+	return pubkeys
+}
+
+func claimRepresentatives(claims []k2common.K2Claim) []ethcommon.Address {
+	representatives := make([]ethcommon.Address, 0, len(claims))
+	for _, claim := range claims {
+		representatives = append(representatives, claim.RepresentativeAddress)
+	}
+	return representatives
+}
@@ -0,0 +1,237 @@
+package k2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/stream"
+)
+
+const (
+	remoteListTimeout         = 30 * time.Second
+	maxRemoteListSize         = 10 << 20
+	remoteListSignatureSuffix = ".sig"
+	remoteListSignedPrefix    = "k2 list version %d expiring %d\n"
+)
+
+var errRemoteList = errors.New("list is fetched from a url and cannot be changed through the API")
+
+// remoteList is the last list document applied from a url
+type remoteList struct {
+	etag    string
+	content []byte
+	version uint64 // version of the signature the document was applied with, 0 if signatures are not checked
+}
+
+// remoteListSignature is the detached signature of a list document. The version and expiry are signed
+// with the document so that an older signed document cannot be served again in place of a newer one
+type remoteListSignature struct {
+	Version   uint64 `json:"version"`
+	ExpiresAt int64  `json:"expiresAt"` // unix timestamp in seconds
+	Signature string `json:"signature"`
+}
+
+func isRemoteList(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// readListSource reads a list from its file or url and applies it with the load callback.
+// A list fetched from a url is only applied if it changed since it was last applied
+func (k2 *K2Service) readListSource(label string, source string, load func(string, []byte) error) error {
+
+	if !isRemoteList(source) {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return fmt.Errorf("failed to read %s file: %w", label, err)
+		}
+		return load(source, content)
+	}
+
+	k2.remoteListLock.Lock()
+	applied := k2.remoteLists[source]
+	k2.remoteListLock.Unlock()
+
+	content, etag, changed, err := k2.fetchRemoteList(source, applied.etag)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", label, err)
+	}
+	if !changed || bytes.Equal(content, applied.content) {
+		// servers without etag support return the full document every time
+		return nil
+	}
+
+	var version uint64
+	if k2.cfg.ListSignerAddress != (ethcommon.Address{}) {
+		version, err = k2.verifyRemoteList(source, content, applied.version, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to verify %s: %w", label, err)
+		}
+	}
+
+	if err := load(source, content); err != nil {
+		return err
+	}
+
+	k2.remoteListLock.Lock()
+	k2.remoteLists[source] = remoteList{etag: etag, content: content, version: version}
+	k2.remoteListLock.Unlock()
+
+	return nil
+}
+
+// fetchRemoteList fetches a list document from its url, returning changed false if the server reports
+// it unchanged since the document with the given etag
+func (k2 *K2Service) fetchRemoteList(listUrl string, etag string) (content []byte, newEtag string, changed bool, err error) {
+
+	req, err := http.NewRequest(http.MethodGet, listUrl, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := k2.listClient.Do(req)
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	content, err = io.ReadAll(io.LimitReader(resp.Body, maxRemoteListSize+1))
+	if err != nil {
+		return nil, "", false, err
+	}
+	if len(content) > maxRemoteListSize {
+		return nil, "", false, fmt.Errorf("list exceeds the maximum size of %d bytes", maxRemoteListSize)
+	}
+
+	return content, resp.Header.Get("ETag"), true, nil
+}
+
+// verifyRemoteList fetches the detached signature of a list document, published at the list url with the signature
+// suffix, and checks it is an EIP-191 personal signature by the list signer of the document prefixed with its version
+// and expiry. Documents that expired or are not newer than the version last applied are refused, and the version of
+// the document is returned
+func (k2 *K2Service) verifyRemoteList(listUrl string, content []byte, appliedVersion uint64, now time.Time) (uint64, error) {
+
+	signatureUrl, err := url.Parse(listUrl)
+	if err != nil {
+		return 0, err
+	}
+	signatureUrl.Path += remoteListSignatureSuffix
+
+	resp, err := k2.listClient.Get(signatureUrl.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch list signature: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to fetch list signature: unexpected status code %d", resp.StatusCode)
+	}
+
+	var signature remoteListSignature
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&signature); err != nil {
+		return 0, fmt.Errorf("invalid list signature: %w", err)
+	}
+
+	return signature.Version, checkRemoteListSignature(signature, content, k2.cfg.ListSignerAddress, appliedVersion, now)
+}
+
+// checkRemoteListSignature checks the signature of a list document was made by the signer, is not expired
+// and is for a version newer than the version last applied
+func checkRemoteListSignature(signature remoteListSignature, content []byte, signer ethcommon.Address, appliedVersion uint64, now time.Time) error {
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature.Signature), "0x"))
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return fmt.Errorf("invalid list signature")
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(accounts.TextHash(signedRemoteList(signature.Version, signature.ExpiresAt, content)), sig)
+	if err != nil {
+		return fmt.Errorf("invalid list signature: %w", err)
+	}
+	if recovered := crypto.PubkeyToAddress(*publicKey); recovered != signer {
+		return fmt.Errorf("list signed by %s, expected %s", recovered.String(), signer.String())
+	}
+
+	if !now.Before(time.Unix(signature.ExpiresAt, 0)) {
+		return fmt.Errorf("list signature expired at %s", time.Unix(signature.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+	if signature.Version <= appliedVersion {
+		return fmt.Errorf("list version %d is not newer than the version %d in use", signature.Version, appliedVersion)
+	}
+
+	return nil
+}
+
+// signedRemoteList returns the message signed for a list document, the document prefixed with its version and expiry
+func signedRemoteList(version uint64, expiresAt int64, content []byte) []byte {
+	return append([]byte(fmt.Sprintf(remoteListSignedPrefix, version, expiresAt)), content...)
+}
+
+// pollRemoteList periodically fetches a list from its url and applies it when it changes
+func (k2 *K2Service) pollRemoteList(label string, listUrl string, readCallback func(string) error) {
+
+	ticker := time.NewTicker(k2.cfg.ListPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-k2.exit:
+			k2.log.Debugf("Stopping %s poller", label)
+			return
+		case <-ticker.C:
+			k2.remoteListLock.Lock()
+			applied := k2.remoteLists[listUrl].content
+			k2.remoteListLock.Unlock()
+
+			err := readCallback(listUrl)
+			if err != nil {
+				k2.log.WithError(err).Warnf("Failed to read %s with provided callback", label)
+				k2.publish(stream.TopicLists, stream.EventListReloaded, nil, map[string]any{
+					"list":   label,
+					"url":    listUrl,
+					"status": "failed",
+					"error":  err.Error(),
+				})
+				continue
+			}
+
+			k2.remoteListLock.Lock()
+			reloaded := !bytes.Equal(k2.remoteLists[listUrl].content, applied)
+			k2.remoteListLock.Unlock()
+
+			if reloaded {
+				k2.publish(stream.TopicLists, stream.EventListReloaded, nil, map[string]any{
+					"list":   label,
+					"url":    listUrl,
+					"status": "reloaded",
+				})
+			}
+		}
+	}
+}
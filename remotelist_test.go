@@ -0,0 +1,272 @@
+package k2
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/internal/testserver"
+)
+
+func signRemoteList(t *testing.T, key *ecdsa.PrivateKey, version uint64, expiresAt int64, content []byte) remoteListSignature {
+	t.Helper()
+	sig, err := crypto.Sign(accounts.TextHash(signedRemoteList(version, expiresAt, content)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return remoteListSignature{Version: version, ExpiresAt: expiresAt, Signature: hexutil.Encode(sig)}
+}
+
+func TestCheckRemoteListSignature(t *testing.T) {
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	now := time.Unix(1700000000, 0)
+	content := []byte(`[{"publicKey": "0x01"}]`)
+	expiresAt := now.Add(time.Hour).Unix()
+
+	tests := []struct {
+		name           string
+		signature      func() remoteListSignature
+		appliedVersion uint64
+		wantErr        string
+	}{
+		{
+			name:      "valid",
+			signature: func() remoteListSignature { return signRemoteList(t, key, 2, expiresAt, content) },
+		},
+		{
+			name: "valid with recovery id offset",
+			signature: func() remoteListSignature {
+				signature := signRemoteList(t, key, 2, expiresAt, content)
+				sig := hexutil.MustDecode(signature.Signature)
+				sig[crypto.RecoveryIDOffset] += 27
+				signature.Signature = hexutil.Encode(sig)
+				return signature
+			},
+		},
+		{
+			name:      "signed by another key",
+			signature: func() remoteListSignature { return signRemoteList(t, otherKey, 2, expiresAt, content) },
+			wantErr:   "expected " + signer.String(),
+		},
+		{
+			name:      "signed another document",
+			signature: func() remoteListSignature { return signRemoteList(t, key, 2, expiresAt, []byte("[]")) },
+			wantErr:   "expected " + signer.String(),
+		},
+		{
+			name: "version changed after signing",
+			signature: func() remoteListSignature {
+				signature := signRemoteList(t, key, 2, expiresAt, content)
+				signature.Version = 3
+				return signature
+			},
+			wantErr: "expected " + signer.String(),
+		},
+		{
+			name: "expiry changed after signing",
+			signature: func() remoteListSignature {
+				signature := signRemoteList(t, key, 2, expiresAt, content)
+				signature.ExpiresAt++
+				return signature
+			},
+			wantErr: "expected " + signer.String(),
+		},
+		{
+			name:      "expired",
+			signature: func() remoteListSignature { return signRemoteList(t, key, 2, now.Unix(), content) },
+			wantErr:   "list signature expired",
+		},
+		{
+			name:           "same version as applied",
+			signature:      func() remoteListSignature { return signRemoteList(t, key, 2, expiresAt, content) },
+			appliedVersion: 2,
+			wantErr:        "list version 2 is not newer than the version 2 in use",
+		},
+		{
+			name:           "older version than applied",
+			signature:      func() remoteListSignature { return signRemoteList(t, key, 1, expiresAt, content) },
+			appliedVersion: 2,
+			wantErr:        "list version 1 is not newer than the version 2 in use",
+		},
+		{
+			name: "malformed signature",
+			signature: func() remoteListSignature {
+				return remoteListSignature{Version: 2, ExpiresAt: expiresAt, Signature: "0x1234"}
+			},
+			wantErr: "invalid list signature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRemoteListSignature(tt.signature(), content, signer, tt.appliedVersion, now)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("checkRemoteListSignature() error = %v", err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("checkRemoteListSignature() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// testListServer is the list document and signature served, answering conditional requests if it has an etag
+type testListServer struct {
+	content     string
+	etag        string
+	signature   remoteListSignature
+	ifNoneMatch string // If-None-Match header of the last list request
+}
+
+func newTestListServer(t *testing.T) *testserver.Server[testListServer] {
+	t.Helper()
+
+	return testserver.New(t, testListServer{}, func(s *testListServer, w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, remoteListSignatureSuffix) {
+			json.NewEncoder(w).Encode(s.signature)
+			return
+		}
+
+		s.ifNoneMatch = r.Header.Get("If-None-Match")
+		if s.etag != "" {
+			if s.ifNoneMatch == s.etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", s.etag)
+		}
+		w.Write([]byte(s.content))
+	})
+}
+
+// listSourceStep is a change to the served list followed by a read of the list
+type listSourceStep struct {
+	update          func(*testListServer)
+	wantIfNoneMatch string
+	wantLoaded      string // content applied by the read, empty if not applied
+	wantErr         string
+}
+
+func TestReadListSource(t *testing.T) {
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name   string
+		signed bool
+		steps  []listSourceStep
+	}{
+		{
+			name: "etag",
+			steps: []listSourceStep{
+				{update: func(s *testListServer) { s.content, s.etag = "[1]", `"v1"` }, wantLoaded: "[1]"},
+				{update: func(*testListServer) {}, wantIfNoneMatch: `"v1"`},
+				{update: func(s *testListServer) { s.content, s.etag = "[2]", `"v2"` }, wantIfNoneMatch: `"v1"`, wantLoaded: "[2]"},
+				{update: func(*testListServer) {}, wantIfNoneMatch: `"v2"`},
+			},
+		},
+		{
+			name: "no etag",
+			steps: []listSourceStep{
+				{update: func(s *testListServer) { s.content = "[1]" }, wantLoaded: "[1]"},
+				{update: func(*testListServer) {}},
+				{update: func(s *testListServer) { s.content = "[2]" }, wantLoaded: "[2]"},
+			},
+		},
+		{
+			name:   "signed",
+			signed: true,
+			steps: []listSourceStep{
+				{
+					update: func(s *testListServer) {
+						s.content, s.etag = "[1]", `"v1"`
+						s.signature = signRemoteList(t, key, 1, expiresAt, []byte(s.content))
+					},
+					wantLoaded: "[1]",
+				},
+				{
+					// a newer document served with the signature of the document in use
+					update:          func(s *testListServer) { s.content, s.etag = "[2]", `"v2"` },
+					wantIfNoneMatch: `"v1"`,
+					wantErr:         "failed to verify",
+				},
+				{
+					// an older signed document replayed
+					update: func(s *testListServer) {
+						s.content, s.etag = "[0]", `"v0"`
+						s.signature = signRemoteList(t, key, 1, expiresAt, []byte(s.content))
+					},
+					wantIfNoneMatch: `"v1"`,
+					wantErr:         "not newer than the version 1 in use",
+				},
+				{
+					update: func(s *testListServer) {
+						s.content, s.etag = "[2]", `"v2"`
+						s.signature = signRemoteList(t, key, 2, expiresAt, []byte(s.content))
+					},
+					wantIfNoneMatch: `"v1"`,
+					wantLoaded:      "[2]",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestListServer(t)
+			listUrl := server.URL.String() + "/exclusions.json"
+
+			k2 := NewK2Service()
+			if tt.signed {
+				k2.cfg.ListSignerAddress = crypto.PubkeyToAddress(key.PublicKey)
+			} else {
+				k2.cfg.ListSignerAddress = ethcommon.Address{}
+			}
+
+			for i, step := range tt.steps {
+				server.Set(step.update)
+
+				var loaded string
+				err := k2.readListSource("exclusion list", listUrl, func(source string, content []byte) error {
+					if source != listUrl {
+						t.Errorf("step %d: loaded from %s, want %s", i, source, listUrl)
+					}
+					loaded = string(content)
+					return nil
+				})
+				if step.wantErr == "" && err != nil {
+					t.Fatalf("step %d: readListSource() error = %v", i, err)
+				} else if step.wantErr != "" && (err == nil || !strings.Contains(err.Error(), step.wantErr)) {
+					t.Fatalf("step %d: readListSource() error = %v, want %q", i, err, step.wantErr)
+				}
+				if loaded != step.wantLoaded {
+					t.Errorf("step %d: loaded %q, want %q", i, loaded, step.wantLoaded)
+				}
+				if ifNoneMatch := server.State().ifNoneMatch; ifNoneMatch != step.wantIfNoneMatch {
+					t.Errorf("step %d: If-None-Match = %q, want %q", i, ifNoneMatch, step.wantIfNoneMatch)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package k2
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"sort"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/config"
+)
+
+// readRepresentativeAssignments loads the representatives previously selected for each fee recipient,
+// starting without any if the assignment file does not exist yet
+func (k2 *K2Service) readRepresentativeAssignments(filePath string) error {
+
+	var entries []k2common.CustomPayoutRepresentative
+	err := readListEntries(filePath, &entries)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read representative assignments: %w", err)
+	}
+
+	assignments := make(map[string]ethcommon.Address, len(entries))
+	for _, entry := range entries {
+		if entry.FeeRecipientAddress == (ethcommon.Address{}) || entry.RepresentativeAddress == (ethcommon.Address{}) {
+			return fmt.Errorf("invalid representative assignment [%s, %s], must specify both a feeRecipientAddress and a representativeAddress", entry.FeeRecipientAddress.String(), entry.RepresentativeAddress.String())
+		}
+		assignments[strings.ToLower(entry.FeeRecipientAddress.String())] = entry.RepresentativeAddress
+	}
+
+	k2.lock.Lock()
+	defer k2.lock.Unlock()
+
+	k2.representativeAssignments = assignments
+
+	k2.log.Infof("Representative assignments loaded for %d fee recipients", len(assignments))
+
+	return nil
+}
+
+// writeRepresentativeAssignments persists the representative selected for each fee recipient.
+// k2.lock must be held by the caller
+func (k2 *K2Service) writeRepresentativeAssignments() error {
+
+	entries := make([]k2common.CustomPayoutRepresentative, 0, len(k2.representativeAssignments))
+	for feeRecipient, representative := range k2.representativeAssignments {
+		entries = append(entries, k2common.CustomPayoutRepresentative{
+			RepresentativeAddress: representative,
+			FeeRecipientAddress:   ethcommon.HexToAddress(feeRecipient),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].FeeRecipientAddress.String()) < strings.ToLower(entries[j].FeeRecipientAddress.String())
+	})
+
+	return writeListFile(k2.cfg.RepresentativeAssignmentFile, entries)
+}
+
+// selectRepresentative selects, among the candidate wallets eligible for the fee recipient, the representative for validators
+// paying to a fee recipient without a mapped representative. The priority strategy takes the first candidate in the configured
+// order. The other strategies keep the representative assigned to the fee recipient while it remains a candidate, otherwise
+// select one by remaining individual capacity, ETH balance or in turn, and persist the assignment if record is set.
+// k2.lock must be held by the caller
+func (k2 *K2Service) selectRepresentative(feeRecipient ethcommon.Address, candidates []k2common.ValidatorWallet, record bool) (k2common.ValidatorWallet, string, error) {
+
+	if len(candidates) == 0 {
+		return k2common.ValidatorWallet{}, "", fmt.Errorf("no eligible representative for fee recipient %s", feeRecipient.String())
+	}
+
+	strategy := k2.cfg.RepresentativeStrategy
+	if strategy == config.RepresentativeStrategyPriority {
+		return candidates[0], "first eligible wallet in the configured order", nil
+	}
+
+	if assigned, ok := k2.representativeAssignments[strings.ToLower(feeRecipient.String())]; ok {
+		for _, wallet := range candidates {
+			if wallet.Address == assigned {
+				return wallet, "representative previously assigned to the fee recipient", nil
+			}
+		}
+	}
+
+	var selected k2common.ValidatorWallet
+	var reason string
+	switch strategy {
+	case config.RepresentativeStrategyCapacity:
+		addresses := make([]ethcommon.Address, len(candidates))
+		for i, wallet := range candidates {
+			addresses[i] = wallet.Address
+		}
+		delegations, err := k2.eth1.TotalNativeDelegationsForRepresentatives(addresses)
+		if err != nil {
+			return selected, "", fmt.Errorf("failed to get the native delegations of the representatives: %w", err)
+		}
+		individualMax, err := k2.eth1.IndividualMaxNativeDelegation()
+		if err != nil {
+			return selected, "", fmt.Errorf("failed to get the individual max native delegation: %w", err)
+		}
+		var most *big.Int
+		for _, wallet := range candidates {
+			remaining := new(big.Int).Sub(individualMax, delegations[wallet.Address])
+			if remaining.Sign() > 0 && (most == nil || remaining.Cmp(most) > 0) {
+				selected, most = wallet, remaining
+			}
+		}
+		if most == nil {
+			return selected, "", fmt.Errorf("no eligible representative for fee recipient %s has remaining individual native delegation capacity", feeRecipient.String())
+		}
+		reason = "eligible wallet with the most remaining individual native delegation capacity"
+	case config.RepresentativeStrategyBalance:
+		var highest *big.Int
+		for _, wallet := range candidates {
+			balance, err := k2.eth1.WalletBalance(wallet.Address)
+			if err != nil {
+				return selected, "", fmt.Errorf("failed to get the balance of representative %s: %w", wallet.Address.String(), err)
+			}
+			if highest == nil || balance.Cmp(highest) > 0 {
+				selected, highest = wallet, balance
+			}
+		}
+		reason = "eligible wallet with the highest ETH balance"
+	case config.RepresentativeStrategyRoundRobin:
+		selected = candidates[k2.representativeTurn%uint64(len(candidates))]
+		if record {
+			k2.representativeTurn++
+		}
+		reason = "next eligible wallet in turn"
+	}
+
+	if record {
+		k2.representativeAssignments[strings.ToLower(feeRecipient.String())] = selected.Address
+		if err := k2.writeRepresentativeAssignments(); err != nil {
+			// the selection still applies, and is persisted with the next assignment
+			k2.log.WithError(err).Error("Failed to persist the representative assignments")
+		}
+		k2.log.WithFields(logrus.Fields{
+			"feeRecipient":   feeRecipient.String(),
+			"representative": selected.Address.String(),
+			"strategy":       strategy,
+		}).Info("Representative assigned to fee recipient")
+	}
+
+	return selected, reason, nil
+}
@@ -0,0 +1,135 @@
+package k2
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/config"
+)
+
+func TestSelectRepresentative(t *testing.T) {
+
+	feeRecipient := ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	otherFeeRecipient := ethcommon.HexToAddress("0x4444444444444444444444444444444444444444")
+	wallets := []k2common.ValidatorWallet{
+		{Address: ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")},
+		{Address: ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")},
+	}
+
+	newService := func(t *testing.T, strategy string) *K2Service {
+		k2 := NewK2Service()
+		k2.cfg.RepresentativeStrategy = strategy
+		k2.cfg.RepresentativeAssignmentFile = filepath.Join(t.TempDir(), "assignments.json")
+		return k2
+	}
+
+	t.Run("no candidates", func(t *testing.T) {
+		k2 := newService(t, config.RepresentativeStrategyRoundRobin)
+		if _, _, err := k2.selectRepresentative(feeRecipient, nil, true); err == nil {
+			t.Fatalf("selectRepresentative() selected a representative without candidates")
+		}
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		k2 := newService(t, config.RepresentativeStrategyPriority)
+		for i := 0; i < 2; i++ {
+			selected, _, err := k2.selectRepresentative(feeRecipient, wallets, true)
+			if err != nil {
+				t.Fatalf("selectRepresentative() error = %v", err)
+			}
+			if selected.Address != wallets[0].Address {
+				t.Errorf("selected %s, want %s", selected.Address, wallets[0].Address)
+			}
+		}
+		if _, err := os.Stat(k2.cfg.RepresentativeAssignmentFile); err == nil {
+			t.Errorf("priority strategy persisted an assignment")
+		}
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		k2 := newService(t, config.RepresentativeStrategyRoundRobin)
+
+		// a selection that is not recorded does not take a turn
+		preview, _, err := k2.selectRepresentative(feeRecipient, wallets, false)
+		if err != nil {
+			t.Fatalf("selectRepresentative() error = %v", err)
+		}
+		first, _, _ := k2.selectRepresentative(feeRecipient, wallets, true)
+		second, _, _ := k2.selectRepresentative(otherFeeRecipient, wallets, true)
+		if preview.Address != wallets[0].Address || first.Address != wallets[0].Address || second.Address != wallets[1].Address {
+			t.Fatalf("selected %s, %s, %s, want %s, %s, %s", preview.Address, first.Address, second.Address, wallets[0].Address, wallets[0].Address, wallets[1].Address)
+		}
+
+		// the fee recipient keeps its representative while it remains a candidate
+		again, reason, _ := k2.selectRepresentative(feeRecipient, wallets, true)
+		if again.Address != first.Address || !strings.Contains(reason, "previously assigned") {
+			t.Errorf("selected %s (%s), want the assigned %s", again.Address, reason, first.Address)
+		}
+		moved, _, _ := k2.selectRepresentative(feeRecipient, wallets[1:], true)
+		if moved.Address != wallets[1].Address {
+			t.Errorf("selected %s, want %s once the assigned representative is not a candidate", moved.Address, wallets[1].Address)
+		}
+
+		// the assignments survive a restart
+		restarted := newService(t, config.RepresentativeStrategyRoundRobin)
+		if err := restarted.readRepresentativeAssignments(k2.cfg.RepresentativeAssignmentFile); err != nil {
+			t.Fatalf("readRepresentativeAssignments() error = %v", err)
+		}
+		for recipient, want := range map[ethcommon.Address]ethcommon.Address{feeRecipient: wallets[1].Address, otherFeeRecipient: wallets[1].Address} {
+			if got := restarted.representativeAssignments[strings.ToLower(recipient.String())]; got != want {
+				t.Errorf("assignment of %s = %s, want %s", recipient, got, want)
+			}
+		}
+	})
+}
+
+func TestReadRepresentativeAssignments(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		content *string // file not created if nil
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "file not created yet",
+		},
+		{
+			name:    "assignments",
+			content: stringPtr(`[{"feeRecipientAddress":"0x1111111111111111111111111111111111111111","representativeAddress":"0x2222222222222222222222222222222222222222"}]`),
+			want:    1,
+		},
+		{
+			name:    "assignment without a representative",
+			content: stringPtr(`[{"feeRecipientAddress":"0x1111111111111111111111111111111111111111"}]`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "assignments.json")
+			if tt.content != nil {
+				if err := os.WriteFile(filePath, []byte(*tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			k2 := NewK2Service()
+			err := k2.readRepresentativeAssignments(filePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readRepresentativeAssignments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := len(k2.representativeAssignments); got != tt.want {
+				t.Errorf("%d assignments, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func stringPtr(v string) *string { return &v }
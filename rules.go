@@ -0,0 +1,257 @@
+package k2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+// filterRulePrefix keys rule entries in the exclusion and strict inclusion lists apart from BLS keys and fee recipients
+const filterRulePrefix = "rule:"
+
+func filterRuleKey(name string) string {
+	return filterRulePrefix + strings.ToLower(name)
+}
+
+// listRule is a rule entry of the exclusion or strict inclusion list
+type listRule struct {
+	list   string // audit.ListExclusion or audit.ListInclusion
+	filter k2common.ValidatorFilter
+}
+
+// ruleContext holds the rule entries of the exclusion and strict inclusion lists in the order they are evaluated,
+// with what the rules match the validators of a batch of registrations by
+type ruleContext struct {
+	rules []listRule
+	tags  map[string]map[string]bool // [Validator pubKey] -> Tags from the validator metadata file
+
+	indices        map[string]uint64 // [Validator pubKey] -> Validator index, for validators found on the beacon node
+	web3SignerKeys map[string]bool   // [Validator pubKey] -> Whether the key is loaded in the web3signer, nil if unknown
+	gasLimits      map[string]uint64 // [Validator pubKey] -> Gas limit of the registration message
+}
+
+// ruleSnapshot returns the rule entries of the exclusion and strict inclusion lists, highest priority first, exclusion
+// rules before inclusion rules of the same priority, then by name. Rules are only evaluated for validators without
+// an exclusion or inclusion list entry for their BLS key or fee recipient
+func (k2 *K2Service) ruleSnapshot() ruleContext {
+
+	k2.listLock.RLock()
+	defer k2.listLock.RUnlock()
+
+	ctx := ruleContext{
+		tags: k2.validatorTags,
+	}
+	for _, filter := range k2.exclusionList {
+		if filter.Rule != "" {
+			ctx.rules = append(ctx.rules, listRule{list: audit.ListExclusion, filter: filter})
+		}
+	}
+	for _, filter := range k2.strictInclusionList {
+		if filter.Rule != "" {
+			ctx.rules = append(ctx.rules, listRule{list: audit.ListInclusion, filter: filter})
+		}
+	}
+
+	sort.Slice(ctx.rules, func(i, j int) bool {
+		a, b := ctx.rules[i], ctx.rules[j]
+		if a.filter.Priority != b.filter.Priority {
+			return a.filter.Priority > b.filter.Priority
+		}
+		if a.list != b.list {
+			return a.list == audit.ListExclusion
+		}
+		return strings.ToLower(a.filter.Rule) < strings.ToLower(b.filter.Rule)
+	})
+
+	return ctx
+}
+
+// gatherRuleFacts fetches what the rules match the validators of the registrations by. Facts that cannot be
+// fetched are left out, so that rules depending on them do not match
+func (k2 *K2Service) gatherRuleFacts(ctx *ruleContext, payload []apiv1.SignedValidatorRegistration) {
+
+	ctx.gasLimits = make(map[string]uint64, len(payload))
+	ctx.indices = make(map[string]uint64)
+
+	if len(ctx.rules) == 0 {
+		return
+	}
+
+	var needIndices, needWeb3SignerKeys bool
+	for _, rule := range ctx.rules {
+		needIndices = needIndices || rule.filter.MinValidatorIndex != nil || rule.filter.MaxValidatorIndex != nil
+		needWeb3SignerKeys = needWeb3SignerKeys || rule.filter.Web3SignerKey != nil
+	}
+
+	blsKeys := make([]phase0.BLSPubKey, 0, len(payload))
+	for _, reg := range payload {
+		ctx.gasLimits[strings.ToLower(reg.Message.Pubkey.String())] = reg.Message.GasLimit
+		blsKeys = append(blsKeys, reg.Message.Pubkey)
+	}
+
+	if needIndices {
+		indices, err := k2.beacon.FinalizedValidatorIndices(blsKeys)
+		if err != nil {
+			k2.log.WithError(err).Warn("Failed to get validator indices for the validator index rules, the rules will not match")
+		}
+		for blsKey, index := range indices {
+			ctx.indices[strings.ToLower(blsKey.String())] = index
+		}
+	}
+
+	if needWeb3SignerKeys && k2.cfg.Web3SignerUrl != nil {
+		keys, err := k2.web3Signer.GetPubkeyList()
+		if err != nil {
+			k2.log.WithError(err).Warn("Failed to get the web3signer keys for the web3signer key rules, the rules will not match")
+		} else {
+			ctx.web3SignerKeys = make(map[string]bool, len(keys))
+			for key, loaded := range keys {
+				ctx.web3SignerKeys[strings.ToLower(key)] = loaded
+			}
+		}
+	}
+}
+
+// match returns the first rule matching the validator
+func (ctx *ruleContext) match(validator string) (listRule, bool) {
+	validator = strings.ToLower(validator)
+	for _, rule := range ctx.rules {
+		if ctx.matches(rule.filter, validator) {
+			return rule, true
+		}
+	}
+	return listRule{}, false
+}
+
+// included returns whether a rule of the strict inclusion list matches the validator
+func (ctx *ruleContext) included(validator string) bool {
+	validator = strings.ToLower(validator)
+	for _, rule := range ctx.rules {
+		if rule.list == audit.ListInclusion && ctx.matches(rule.filter, validator) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *ruleContext) matches(rule k2common.ValidatorFilter, validator string) bool {
+
+	if rule.MinValidatorIndex != nil || rule.MaxValidatorIndex != nil {
+		index, ok := ctx.indices[validator]
+		if !ok {
+			return false
+		}
+		if rule.MinValidatorIndex != nil && index < *rule.MinValidatorIndex {
+			return false
+		}
+		if rule.MaxValidatorIndex != nil && index > *rule.MaxValidatorIndex {
+			return false
+		}
+	}
+
+	if rule.Web3SignerKey != nil {
+		if ctx.web3SignerKeys == nil || ctx.web3SignerKeys[validator] != *rule.Web3SignerKey {
+			return false
+		}
+	}
+
+	if rule.MinGasLimit != nil || rule.MaxGasLimit != nil {
+		gasLimit, ok := ctx.gasLimits[validator]
+		if !ok {
+			return false
+		}
+		if rule.MinGasLimit != nil && gasLimit < *rule.MinGasLimit {
+			return false
+		}
+		if rule.MaxGasLimit != nil && gasLimit > *rule.MaxGasLimit {
+			return false
+		}
+	}
+
+	if rule.Tag != "" && !ctx.tags[validator][rule.Tag] {
+		return false
+	}
+
+	return true
+}
+
+// checkFilterRule validates the rule conditions of an exclusion or strict inclusion list entry
+func (k2 *K2Service) checkFilterRule(list string, entry k2common.ValidatorFilter) error {
+
+	if entry.Rule == "" {
+		if entry.HasRuleConditions() || entry.Priority != 0 {
+			return fmt.Errorf("invalid %s list entry [%s, %s], rule conditions and priority can only be set on a named rule", list, entry.PublicKey.String(), entry.FeeRecipient.String())
+		}
+		return nil
+	}
+
+	if entry.PublicKey != (phase0.BLSPubKey{}) || entry.FeeRecipient != (ethcommon.Address{}) {
+		return fmt.Errorf("invalid %s list rule %s, cannot specify a PublicKey or FeeRecipient in a rule", list, entry.Rule)
+	}
+	if !entry.HasRuleConditions() {
+		return fmt.Errorf("invalid %s list rule %s, must specify at least one condition", list, entry.Rule)
+	}
+	if entry.MinValidatorIndex != nil && entry.MaxValidatorIndex != nil && *entry.MinValidatorIndex > *entry.MaxValidatorIndex {
+		return fmt.Errorf("invalid %s list rule %s, minimum validator index is greater than the maximum", list, entry.Rule)
+	}
+	if entry.MinGasLimit != nil && entry.MaxGasLimit != nil && *entry.MinGasLimit > *entry.MaxGasLimit {
+		return fmt.Errorf("invalid %s list rule %s, minimum gas limit is greater than the maximum", list, entry.Rule)
+	}
+	if entry.Web3SignerKey != nil && k2.cfg.Web3SignerUrl == nil {
+		return fmt.Errorf("invalid %s list rule %s, matching by web3signer key requires a web3signer url", list, entry.Rule)
+	}
+	if entry.Tag != "" && k2.cfg.ValidatorMetadataFile == "" {
+		return fmt.Errorf("invalid %s list rule %s, matching by tag requires a validator metadata file", list, entry.Rule)
+	}
+
+	return nil
+}
+
+func (k2 *K2Service) readValidatorMetadata(source string) error {
+	return k2.readListSource("validator metadata", source, k2.loadValidatorMetadata)
+}
+
+// loadValidatorMetadata decodes and applies the validator tags read from the metadata file or url
+func (k2 *K2Service) loadValidatorMetadata(source string, content []byte) error {
+
+	var entries []k2common.ValidatorTag
+	err := k2common.DecodeList(source, content, &entries)
+	if err != nil {
+		return fmt.Errorf("failed to parse validator metadata: %w", err)
+	}
+
+	tags := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if entry.PublicKey == (phase0.BLSPubKey{}) || entry.Tag == "" {
+			return fmt.Errorf("invalid validator metadata entry [%s, %q], must specify both a PublicKey and a tag", entry.PublicKey.String(), entry.Tag)
+		}
+		key := strings.ToLower(entry.PublicKey.String())
+		if tags[key] == nil {
+			tags[key] = make(map[string]bool)
+		}
+		tags[key][entry.Tag] = true
+	}
+
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
+
+	k2.validatorTags = tags
+
+	k2.log.Infof("Validator metadata updated with tags for %d validators", len(tags))
+
+	return nil
+}
+
+func (k2 *K2Service) clearValidatorMetadata() error {
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
+	k2.validatorTags = make(map[string]map[string]bool)
+	return nil
+}
@@ -0,0 +1,218 @@
+package k2
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+func uint64Ptr(v uint64) *uint64 { return &v }
+
+func boolPtr(v bool) *bool { return &v }
+
+func TestRuleContext_Matches(t *testing.T) {
+
+	validator := strings.ToLower(phase0.BLSPubKey{0xa1}.String())
+	unknown := strings.ToLower(phase0.BLSPubKey{0xb2}.String())
+
+	ctx := ruleContext{
+		tags:           map[string]map[string]bool{validator: {"lido": true}},
+		indices:        map[string]uint64{validator: 500},
+		web3SignerKeys: map[string]bool{validator: true},
+		gasLimits:      map[string]uint64{validator: 30000000},
+	}
+
+	tests := []struct {
+		name      string
+		rule      k2common.ValidatorFilter
+		validator string
+		want      bool
+	}{
+		{"index in range", k2common.ValidatorFilter{MinValidatorIndex: uint64Ptr(100), MaxValidatorIndex: uint64Ptr(500)}, validator, true},
+		{"index below range", k2common.ValidatorFilter{MinValidatorIndex: uint64Ptr(501)}, validator, false},
+		{"index above range", k2common.ValidatorFilter{MaxValidatorIndex: uint64Ptr(499)}, validator, false},
+		{"index unknown", k2common.ValidatorFilter{MinValidatorIndex: uint64Ptr(0)}, unknown, false},
+		{"web3signer key loaded", k2common.ValidatorFilter{Web3SignerKey: boolPtr(true)}, validator, true},
+		{"web3signer key not loaded", k2common.ValidatorFilter{Web3SignerKey: boolPtr(false)}, unknown, true},
+		{"web3signer key loaded but not wanted", k2common.ValidatorFilter{Web3SignerKey: boolPtr(false)}, validator, false},
+		{"gas limit in range", k2common.ValidatorFilter{MinGasLimit: uint64Ptr(30000000), MaxGasLimit: uint64Ptr(36000000)}, validator, true},
+		{"gas limit out of range", k2common.ValidatorFilter{MaxGasLimit: uint64Ptr(29999999)}, validator, false},
+		{"gas limit unknown", k2common.ValidatorFilter{MinGasLimit: uint64Ptr(0)}, unknown, false},
+		{"tagged", k2common.ValidatorFilter{Tag: "lido"}, validator, true},
+		{"not tagged", k2common.ValidatorFilter{Tag: "lido"}, unknown, false},
+		{"all conditions", k2common.ValidatorFilter{MinValidatorIndex: uint64Ptr(500), Web3SignerKey: boolPtr(true), MinGasLimit: uint64Ptr(30000000), Tag: "lido"}, validator, true},
+		{"one condition failing", k2common.ValidatorFilter{MinValidatorIndex: uint64Ptr(500), Tag: "rocketpool"}, validator, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ctx.matches(tt.rule, tt.validator); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// web3signer key rules never match when the web3signer keys could not be fetched
+	if (&ruleContext{}).matches(k2common.ValidatorFilter{Web3SignerKey: boolPtr(false)}, validator) {
+		t.Errorf("web3signer key rule matched without the web3signer keys")
+	}
+}
+
+func TestRuleSnapshot_Order(t *testing.T) {
+
+	validator := strings.ToLower(phase0.BLSPubKey{0xa1}.String())
+
+	k2 := NewK2Service()
+	k2.exclusionList = map[string]k2common.ValidatorFilter{
+		filterRuleKey("low"):    {Rule: "low", Priority: 1, MinGasLimit: uint64Ptr(0)},
+		filterRuleKey("tagged"): {Rule: "tagged", Priority: 5, Tag: "lido"},
+		validator:               {PublicKey: phase0.BLSPubKey{0xa1}},
+	}
+	k2.strictInclusionList = map[string]k2common.ValidatorFilter{
+		filterRuleKey("high"):  {Rule: "high", Priority: 10, Tag: "rocketpool"},
+		filterRuleKey("equal"): {Rule: "equal", Priority: 5, MinGasLimit: uint64Ptr(0)},
+		filterRuleKey("b"):     {Rule: "b", Priority: 1, MinGasLimit: uint64Ptr(0)},
+	}
+
+	ctx := k2.ruleSnapshot()
+
+	var got []string
+	for _, rule := range ctx.rules {
+		got = append(got, rule.list+":"+rule.filter.Rule)
+	}
+	want := []string{
+		audit.ListInclusion + ":high",
+		audit.ListExclusion + ":tagged",
+		audit.ListInclusion + ":equal",
+		audit.ListExclusion + ":low",
+		audit.ListInclusion + ":b",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("rules = %v, want %v", got, want)
+	}
+
+	// the first matching rule applies, and any matching inclusion rule includes the validator
+	ctx.gasLimits = map[string]uint64{validator: 30000000}
+	rule, ok := ctx.match(validator)
+	if !ok || rule.filter.Rule != "equal" {
+		t.Errorf("match() = %s %v, want the equal rule", rule.filter.Rule, ok)
+	}
+	if !ctx.included(validator) {
+		t.Errorf("included() = false, want true")
+	}
+	ctx.gasLimits = nil
+	if _, ok := ctx.match(validator); ok {
+		t.Errorf("match() matched a validator without facts")
+	}
+}
+
+func TestCheckFilterRule(t *testing.T) {
+
+	web3SignerUrl, _ := url.Parse("http://localhost:9000")
+
+	tests := []struct {
+		name          string
+		entry         k2common.ValidatorFilter
+		web3Signer    bool
+		metadataFile  bool
+		wantErrSubstr string
+	}{
+		{
+			name:  "key entry",
+			entry: k2common.ValidatorFilter{PublicKey: phase0.BLSPubKey{0xa1}},
+		},
+		{
+			name:          "key entry with conditions",
+			entry:         k2common.ValidatorFilter{PublicKey: phase0.BLSPubKey{0xa1}, Tag: "lido"},
+			wantErrSubstr: "can only be set on a named rule",
+		},
+		{
+			name:  "rule",
+			entry: k2common.ValidatorFilter{Rule: "large", MinGasLimit: uint64Ptr(36000000)},
+		},
+		{
+			name:          "rule with a fee recipient",
+			entry:         k2common.ValidatorFilter{Rule: "large", FeeRecipient: ethcommon.HexToAddress("0x1111111111111111111111111111111111111111"), MinGasLimit: uint64Ptr(0)},
+			wantErrSubstr: "cannot specify a PublicKey or FeeRecipient",
+		},
+		{
+			name:          "rule without conditions",
+			entry:         k2common.ValidatorFilter{Rule: "everything"},
+			wantErrSubstr: "must specify at least one condition",
+		},
+		{
+			name:          "inverted index range",
+			entry:         k2common.ValidatorFilter{Rule: "range", MinValidatorIndex: uint64Ptr(10), MaxValidatorIndex: uint64Ptr(5)},
+			wantErrSubstr: "minimum validator index is greater than the maximum",
+		},
+		{
+			name:          "inverted gas limit range",
+			entry:         k2common.ValidatorFilter{Rule: "range", MinGasLimit: uint64Ptr(10), MaxGasLimit: uint64Ptr(5)},
+			wantErrSubstr: "minimum gas limit is greater than the maximum",
+		},
+		{
+			name:          "web3signer key without web3signer",
+			entry:         k2common.ValidatorFilter{Rule: "signer", Web3SignerKey: boolPtr(true)},
+			wantErrSubstr: "requires a web3signer url",
+		},
+		{
+			name:       "web3signer key",
+			entry:      k2common.ValidatorFilter{Rule: "signer", Web3SignerKey: boolPtr(true)},
+			web3Signer: true,
+		},
+		{
+			name:          "tag without metadata file",
+			entry:         k2common.ValidatorFilter{Rule: "tagged", Tag: "lido"},
+			wantErrSubstr: "requires a validator metadata file",
+		},
+		{
+			name:         "tag",
+			entry:        k2common.ValidatorFilter{Rule: "tagged", Tag: "lido"},
+			metadataFile: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+			if tt.web3Signer {
+				k2.cfg.Web3SignerUrl = web3SignerUrl
+			}
+			if tt.metadataFile {
+				k2.cfg.ValidatorMetadataFile = "metadata.json"
+			}
+
+			err := k2.checkFilterRule(listExclusion, tt.entry)
+			if tt.wantErrSubstr == "" && err != nil {
+				t.Fatalf("checkFilterRule() error = %v", err)
+			} else if tt.wantErrSubstr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErrSubstr)) {
+				t.Fatalf("checkFilterRule() error = %v, want %q", err, tt.wantErrSubstr)
+			}
+		})
+	}
+}
+
+func TestLoadValidatorMetadata(t *testing.T) {
+
+	validator := phase0.BLSPubKey{0xa1}
+
+	k2 := NewK2Service()
+	content := `[{"publicKey":"` + validator.String() + `","tag":"lido"},{"publicKey":"` + validator.String() + `","tag":"dvt"}]`
+	if err := k2.loadValidatorMetadata("metadata.json", []byte(content)); err != nil {
+		t.Fatalf("loadValidatorMetadata() error = %v", err)
+	}
+	tags := k2.validatorTags[strings.ToLower(validator.String())]
+	if len(tags) != 2 || !tags["lido"] || !tags["dvt"] {
+		t.Errorf("tags = %v, want lido and dvt", tags)
+	}
+
+	if err := k2.loadValidatorMetadata("metadata.json", []byte(`[{"publicKey":"`+validator.String()+`"}]`)); err == nil {
+		t.Errorf("loadValidatorMetadata() accepted an entry without a tag")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
 )
 
 func (k2 *K2Service) startServer() error {
@@ -44,6 +46,24 @@ func (k2 *K2Service) getRouter() http.Handler {
 	r.HandleFunc(pathRegister, k2.handleRegister).Methods(http.MethodPost)
 	r.HandleFunc(pathUpdateK2Payout, k2.handleUpdateK2Payout).Methods(http.MethodPost)
 	r.HandleFunc(pathGetDelegatedValidators, k2.handleGetValidators).Methods(http.MethodGet)
+	r.Handle(pathStream, k2.stream).Methods(http.MethodGet)
+	r.HandleFunc(pathHealth, k2.handleHealth).Methods(http.MethodGet)
+	r.HandleFunc(pathLiveness, k2.handleLiveness).Methods(http.MethodGet)
+	r.HandleFunc(pathReadiness, k2.handleReadiness).Methods(http.MethodGet)
+	r.HandleFunc(pathAudit, k2.handleAudit).Methods(http.MethodGet)
+	r.HandleFunc(pathExplain, k2.handleExplain).Methods(http.MethodGet)
+	r.HandleFunc(pathList, k2.handleGetList).Methods(http.MethodGet)
+	r.HandleFunc(pathList, k2.handleChangeList).Methods(http.MethodPost)
+	r.HandleFunc(pathListEntry, k2.handleChangeList).Methods(http.MethodPut, http.MethodDelete)
+	r.HandleFunc(pathValidateLists, k2.handleValidateLists).Methods(http.MethodGet)
+	r.HandleFunc(pathDeferredDelegations, k2.handleDeferredDelegations).Methods(http.MethodGet)
+	r.HandleFunc(pathClaimRecipients, k2.handleGetClaimRecipients).Methods(http.MethodGet)
+	r.HandleFunc(pathClaimRecipients, k2.handleSetClaimRecipient).Methods(http.MethodPost)
+	r.HandleFunc(pathClaimRecipient, k2.handleClearClaimRecipient).Methods(http.MethodDelete)
+	r.HandleFunc(pathRewardsWallets, k2.handleGetRewardsWallets).Methods(http.MethodGet)
+	r.HandleFunc(pathClaimKETH, k2.handleClaimKETH).Methods(http.MethodPost)
+	r.HandleFunc(pathNodeOperators, k2.handleGetNodeOperators).Methods(http.MethodGet)
+	r.Handle(pathMetrics, metrics.Handler()).Methods(http.MethodGet)
 
 	r.Use(mux.CORSMethodMiddleware(r))
 	loggedRouter := LoggingMiddleware(k2.log, r)
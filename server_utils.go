@@ -35,10 +35,14 @@ func (k2 *K2Service) respondError(w http.ResponseWriter, code int, message strin
 }
 
 func (k2 *K2Service) respondOK(w http.ResponseWriter, response any) {
+	k2.respond(w, http.StatusOK, response)
+}
+
+func (k2 *K2Service) respond(w http.ResponseWriter, code int, response any) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(code)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		k2.log.WithField("response", response).WithError(err).Error("Couldn't write OK response")
+		k2.log.WithField("response", response).WithError(err).Errorf("Couldn't write %d response", code)
 		http.Error(w, "", http.StatusInternalServerError)
 	}
 }
@@ -74,6 +78,7 @@ func LoggingMiddleware(logger *logrus.Entry, next http.Handler) http.Handler {
 			"duration": fmt.Sprintf("%f", time.Since(start).Seconds()),
 		}).Info(fmt.Sprintf("http: %s %s", r.Method, r.URL.EscapedPath()))
 		fmt.Println("*******************************")
-		fmt.Println("*******************************\n")
+		fmt.Println("*******************************")
+		fmt.Println()
 	})
 }
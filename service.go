@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
 	balanceverifier "github.com/restaking-cloud/native-delegation-for-plus/balanceverifier"
 	"github.com/restaking-cloud/native-delegation-for-plus/config"
 
@@ -21,7 +22,9 @@ import (
 	"github.com/restaking-cloud/native-delegation-for-plus/beacon"
 	beaconConfig "github.com/restaking-cloud/native-delegation-for-plus/beacon/config"
 	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
 	"github.com/restaking-cloud/native-delegation-for-plus/signatureswapper"
+	"github.com/restaking-cloud/native-delegation-for-plus/stream"
 	"github.com/restaking-cloud/native-delegation-for-plus/subgraph"
 	"github.com/restaking-cloud/native-delegation-for-plus/web3signer"
 
@@ -40,6 +43,9 @@ type K2Service struct {
 	eth1             *ethservice.EthService
 	beacon           *beacon.BeaconService
 	balanceverifier  *balanceverifier.BalanceVerifierService
+	notifierService  *notifier.NotifierService
+	stream           *stream.StreamService
+	auditLog         *audit.AuditService
 	lock             sync.Mutex
 
 	server *http.Server
@@ -48,10 +54,44 @@ type K2Service struct {
 	strictInclusionList   map[string]k2common.ValidatorFilter // [Validator pubKey / Fee recipient address] -> Validator filter
 	representativeMapping map[string]ethcommon.Address        // [Fee recipient address / Validator pubKey] -> Representative address
 	// *NOTE* Keep/Access the keys of the above maps in lower case to avoid case sensitivity issues [mixed checksums, etc.]
-
-	// Track the last most recent timestamp that was processed
+	validatorTags map[string]map[string]bool // [Validator pubKey] -> Tags attached to the key in the validator metadata file
+	// guards the lists and validator tags above, which are replaced rather than modified so a snapshot stays consistent
+	listLock    sync.RWMutex
+	remoteLists map[string]remoteList // [List url] -> Last applied list document fetched from the url
+	// guards the remote list documents above, apart from the list lock so a slow fetch never holds up the lists
+	remoteListLock sync.Mutex
+	listClient     *http.Client
+
+	delegationPolicies map[string]delegationPolicy            // [Representative address] -> Native delegation quotas and windows
+	delegationUsage    map[ethcommon.Address]*delegationUsage // [Representative address] -> New native delegations counted against the quotas
+
+	representativeAssignments map[string]ethcommon.Address // [Fee recipient address] -> Representative selected by the representative strategy
+	representativeTurn        uint64                       // Position of the next wallet selected by the round-robin strategy
+
+	hdWallet            *k2common.HDWallet           // derives new representative wallets from the mnemonic, if configured
+	nextDerivationIndex uint32                       // Lowest index not yet used by a wallet derived from the mnemonic
+	derivedWallets      map[ethcommon.Address]uint32 // [Representative address] -> Derivation index of the wallets derived from the mnemonic, guarded by statusLock
+	// guards the configured wallets, which grow as wallets are derived from the mnemonic while the module runs
+	walletLock sync.RWMutex
+
+	// Track the last most recent timestamp that was processed, guarded by statusLock so the health check never waits on processing
 	lastRegistrationMessageTimestamp time.Time
 
+	health     *k2common.HealthStatus // Last result of the health monitor, reported by Status and the health endpoints
+	healthLock sync.RWMutex           // guards health so it can be read without waiting on a health check
+
+	recentRegistrations    map[string]apiv1.SignedValidatorRegistration // [Validator pubKey] -> Last registration message received for the validator
+	pendingJobs            map[string]int                               // [Job kind] -> Number of jobs in progress
+	walletRunway           map[ethcommon.Address]map[string]uint64      // [Representative address] -> [Operation] -> Batches the wallet can fund
+	capacityExhausted      map[string]bool                              // [Capacity scope + Representative address] -> Whether the capacity was last seen exhausted
+	deferredDelegations    map[string]k2common.DeferredDelegation       // [Validator pubKey] -> Native delegation queued by the delegation policies
+	claimedKETH            map[ethcommon.Address]*big.Int               // [Representative address] -> KETH claimed with claimKETH since the module started
+	kickedValidators       map[string]k2common.KickedValidator          // [Validator pubKey] -> Validator kicked from the K2 contract
+	bannedRepresentatives  map[ethcommon.Address]bool                   // [Representative address] -> Whether the K2 contract banned the representative
+	nodeOperatorCheckBlock uint64                                       // Last block checked for validators kicked from the K2 contract
+	notifiers              []notifier.Notifier                          // informed of module events such as registrations and failed transactions
+	statusLock             sync.RWMutex                                 // guards the status fields above without waiting on in-flight processing
+
 	exit chan struct{}
 
 	configured bool
@@ -60,18 +100,36 @@ type K2Service struct {
 
 func NewK2Service() *K2Service {
 	return &K2Service{
-		log:                   logrus.NewEntry(logrus.New()).WithField("moduleExecution", config.ModuleName),
-		signatureSwapper:      signatureswapper.NewSignatureSwapperService(),
-		subgraph:              subgraph.NewSubgraphService(),
-		web3Signer:            web3signer.NewWeb3SignerService(),
-		eth1:                  ethservice.NewEthService(),
-		beacon:                beacon.NewBeaconService(),
-		balanceverifier:       balanceverifier.NewBalanceVerifierService(),
-		exclusionList:         make(map[string]k2common.ValidatorFilter),
-		strictInclusionList:   make(map[string]k2common.ValidatorFilter),
-		representativeMapping: make(map[string]ethcommon.Address),
-		exit:                  make(chan struct{}),
-		cfg:                   config.K2ConfigDefaults,
+		log:                       logrus.NewEntry(logrus.New()).WithField("moduleExecution", config.ModuleName),
+		signatureSwapper:          signatureswapper.NewSignatureSwapperService(),
+		subgraph:                  subgraph.NewSubgraphService(),
+		web3Signer:                web3signer.NewWeb3SignerService(),
+		eth1:                      ethservice.NewEthService(),
+		beacon:                    beacon.NewBeaconService(),
+		balanceverifier:           balanceverifier.NewBalanceVerifierService(),
+		exclusionList:             make(map[string]k2common.ValidatorFilter),
+		strictInclusionList:       make(map[string]k2common.ValidatorFilter),
+		representativeMapping:     make(map[string]ethcommon.Address),
+		validatorTags:             make(map[string]map[string]bool),
+		remoteLists:               make(map[string]remoteList),
+		listClient:                &http.Client{Timeout: remoteListTimeout},
+		delegationPolicies:        make(map[string]delegationPolicy),
+		derivedWallets:            make(map[ethcommon.Address]uint32),
+		delegationUsage:           make(map[ethcommon.Address]*delegationUsage),
+		representativeAssignments: make(map[string]ethcommon.Address),
+		recentRegistrations:       make(map[string]apiv1.SignedValidatorRegistration),
+		pendingJobs:               make(map[string]int),
+		walletRunway:              make(map[ethcommon.Address]map[string]uint64),
+		capacityExhausted:         make(map[string]bool),
+		deferredDelegations:       make(map[string]k2common.DeferredDelegation),
+		claimedKETH:               make(map[ethcommon.Address]*big.Int),
+		kickedValidators:          make(map[string]k2common.KickedValidator),
+		bannedRepresentatives:     make(map[ethcommon.Address]bool),
+		notifierService:           notifier.NewNotifierService(),
+		stream:                    stream.NewStreamService(),
+		auditLog:                  audit.NewAuditService(),
+		exit:                      make(chan struct{}),
+		cfg:                       config.K2ConfigDefaults,
 	}
 }
 
@@ -111,6 +169,20 @@ func (k2 *K2Service) Start() error {
 		go k2.watchFile("representative mapping", k2.cfg.RepresentativeMappingFile, k2.readRepresentativeMapping, k2.clearRepresentativeMapping)
 	}
 
+	// start monitoring the validator metadata file
+	if k2.cfg.ValidatorMetadataFile != "" {
+		go k2.watchFile("validator metadata", k2.cfg.ValidatorMetadataFile, k2.readValidatorMetadata, k2.clearValidatorMetadata)
+	}
+
+	// start monitoring the delegation policy file and retrying the delegations it deferred
+	if k2.cfg.DelegationPolicyFile != "" {
+		if err := k2.restoreDelegationUsage(time.Now()); err != nil {
+			k2.log.WithError(err).Warn("Failed to restore the delegation quota usage from the audit log, quotas are counted from now")
+		}
+		go k2.watchFile("delegation policy", k2.cfg.DelegationPolicyFile, k2.readDelegationPolicy, k2.clearDelegationPolicy)
+		go k2.processDeferredDelegations()
+	}
+
 	registryEnabled := k2.cfg.ProposerRegistryContractAddress != ethcommon.Address{}
 	k2Enabled := (k2.cfg.K2LendingContractAddress != ethcommon.Address{}) && (k2.cfg.K2NodeOperatorContractAddress != ethcommon.Address{})
 
@@ -129,11 +201,26 @@ func (k2 *K2Service) Start() error {
 	// start monitoring
 	go k2.monitor()
 
+	// start monitoring the representative wallet balances
+	go k2.monitorWalletBalances()
+
+	// start monitoring the health of the dependencies for the health endpoints
+	go k2.monitorHealth()
+
+	// start monitoring the beacon and execution nodes to fail over between them
+	go k2.monitorNodes()
+
+	// start monitoring the representatives and their validators for bans and kicks
+	if k2Enabled {
+		go k2.monitorNodeOperators()
+	}
+
 	var addresses string
 	var addressesField string = "representativeAddress"
-	for i, wallet := range k2.cfg.ValidatorWallets {
+	wallets := k2.validatorWallets()
+	for i, wallet := range wallets {
 		delimiter := ","
-		if i == len(k2.cfg.ValidatorWallets)-1 {
+		if i == len(wallets)-1 {
 			delimiter = ""
 			if i > 0 {
 				addressesField = "representativeAddresses"
@@ -165,6 +252,8 @@ func (k2 *K2Service) monitor() error {
 	// need to check node and mevPlus are configured correctly for the builder api
 
 	var lastWarnedTimestamp time.Time
+	// Only notify once for each period without registration events
+	var notifiedNoRegistrations bool
 
 	for {
 		select {
@@ -172,11 +261,14 @@ func (k2 *K2Service) monitor() error {
 			cancel()
 			return nil
 		case <-ctxWithCancel.Done():
+			cancel()
 			return nil
 		case <-HeadChan:
 			currentTime := time.Now()
-			k2.lock.Lock()
-			if k2.lastRegistrationMessageTimestamp.IsZero() {
+			k2.statusLock.RLock()
+			lastRegistrationMessageTimestamp := k2.lastRegistrationMessageTimestamp
+			k2.statusLock.RUnlock()
+			if lastRegistrationMessageTimestamp.IsZero() {
 				// no registration events received yet
 				if lastWarnedTimestamp.IsZero() {
 					lastWarnedTimestamp = currentTime
@@ -184,16 +276,27 @@ func (k2 *K2Service) monitor() error {
 					k2.log.Warnf("No registration events received yet from your node for more than 2 epochs since mevPlus started")
 					k2.log.Debug("Please check your node and mevPlus are configured correctly for the builder api")
 					lastWarnedTimestamp = currentTime
+					if !notifiedNoRegistrations {
+						k2.notify(notifier.EventNoRegistrations, nil, nil)
+						notifiedNoRegistrations = true
+					}
 				}
-			} else if currentTime.Sub(k2.lastRegistrationMessageTimestamp) > (2 * time.Duration(12*32) * time.Second) {
+			} else if currentTime.Sub(lastRegistrationMessageTimestamp) > (2 * time.Duration(12*32) * time.Second) {
 				// Send warning message every 2 mins if there is a warning to be sent
 				if currentTime.Sub(lastWarnedTimestamp) > (2 * time.Minute) {
-					k2.log.Warnf("No registration events received for more than 2 epochs from your node, last processed timestamp: %v", k2.lastRegistrationMessageTimestamp)
+					k2.log.Warnf("No registration events received for more than 2 epochs from your node, last processed timestamp: %v", lastRegistrationMessageTimestamp)
 					k2.log.Debug("Please check your node and mevPlus are configured correctly for the builder api")
 					lastWarnedTimestamp = currentTime
+					if !notifiedNoRegistrations {
+						k2.notify(notifier.EventNoRegistrations, nil, map[string]any{
+							"lastRegistrationMessageTimestamp": lastRegistrationMessageTimestamp,
+						})
+						notifiedNoRegistrations = true
+					}
 				}
+			} else {
+				notifiedNoRegistrations = false
 			}
-			k2.lock.Unlock()
 		}
 	}
 
@@ -201,11 +304,20 @@ func (k2 *K2Service) monitor() error {
 
 func (k2 *K2Service) Stop() error {
 
-	// stop monitoring files
-	if k2.cfg.ExclusionListFile != "" || k2.cfg.RepresentativeMappingFile != "" || k2.cfg.StrictInclusionListFile != "" {
+	// stop monitoring files, registrations and wallet balances
+	if k2.configured {
 		close(k2.exit)
 	}
 
+	// deliver any queued notifications and disconnect stream subscribers
+	k2.notifierService.Stop()
+	k2.stream.Close()
+
+	// close the audit log
+	if err := k2.auditLog.Close(); err != nil {
+		k2.log.WithError(err).Error("Failed to close audit log")
+	}
+
 	// stop the server
 	err := k2.stopServer()
 	if err != nil {
@@ -240,8 +352,9 @@ func (k2 *K2Service) Configure(moduleFlags common.ModuleFlags) (err error) {
 
 	// connect to the beacon node and get the chain id configured
 	err = k2.beacon.Configure(beaconConfig.BeaconConfig{
-		BeaconNodeUrl: k2.cfg.BeaconNodeUrl,
-	})
+		BeaconNodeUrl:          k2.cfg.BeaconNodeUrls[0],
+		FallbackBeaconNodeUrls: k2.cfg.BeaconNodeUrls[1:],
+	}, k2.log)
 	if err != nil {
 		return err
 	}
@@ -270,7 +383,7 @@ func (k2 *K2Service) Configure(moduleFlags common.ModuleFlags) (err error) {
 			}
 
 			// Signature swapper and balance verifier are required for K2 operations
-			// but no need to check if the user provided SignatureSwapperUrl and BalanceVerificationUrl here
+			// but no need to check if the user provided SignatureSwapperUrls and BalanceVerificationUrls here
 			// as the individual services will check for the required configuration and throw an error if not provided
 
 			// No need to further check if the user provided ProposerRegistryContractAddress in addition as this can be obtained from the k2 contracts
@@ -298,16 +411,16 @@ func (k2 *K2Service) Configure(moduleFlags common.ModuleFlags) (err error) {
 			k2.log.Debugf("User provided ProposerRegistryContractAddress: %s", k2.cfg.ProposerRegistryContractAddress.String())
 		}
 
-		if k2.cfg.SignatureSwapperUrl == nil {
-			k2.cfg.SignatureSwapperUrl = knownConfig.SignatureSwapperUrl
+		if len(k2.cfg.SignatureSwapperUrls) == 0 {
+			k2.cfg.SignatureSwapperUrls = knownConfig.SignatureSwapperUrls
 		} else {
-			k2.log.Debugf("User provided SignatureSwapperUrl: %s", k2.cfg.SignatureSwapperUrl.String())
+			k2.log.Debugf("User provided SignatureSwapperUrls: %v", k2.cfg.SignatureSwapperUrls)
 		}
 
-		if k2.cfg.BalanceVerificationUrl == nil {
-			k2.cfg.BalanceVerificationUrl = knownConfig.BalanceVerificationUrl
+		if len(k2.cfg.BalanceVerificationUrls) == 0 {
+			k2.cfg.BalanceVerificationUrls = knownConfig.BalanceVerificationUrls
 		} else {
-			k2.log.Debugf("User provided BalanceVerificationUrl: %s", k2.cfg.BalanceVerificationUrl.String())
+			k2.log.Debugf("User provided BalanceVerificationUrls: %v", k2.cfg.BalanceVerificationUrls)
 		}
 
 		if k2.cfg.SubgraphUrl == nil {
@@ -325,13 +438,19 @@ func (k2 *K2Service) Configure(moduleFlags common.ModuleFlags) (err error) {
 		k2.cfg.K2NodeOperatorContractAddress = ethcommon.Address{}
 	}
 
+	// the capacity strategy reads the native delegation capacity of the representatives from the K2 contracts
+	if k2.cfg.RepresentativeStrategy == config.RepresentativeStrategyCapacity && k2.cfg.K2NodeOperatorContractAddress == (ethcommon.Address{}) {
+		return fmt.Errorf("-%s: the %s strategy requires the K2 contracts and cannot be used in registration only mode", config.RepresentativeStrategyFlag.Name, config.RepresentativeStrategyCapacity)
+	}
+
 	// connect to the execution node and get the chain id, and contracts configured
 	err = k2.eth1.Configure(ethConfig.EthServiceConfig{
-		ExecutionNodeUrl:                k2.cfg.ExecutionNodeUrl,
+		ExecutionNodeUrl:                k2.cfg.ExecutionNodeUrls[0],
+		FallbackExecutionNodeUrls:       k2.cfg.ExecutionNodeUrls[1:],
 		K2LendingContractAddress:        k2.cfg.K2LendingContractAddress,
 		K2NodeOperatorContractAddress:   k2.cfg.K2NodeOperatorContractAddress,
 		ProposerRegistryContractAddress: k2.cfg.ProposerRegistryContractAddress,
-		ValidatorWallets:                k2.cfg.ValidatorWallets,
+		ValidatorWallets:                k2.validatorWallets(),
 	}, k2.log)
 	if err != nil {
 		return err
@@ -351,7 +470,7 @@ func (k2 *K2Service) Configure(moduleFlags common.ModuleFlags) (err error) {
 			return err
 		}
 	}
-	err = k2.signatureSwapper.Configure(k2.cfg.SignatureSwapperUrl)
+	err = k2.signatureSwapper.Configure(k2.cfg.SignatureSwapperUrls)
 	if err != nil {
 		return err
 	}
@@ -364,7 +483,7 @@ func (k2 *K2Service) Configure(moduleFlags common.ModuleFlags) (err error) {
 
 	// If configured for K2 operations
 	if (k2.cfg.K2LendingContractAddress != ethcommon.Address{}) && (k2.cfg.K2NodeOperatorContractAddress != ethcommon.Address{}) {
-		err = k2.balanceverifier.Configure(k2.cfg.BalanceVerificationUrl)
+		err = k2.balanceverifier.Configure(k2.cfg.BalanceVerificationUrls)
 		if err != nil {
 			return err
 		}
@@ -412,6 +531,21 @@ func (k2 *K2Service) Configure(moduleFlags common.ModuleFlags) (err error) {
 		}
 	}
 
+	// push module events and transaction updates to stream subscribers
+	k2.stream.Configure(k2.log)
+	k2.RegisterNotifier(k2.stream)
+	k2.eth1.OnTransactionUpdate(k2.publishTransactionUpdate)
+
+	err = k2.configureNotifier()
+	if err != nil {
+		return err
+	}
+
+	err = k2.configureAudit()
+	if err != nil {
+		return err
+	}
+
 	if k2.cfg.MaxGasPrice > 0 {
 		// Then the user has set a max gas price, set it on the eth1 service
 		k2.eth1.SetMaxGasPrice(k2.cfg.MaxGasPrice)
@@ -422,29 +556,15 @@ func (k2 *K2Service) Configure(moduleFlags common.ModuleFlags) (err error) {
 
 func (k2 *K2Service) Status() error {
 
-	// check beacon node is up
-	_, err := k2.beacon.Status()
-	if err != nil {
-		return fmt.Errorf("beacon node is down: %v", err)
-	}
-
-	// check execution node is up
-	_, err = k2.eth1.Status()
-	if err != nil {
-		return fmt.Errorf("execution node is down: %v", err)
-	}
-
-	// check signature swapper is up
-	_, err = k2.signatureSwapper.GetInfo()
-	if err != nil {
-		return fmt.Errorf("signature swapper is down: %v", err)
+	if !k2.configured {
+		return fmt.Errorf("module not configured")
 	}
 
-	// check web3 signer is up if configured
-	if k2.cfg.Web3SignerUrl != nil {
-		err = k2.web3Signer.Status()
-		if err != nil {
-			return fmt.Errorf("web3 signer is down: %v", err)
+	// check all required dependencies (beacon node, execution node, signature swapper,
+	// web3 signer and balance verifier where configured) were up at the last health check
+	for _, dependency := range k2.cachedHealth().Dependencies {
+		if dependency.Required && !dependency.Reachable {
+			return fmt.Errorf("%s is down: %v", dependency.Name, dependency.Error)
 		}
 	}
 
@@ -459,6 +579,18 @@ func (k2 *K2Service) RegisterValidator(payload []apiv1.SignedValidatorRegistrati
 		return nil, nil
 	}
 
+	payload, invalid, err := k2.verifyValidatorRegistrations(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(invalid) > 0 {
+		// each invalid registration is also logged and audited with its reason by the verification
+		k2.log.WithFields(logrus.Fields{
+			"invalid": len(invalid),
+			"valid":   len(payload),
+		}).Warn("Excluded registrations with an invalid signature from the node")
+	}
+
 	var recentTimestamp time.Time
 	var proposers []string
 	for _, reg := range payload {
@@ -470,11 +602,24 @@ func (k2 *K2Service) RegisterValidator(payload []apiv1.SignedValidatorRegistrati
 
 	k2.log.Debugf("Registering validators: %v", proposers)
 
-	k2.lock.Lock()
+	k2.statusLock.Lock()
 	if recentTimestamp.After(k2.lastRegistrationMessageTimestamp) {
 		k2.lastRegistrationMessageTimestamp = recentTimestamp
 	}
-	k2.lock.Unlock()
+	k2.statusLock.Unlock()
+
+	k2.recordRecentRegistrations(payload)
 
 	return k2.batchProcessValidatorRegistrations(payload)
 }
+
+// recordRecentRegistrations keeps the last registration message received for each validator, from the node or the
+// register endpoint, which deferred native delegations are retried with and registration decisions are explained from
+func (k2 *K2Service) recordRecentRegistrations(payload []apiv1.SignedValidatorRegistration) {
+	k2.statusLock.Lock()
+	defer k2.statusLock.Unlock()
+
+	for _, reg := range payload {
+		k2.recentRegistrations[strings.ToLower(reg.Message.Pubkey.String())] = reg
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 type SignatureSwapperConfig struct {
-	Url *url.URL
+	Urls []*url.URL
 	ChainID *big.Int
 	Domain uint64
 }
\ No newline at end of file
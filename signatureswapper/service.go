@@ -17,39 +17,83 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	k2Common "github.com/restaking-cloud/native-delegation-for-plus/common"
 
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
 	"github.com/restaking-cloud/native-delegation-for-plus/signatureswapper/config"
 )
 
 type SignatureSwapperService struct {
-	client *http.Client
-	cfg    config.SignatureSwapperConfig
+	client    *http.Client
+	cfg       config.SignatureSwapperConfig
+	endpoints *k2Common.EndpointPool
 }
 
 func NewSignatureSwapperService() *SignatureSwapperService {
 	return &SignatureSwapperService{
-		client: &http.Client{Timeout: 192 * time.Second},
+		client: &http.Client{
+			Timeout:   192 * time.Second,
+			Transport: metrics.NewTransport(metrics.DependencySignatureSwapper),
+		},
 	}
 }
 
-func (s *SignatureSwapperService) Configure(url *url.URL) error {
+// Configure sets the signature swapper endpoints in order of preference. Every reachable endpoint must report the
+// same chain id and domain, endpoints that cannot be reached are checked before they are first used
+func (s *SignatureSwapperService) Configure(urls []*url.URL) error {
 
-	if url == nil {
+	if len(urls) == 0 {
 		return fmt.Errorf("signatureswapperservice: url not set, cannot configure service")
 	}
 
-	s.cfg.Url = url
+	s.cfg.Urls = urls
+	s.cfg.ChainID = nil
 
-	info, err := s.GetInfo()
-	if err != nil {
-		return fmt.Errorf("signatureswapperservice: failed to get info: %w", err)
+	verified := make(map[*url.URL]bool)
+	var lastErr error
+	for _, u := range urls {
+		info, err := s.getInfo(u)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if s.cfg.ChainID == nil {
+			s.cfg.Domain = info.GasLimitProposerRegistryDomain
+			s.cfg.ChainID = big.NewInt(int64(info.ChainID))
+		} else if err := s.checkInfo(info); err != nil {
+			return fmt.Errorf("signatureswapperservice: %s %w", u.Redacted(), err)
+		}
+		verified[u] = true
+	}
+
+	if len(verified) == 0 {
+		return fmt.Errorf("signatureswapperservice: failed to get info: %w", lastErr)
 	}
 
-	s.cfg.Domain = info.GasLimitProposerRegistryDomain
-	s.cfg.ChainID = big.NewInt(int64(info.ChainID))
+	s.endpoints = k2Common.NewEndpointPool(urls, verified, s.verifyEndpoint)
 
 	return nil
 }
 
+// verifyEndpoint checks an endpoint that could not be reached at configuration serves the configured chain and domain
+func (s *SignatureSwapperService) verifyEndpoint(u *url.URL) error {
+	info, err := s.getInfo(u)
+	if err != nil {
+		return err
+	}
+	return s.checkInfo(info)
+}
+
+func (s *SignatureSwapperService) checkInfo(info Info) error {
+	if info.ChainID != s.cfg.ChainID.Uint64() || info.GasLimitProposerRegistryDomain != s.cfg.Domain {
+		return fmt.Errorf("reports chain id %v and domain %v, expected chain id %v and domain %v", info.ChainID, info.GasLimitProposerRegistryDomain, s.cfg.ChainID, s.cfg.Domain)
+	}
+	return nil
+}
+
+// Endpoints returns the circuit state of each configured endpoint
+func (s *SignatureSwapperService) Endpoints() []k2Common.EndpointHealth {
+	return s.endpoints.Health()
+}
+
 func (s *SignatureSwapperService) Domain() uint64 {
 	return s.cfg.Domain
 }
@@ -58,8 +102,17 @@ func (s *SignatureSwapperService) ConnectedChainId() *big.Int {
 	return s.cfg.ChainID
 }
 
-func (s *SignatureSwapperService) GetInfo() (Info, error) {
-	req, err := http.NewRequestWithContext(context.Background(), "GET", s.cfg.Url.String()+InfoPath, nil)
+// GetInfo returns the info of the first available endpoint
+func (s *SignatureSwapperService) GetInfo() (info Info, err error) {
+	err = s.endpoints.Do(func(u *url.URL) error {
+		info, err = s.getInfo(u)
+		return err
+	})
+	return info, err
+}
+
+func (s *SignatureSwapperService) getInfo(u *url.URL) (Info, error) {
+	req, err := http.NewRequestWithContext(context.Background(), "GET", u.String()+InfoPath, nil)
 	if err != nil {
 		return Info{}, err
 	}
@@ -68,9 +121,15 @@ func (s *SignatureSwapperService) GetInfo() (Info, error) {
 	if err != nil {
 		return Info{}, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return Info{}, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, resp)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return Info{}, fmt.Errorf("error reading invalid response (%d) body: %w", resp.StatusCode, err)
+		}
+
+		return Info{}, &k2Common.StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var response Info
@@ -96,7 +155,25 @@ func (s *SignatureSwapperService) GenerateSignature(
 		return k2Common.EcdsaSignature{}, err
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), "POST", s.cfg.Url.String()+GenerateSignaturePath, bytes.NewReader(payloadBytes))
+	var signature k2Common.EcdsaSignature
+	err = s.endpoints.Do(func(u *url.URL) error {
+		generated, err := s.generateSignature(u, registration, payloadBytes)
+		if err != nil {
+			return err
+		}
+		signature = generated
+		return nil
+	})
+	return signature, err
+}
+
+func (s *SignatureSwapperService) generateSignature(
+	u *url.URL,
+	registration apiv1.SignedValidatorRegistration,
+	payloadBytes []byte,
+) (k2Common.EcdsaSignature, error) {
+
+	req, err := http.NewRequestWithContext(context.Background(), "POST", u.String()+GenerateSignaturePath, bytes.NewReader(payloadBytes))
 	if err != nil {
 		return k2Common.EcdsaSignature{}, err
 	}
@@ -105,14 +182,15 @@ func (s *SignatureSwapperService) GenerateSignature(
 	if err != nil {
 		return k2Common.EcdsaSignature{}, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return k2Common.EcdsaSignature{}, fmt.Errorf("error reading invalid response (%d) body: %v", resp.StatusCode, err)
+			return k2Common.EcdsaSignature{}, fmt.Errorf("error reading invalid response (%d) body: %w", resp.StatusCode, err)
 		}
 
-		return k2Common.EcdsaSignature{}, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, string(body))
+		return k2Common.EcdsaSignature{}, &k2Common.StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var response SignatureSwapResponse
@@ -120,6 +198,16 @@ func (s *SignatureSwapperService) GenerateSignature(
 		return k2Common.EcdsaSignature{}, err
 	}
 
+	// reject the response if it signed anything other than the registration that was sent
+	if response.OriginalData == nil || response.OriginalData.Message == nil ||
+		response.OriginalData.Signature != registration.Signature ||
+		response.OriginalData.Message.Pubkey != registration.Message.Pubkey ||
+		response.OriginalData.Message.FeeRecipient != registration.Message.FeeRecipient ||
+		response.OriginalData.Message.GasLimit != registration.Message.GasLimit ||
+		!response.OriginalData.Message.Timestamp.Equal(registration.Message.Timestamp) {
+		return k2Common.EcdsaSignature{}, fmt.Errorf("invalid response: original data differs from the registration sent")
+	}
+
 	return response.EcdsaSignature, nil
 }
 
@@ -148,7 +236,27 @@ func (s *SignatureSwapperService) BatchGenerateSignature(
 		return res, err
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), "POST", s.cfg.Url.String()+BatchGenerateSignaturePath, bytes.NewReader(payloadBytes))
+	err = s.endpoints.Do(func(u *url.URL) error {
+		signatures, err := s.batchGenerateSignature(u, registration, representativeAddress, payloadBytes)
+		if err != nil {
+			return err
+		}
+		res = signatures
+		return nil
+	})
+	return res, err
+}
+
+func (s *SignatureSwapperService) batchGenerateSignature(
+	u *url.URL,
+	registration []apiv1.SignedValidatorRegistration,
+	representativeAddress common.Address,
+	payloadBytes []byte,
+) (map[phase0.BLSPubKey]k2Common.EcdsaSignature, error) {
+
+	res := make(map[phase0.BLSPubKey]k2Common.EcdsaSignature)
+
+	req, err := http.NewRequestWithContext(context.Background(), "POST", u.String()+BatchGenerateSignaturePath, bytes.NewReader(payloadBytes))
 	if err != nil {
 		return res, err
 	}
@@ -157,14 +265,15 @@ func (s *SignatureSwapperService) BatchGenerateSignature(
 	if err != nil {
 		return res, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return res, fmt.Errorf("error reading invalid response (%d) body: %v", resp.StatusCode, err)
+			return res, fmt.Errorf("error reading invalid response (%d) body: %w", resp.StatusCode, err)
 		}
 
-		return res, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, string(body))
+		return res, &k2Common.StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var response BatchSignatureSwapResponse
@@ -177,7 +286,26 @@ func (s *SignatureSwapperService) BatchGenerateSignature(
 		return res, fmt.Errorf("invalid response: length of original data does not match the length of signatures")
 	}
 
+	// reject the response if it signed anything other than the registrations that were sent
+	sent := make(map[phase0.BLSPubKey]apiv1.SignedValidatorRegistration)
+	for _, reg := range registration {
+		sent[reg.Message.Pubkey] = reg
+	}
 	for i, reg := range response.OriginalData {
+		if reg.Message == nil {
+			return res, fmt.Errorf("invalid response: original data %d has no registration message", i)
+		}
+		sentReg, ok := sent[reg.Message.Pubkey]
+		if !ok {
+			return res, fmt.Errorf("invalid response: original data contains registration for %s which was not sent", reg.Message.Pubkey.String())
+		}
+		if reg.RepresentativeAddress != representativeAddress ||
+			reg.Signature != sentReg.Signature ||
+			reg.Message.FeeRecipient != sentReg.Message.FeeRecipient ||
+			reg.Message.GasLimit != sentReg.Message.GasLimit ||
+			!reg.Message.Timestamp.Equal(sentReg.Message.Timestamp) {
+			return res, fmt.Errorf("invalid response: original data for %s differs from the registration sent", reg.Message.Pubkey.String())
+		}
 		res[reg.Message.Pubkey] = response.EcdsaSignatures[i]
 	}
 
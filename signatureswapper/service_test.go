@@ -0,0 +1,154 @@
+package signatureswapper_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/internal/testserver"
+	"github.com/restaking-cloud/native-delegation-for-plus/signatureswapper"
+)
+
+var testSwapperSignature = k2common.EcdsaSignature{R: common.HexToHash("0x01").Hex(), S: common.HexToHash("0x02").Hex(), V: 27}
+
+// testSwapper is a signature swapper echoing the registrations it is sent as the original data it signed, after
+// tamper changes them
+type testSwapper struct {
+	tamper func(representative *common.Address, message *apiv1.ValidatorRegistration)
+}
+
+func newTestSwapper(t *testing.T) *testserver.Server[testSwapper] {
+	t.Helper()
+
+	return testserver.New(t, testSwapper{}, func(s *testSwapper, w http.ResponseWriter, r *http.Request) {
+		echo := func(payload signatureswapper.SignatureSwapPayload) signatureswapper.OriginalDataForBatchResponse {
+			message := *payload.Message
+			representative := payload.RepresentativeAddress
+			if s.tamper != nil {
+				s.tamper(&representative, &message)
+			}
+			return signatureswapper.OriginalDataForBatchResponse{Message: &message, RepresentativeAddress: representative, Signature: payload.Signature}
+		}
+
+		switch r.URL.Path {
+		case signatureswapper.InfoPath:
+			w.Write([]byte(`{"CHAIN_ID":"1","BLS_DOMAIN":"0x00000001","GAS_LIMIT_PROPOSER_REGISTRY_DOMAIN":"1"}`))
+		case signatureswapper.GenerateSignaturePath:
+			var payload signatureswapper.SignatureSwapPayload
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			original := echo(payload)
+			json.NewEncoder(w).Encode(signatureswapper.SignatureSwapResponse{
+				OriginalData:   &apiv1.SignedValidatorRegistration{Message: original.Message, Signature: original.Signature},
+				EcdsaSignature: testSwapperSignature,
+			})
+		case signatureswapper.BatchGenerateSignaturePath:
+			var payload signatureswapper.BatchSignatureSwapPayload
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var response signatureswapper.BatchSignatureSwapResponse
+			for _, signature := range payload.Signatures {
+				response.OriginalData = append(response.OriginalData, echo(signature))
+				response.EcdsaSignatures = append(response.EcdsaSignatures, testSwapperSignature)
+			}
+			json.NewEncoder(w).Encode(response)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestSignatureSwapperService_OriginalData(t *testing.T) {
+
+	representative := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	registrations := []apiv1.SignedValidatorRegistration{
+		{
+			Message:   &apiv1.ValidatorRegistration{Pubkey: phase0.BLSPubKey{0xa1}, FeeRecipient: bellatrix.ExecutionAddress{0x01}, GasLimit: 30000000, Timestamp: time.Unix(1700000000, 0)},
+			Signature: phase0.BLSSignature{0xb1},
+		},
+		{
+			Message:   &apiv1.ValidatorRegistration{Pubkey: phase0.BLSPubKey{0xa2}, FeeRecipient: bellatrix.ExecutionAddress{0x01}, GasLimit: 30000000, Timestamp: time.Unix(1700000000, 0)},
+			Signature: phase0.BLSSignature{0xb2},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(representative *common.Address, message *apiv1.ValidatorRegistration)
+		wantErr string
+	}{
+		{
+			name: "original data matches",
+		},
+		{
+			name: "fee recipient changed",
+			tamper: func(_ *common.Address, message *apiv1.ValidatorRegistration) {
+				message.FeeRecipient = bellatrix.ExecutionAddress{0x02}
+			},
+			wantErr: "differs from the registration sent",
+		},
+		{
+			name:    "gas limit changed",
+			tamper:  func(_ *common.Address, message *apiv1.ValidatorRegistration) { message.GasLimit++ },
+			wantErr: "differs from the registration sent",
+		},
+		{
+			name:    "validator not sent",
+			tamper:  func(_ *common.Address, message *apiv1.ValidatorRegistration) { message.Pubkey = phase0.BLSPubKey{0xc1} },
+			wantErr: "differs from the registration sent",
+		},
+	}
+
+	swapper := newTestSwapper(t)
+	s := signatureswapper.NewSignatureSwapperService()
+	if err := s.Configure([]*url.URL{swapper.URL}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swapper.Set(func(s *testSwapper) { s.tamper = tt.tamper })
+
+			signature, err := s.GenerateSignature(registrations[0], representative)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("GenerateSignature() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil || signature != testSwapperSignature {
+				t.Errorf("GenerateSignature() = %+v, %v, want %+v", signature, err, testSwapperSignature)
+			}
+
+			signatures, err := s.BatchGenerateSignature(registrations, representative)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Errorf("BatchGenerateSignature() accepted tampered original data")
+				}
+			} else if err != nil || len(signatures) != len(registrations) {
+				t.Errorf("BatchGenerateSignature() = %v, %v, want a signature for each registration", signatures, err)
+			}
+		})
+	}
+
+	// the batch response must be for the representative the registrations were sent for
+	swapper.Set(func(s *testSwapper) {
+		s.tamper = func(representative *common.Address, _ *apiv1.ValidatorRegistration) {
+			*representative = common.Address{}
+		}
+	})
+	if _, err := s.BatchGenerateSignature(registrations, representative); err == nil {
+		t.Errorf("BatchGenerateSignature() accepted original data for another representative")
+	}
+}
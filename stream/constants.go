@@ -0,0 +1,33 @@
+package stream
+
+const (
+	TopicRegistrations = "registrations"
+	TopicTransactions  = "transactions"
+	TopicClaims        = "claims"
+	TopicExits         = "exits"
+	TopicLists         = "lists"
+	TopicCapacity      = "capacity"
+)
+
+var Topics = []string{
+	TopicRegistrations,
+	TopicTransactions,
+	TopicClaims,
+	TopicExits,
+	TopicLists,
+	TopicCapacity,
+}
+
+const (
+	// Events only published to the stream, in addition to the notifier events
+	EventRegistrationsProcessed = "registrations_processed"
+	EventTransactionSent        = "transaction_sent"
+	EventTransactionMined       = "transaction_mined"
+	EventListReloaded           = "list_reloaded"
+	EventCapacityUpdated        = "capacity_updated"
+)
+
+const (
+	// Query parameter used to subscribe to a comma-separated list of topics
+	TopicsQueryParam = "topics"
+)
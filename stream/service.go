@@ -0,0 +1,158 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/r3labs/sse/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+)
+
+// StreamService pushes module events to Server-Sent Events subscribers.
+// Every combination of topics a client subscribes to is served as its own sse stream
+type StreamService struct {
+	server *sse.Server
+	log    *logrus.Entry
+
+	lock    sync.RWMutex
+	streams map[string][]string // [Stream ID] -> Topics published to the stream
+}
+
+func NewStreamService() *StreamService {
+	server := sse.New()
+	// only push events as they happen rather than replaying the history to new subscribers
+	server.AutoReplay = false
+	server.AutoStream = false
+
+	return &StreamService{
+		server:  server,
+		log:     logrus.NewEntry(logrus.New()),
+		streams: make(map[string][]string),
+	}
+}
+
+func (s *StreamService) Configure(logger *logrus.Entry) {
+	s.log = logger.WithField("service", "stream")
+}
+
+// Notify publishes notifier events to the topic they relate to
+func (s *StreamService) Notify(event notifier.Event) {
+	switch event.Type {
+	case notifier.EventRegistration, notifier.EventDelegation, notifier.EventNoRegistrations, notifier.EventDelegationDeferred, notifier.EventValidatorKicked, notifier.EventNodeOperatorBanned:
+		s.Publish(TopicRegistrations, event)
+	case notifier.EventClaim:
+		s.Publish(TopicClaims, event)
+	case notifier.EventExit:
+		s.Publish(TopicExits, event)
+	case notifier.EventPayoutChange, notifier.EventClaimRecipientChange, notifier.EventTransactionFailed:
+		s.Publish(TopicTransactions, event)
+	case notifier.EventCapacityExhausted:
+		s.Publish(TopicCapacity, event)
+	}
+}
+
+// Publish sends the event to every stream subscribed to the topic, dropping it for streams that cannot keep up
+func (s *StreamService) Publish(topic string, event notifier.Event) {
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		s.log.WithError(err).WithField("event", event.Type).Error("Failed to encode stream event")
+		return
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for streamID, topics := range s.streams {
+		for _, streamTopic := range topics {
+			if streamTopic != topic {
+				continue
+			}
+			if !s.server.TryPublish(streamID, &sse.Event{
+				Event: []byte(event.Type),
+				Data:  data,
+			}) {
+				s.log.WithFields(logrus.Fields{
+					"stream": streamID,
+					"event":  event.Type,
+				}).Debug("Stream buffer full, dropping event")
+			}
+			break
+		}
+	}
+}
+
+// ServeHTTP subscribes the client to the topics requested, or all topics if none are requested
+func (s *StreamService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	topics, err := parseTopics(r.URL.Query().Get(TopicsQueryParam))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	streamID := strings.Join(topics, ",")
+
+	s.lock.Lock()
+	if _, ok := s.streams[streamID]; !ok {
+		s.server.CreateStream(streamID)
+		s.streams[streamID] = topics
+	}
+	s.lock.Unlock()
+
+	// the sse server reads the stream to serve from the request
+	query := r.URL.Query()
+	query.Set("stream", streamID)
+	r.URL.RawQuery = query.Encode()
+
+	s.server.ServeHTTP(w, r)
+}
+
+func (s *StreamService) Close() {
+	s.server.Close()
+}
+
+func parseTopics(param string) ([]string, error) {
+
+	if param == "" {
+		return Topics, nil
+	}
+
+	requested := make(map[string]bool)
+	for _, topic := range strings.Split(param, ",") {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+		supported := false
+		for _, t := range Topics {
+			if t == topic {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, fmt.Errorf("unknown topic %q, supported topics are %s", topic, strings.Join(Topics, ","))
+		}
+		requested[topic] = true
+	}
+
+	if len(requested) == 0 {
+		return Topics, nil
+	}
+
+	topics := make([]string, 0, len(requested))
+	for topic := range requested {
+		topics = append(topics, topic)
+	}
+	// sort so that the same combination of topics shares a stream
+	sort.Strings(topics)
+
+	return topics, nil
+}
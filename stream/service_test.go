@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+)
+
+func TestParseTopics(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		param   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "all topics when none requested",
+			param: "",
+			want:  Topics,
+		},
+		{
+			name:  "sorted and deduplicated",
+			param: "exits, claims,exits",
+			want:  []string{TopicClaims, TopicExits},
+		},
+		{
+			name:  "all topics when only separators",
+			param: ",,",
+			want:  Topics,
+		},
+		{
+			name:    "unknown topic",
+			param:   "claims,blocks",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTopics(tt.param)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTopics() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTopics() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamService_UnknownTopic(t *testing.T) {
+
+	s := NewStreamService()
+	defer s.Close()
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?topics=blocks", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestStreamService_Subscribe(t *testing.T) {
+
+	s := NewStreamService()
+	server := httptest.NewServer(s)
+	defer server.Close()
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?"+TopicsQueryParam+"="+TopicClaims, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("subscribe error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	events := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if event, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	// the subscriber is registered asynchronously so publish until the stream delivers
+	deadline := time.After(5 * time.Second)
+	for {
+		s.Notify(notifier.Event{Type: notifier.EventExit})
+		s.Notify(notifier.Event{Type: notifier.EventClaim})
+
+		select {
+		case event := <-events:
+			if event != notifier.EventClaim {
+				t.Fatalf("received event %q, want only events on the %s topic", event, TopicClaims)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no event received")
+		}
+	}
+}
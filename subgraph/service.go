@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
 	"github.com/restaking-cloud/native-delegation-for-plus/subgraph/config"
 
 	graphql "github.com/hasura/go-graphql-client"
@@ -30,7 +32,9 @@ func (s *SubgraphService) Configure(url *url.URL) error {
 
 	s.cfg.Url = url
 
-	s.client = graphql.NewClient(url.String(), nil)
+	s.client = graphql.NewClient(url.String(), &http.Client{
+		Transport: metrics.NewTransport(metrics.DependencySubgraph),
+	})
 
 	return nil
 }
@@ -2,25 +2,27 @@ package k2
 
 import (
 	"crypto/ecdsa"
-	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"strconv"
+	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/fsnotify/fsnotify"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	eth1Common "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
 
 	"github.com/pon-network/mev-plus/common"
 	"github.com/restaking-cloud/native-delegation-for-plus/config"
+	"github.com/restaking-cloud/native-delegation-for-plus/notifier"
+	"github.com/restaking-cloud/native-delegation-for-plus/stream"
 )
 
 func (k2 *K2Service) parseConfig(moduleFlags common.ModuleFlags) (err error) {
@@ -55,20 +57,39 @@ func (k2 *K2Service) parseConfig(moduleFlags common.ModuleFlags) (err error) {
 				})
 			}
 
+		case config.WalletMnemonicFlag.Name:
+			k2.cfg.WalletMnemonic = strings.Join(strings.Fields(flagValue), " ")
+		case config.WalletDerivationPathFlag.Name:
+			if _, err := accounts.ParseDerivationPath(flagValue); err != nil {
+				return fmt.Errorf("-%s: invalid derivation path %q", config.WalletDerivationPathFlag.Name, flagValue)
+			}
+			k2.cfg.WalletDerivationPath = flagValue
 		case config.Web3SignerUrlFlag.Name:
 			k2.cfg.Web3SignerUrl, err = k2common.CreateUrl(flagValue)
 			if err != nil {
 				return fmt.Errorf("-%s: invalid url %q", config.Web3SignerUrlFlag.Name, flagValue)
 			}
 		case config.BeaconNodeUrlFlag.Name:
-			k2.cfg.BeaconNodeUrl, err = k2common.CreateUrl(flagValue)
-			if err != nil {
-				return fmt.Errorf("-%s: invalid url %q", config.BeaconNodeUrlFlag.Name, flagValue)
+			for _, urlStr := range strings.Split(flagValue, ",") {
+				if urlStr == "" {
+					continue
+				}
+				beaconNodeUrl, err := k2common.CreateUrl(urlStr)
+				if err != nil {
+					return fmt.Errorf("-%s: invalid url %q", config.BeaconNodeUrlFlag.Name, urlStr)
+				}
+				k2.cfg.BeaconNodeUrls = append(k2.cfg.BeaconNodeUrls, beaconNodeUrl)
 			}
 		case config.ExecutionNodeUrlFlag.Name:
-			k2.cfg.ExecutionNodeUrl, err = k2common.CreateUrl(flagValue)
-			if err != nil {
-				return fmt.Errorf("-%s: invalid url %q", config.ExecutionNodeUrlFlag.Name, flagValue)
+			for _, urlStr := range strings.Split(flagValue, ",") {
+				if urlStr == "" {
+					continue
+				}
+				executionNodeUrl, err := k2common.CreateUrl(urlStr)
+				if err != nil {
+					return fmt.Errorf("-%s: invalid url %q", config.ExecutionNodeUrlFlag.Name, urlStr)
+				}
+				k2.cfg.ExecutionNodeUrls = append(k2.cfg.ExecutionNodeUrls, executionNodeUrl)
 			}
 		case config.PayoutRecipientFlag.Name:
 			k2.cfg.PayoutRecipient = eth1Common.HexToAddress(flagValue)
@@ -81,6 +102,24 @@ func (k2 *K2Service) parseConfig(moduleFlags common.ModuleFlags) (err error) {
 			k2.cfg.StrictInclusionListFile = flagValue
 		case config.RepresentativeMappingFlag.Name:
 			k2.cfg.RepresentativeMappingFile = flagValue
+		case config.ValidatorMetadataFlag.Name:
+			k2.cfg.ValidatorMetadataFile = flagValue
+		case config.DelegationPolicyFlag.Name:
+			k2.cfg.DelegationPolicyFile = flagValue
+		case config.RepresentativeStrategyFlag.Name:
+			supported := false
+			for _, strategy := range config.RepresentativeStrategies {
+				if flagValue == strategy {
+					supported = true
+					break
+				}
+			}
+			if !supported {
+				return fmt.Errorf("-%s: unknown representative strategy %q, supported strategies are %s", config.RepresentativeStrategyFlag.Name, flagValue, strings.Join(config.RepresentativeStrategies, ","))
+			}
+			k2.cfg.RepresentativeStrategy = flagValue
+		case config.RepresentativeAssignmentFileFlag.Name:
+			k2.cfg.RepresentativeAssignmentFile = flagValue
 		case config.MaxGasPriceFlag.Name:
 			setMaxGasPrice, err := strconv.ParseUint(flagValue, 10, 64)
 			if err != nil {
@@ -109,6 +148,11 @@ func (k2 *K2Service) parseConfig(moduleFlags common.ModuleFlags) (err error) {
 			if k2.cfg.ClaimThreshold < 0 {
 				return fmt.Errorf("-%s: claim threshold KETH amount must be positive", config.ClaimThresholdFlag.Name)
 			}
+		case config.KETHTreasuryFlag.Name:
+			if !eth1Common.IsHexAddress(flagValue) || eth1Common.HexToAddress(flagValue) == (eth1Common.Address{}) {
+				return fmt.Errorf("-%s: invalid address %q", config.KETHTreasuryFlag.Name, flagValue)
+			}
+			k2.cfg.KETHTreasury = eth1Common.HexToAddress(flagValue)
 		case config.K2LendingContractAddressFlag.Name:
 			k2.cfg.K2LendingContractAddress = eth1Common.HexToAddress(flagValue)
 			if k2.cfg.K2LendingContractAddress == (eth1Common.Address{}) {
@@ -125,26 +169,151 @@ func (k2 *K2Service) parseConfig(moduleFlags common.ModuleFlags) (err error) {
 				return fmt.Errorf("-%s: invalid address %q", config.ProposerRegistryContractAddressFlag.Name, flagValue)
 			}
 		case config.SignatureSwapperUrlFlag.Name:
-			k2.cfg.SignatureSwapperUrl, err = k2common.CreateUrl(flagValue)
-			if err != nil {
-				return fmt.Errorf("-%s: invalid url %q", config.SignatureSwapperUrlFlag.Name, flagValue)
+			for _, urlStr := range strings.Split(flagValue, ",") {
+				if urlStr == "" {
+					continue
+				}
+				signatureSwapperUrl, err := k2common.CreateUrl(urlStr)
+				if err != nil {
+					return fmt.Errorf("-%s: invalid url %q", config.SignatureSwapperUrlFlag.Name, urlStr)
+				}
+				k2.cfg.SignatureSwapperUrls = append(k2.cfg.SignatureSwapperUrls, signatureSwapperUrl)
 			}
 		case config.BalanceVerificationUrlFlag.Name:
-			k2.cfg.BalanceVerificationUrl, err = k2common.CreateUrl(flagValue)
-			if err != nil {
-				return fmt.Errorf("-%s: invalid url %q", config.BalanceVerificationUrlFlag.Name, flagValue)
+			for _, urlStr := range strings.Split(flagValue, ",") {
+				if urlStr == "" {
+					continue
+				}
+				balanceVerificationUrl, err := k2common.CreateUrl(urlStr)
+				if err != nil {
+					return fmt.Errorf("-%s: invalid url %q", config.BalanceVerificationUrlFlag.Name, urlStr)
+				}
+				k2.cfg.BalanceVerificationUrls = append(k2.cfg.BalanceVerificationUrls, balanceVerificationUrl)
 			}
 		case config.SubgraphUrlFlag.Name:
 			k2.cfg.SubgraphUrl, err = k2common.CreateUrl(flagValue)
 			if err != nil {
 				return fmt.Errorf("-%s: invalid url %q", config.SubgraphUrlFlag.Name, flagValue)
 			}
+		case config.LowBalanceThresholdFlag.Name:
+			k2.cfg.LowBalanceThreshold, err = strconv.ParseFloat(flagValue, 64)
+			if err != nil {
+				return fmt.Errorf("-%s: invalid low balance threshold ETH amount %q", config.LowBalanceThresholdFlag.Name, flagValue)
+			}
+			// ensure the low balance threshold is positive
+			if k2.cfg.LowBalanceThreshold < 0 {
+				return fmt.Errorf("-%s: low balance threshold ETH amount must be positive", config.LowBalanceThresholdFlag.Name)
+			}
+		case config.BalanceCheckIntervalFlag.Name:
+			k2.cfg.BalanceCheckInterval, err = time.ParseDuration(flagValue)
+			if err != nil {
+				return fmt.Errorf("-%s: invalid balance check interval %q", config.BalanceCheckIntervalFlag.Name, flagValue)
+			}
+			if k2.cfg.BalanceCheckInterval <= 0 {
+				return fmt.Errorf("-%s: balance check interval must be greater than zero", config.BalanceCheckIntervalFlag.Name)
+			}
+		case config.RunwayWarningThresholdFlag.Name:
+			k2.cfg.RunwayWarningThreshold, err = strconv.ParseUint(flagValue, 10, 64)
+			if err != nil {
+				return fmt.Errorf("-%s: invalid runway warning threshold %q", config.RunwayWarningThresholdFlag.Name, flagValue)
+			}
+		case config.RunwayAlertThresholdFlag.Name:
+			k2.cfg.RunwayAlertThreshold, err = strconv.ParseUint(flagValue, 10, 64)
+			if err != nil {
+				return fmt.Errorf("-%s: invalid runway alert threshold %q", config.RunwayAlertThresholdFlag.Name, flagValue)
+			}
+		case config.NodeOperatorCheckIntervalFlag.Name:
+			k2.cfg.NodeOperatorCheckInterval, err = time.ParseDuration(flagValue)
+			if err != nil {
+				return fmt.Errorf("-%s: invalid node operator check interval %q", config.NodeOperatorCheckIntervalFlag.Name, flagValue)
+			}
+			if k2.cfg.NodeOperatorCheckInterval <= 0 {
+				return fmt.Errorf("-%s: node operator check interval must be greater than zero", config.NodeOperatorCheckIntervalFlag.Name)
+			}
+		case config.NodeHealthCheckIntervalFlag.Name:
+			k2.cfg.NodeHealthCheckInterval, err = time.ParseDuration(flagValue)
+			if err != nil {
+				return fmt.Errorf("-%s: invalid node health check interval %q", config.NodeHealthCheckIntervalFlag.Name, flagValue)
+			}
+			if k2.cfg.NodeHealthCheckInterval <= 0 {
+				return fmt.Errorf("-%s: node health check interval must be greater than zero", config.NodeHealthCheckIntervalFlag.Name)
+			}
+		case config.WebhookUrlsFlag.Name:
+			for _, urlStr := range strings.Split(flagValue, ",") {
+				if urlStr == "" {
+					continue
+				}
+				webhookUrl, err := k2common.CreateUrl(urlStr)
+				if err != nil {
+					return fmt.Errorf("-%s: invalid url %q", config.WebhookUrlsFlag.Name, urlStr)
+				}
+				k2.cfg.WebhookUrls = append(k2.cfg.WebhookUrls, webhookUrl)
+			}
+		case config.WebhookSecretFlag.Name:
+			k2.cfg.WebhookSecret = flagValue
+		case config.WebhookEventsFlag.Name:
+			for _, event := range strings.Split(flagValue, ",") {
+				if event == "" {
+					continue
+				}
+				supported := false
+				for _, eventType := range notifier.EventTypes {
+					if event == eventType {
+						supported = true
+						break
+					}
+				}
+				if !supported {
+					return fmt.Errorf("-%s: unknown event type %q, supported event types are %s", config.WebhookEventsFlag.Name, event, strings.Join(notifier.EventTypes, ","))
+				}
+				k2.cfg.WebhookEvents = append(k2.cfg.WebhookEvents, event)
+			}
+		case config.WebhookRepresentativesFlag.Name:
+			for _, addressStr := range strings.Split(flagValue, ",") {
+				if addressStr == "" {
+					continue
+				}
+				if !eth1Common.IsHexAddress(addressStr) {
+					return fmt.Errorf("-%s: invalid address %q", config.WebhookRepresentativesFlag.Name, addressStr)
+				}
+				k2.cfg.WebhookRepresentatives = append(k2.cfg.WebhookRepresentatives, eth1Common.HexToAddress(addressStr))
+			}
+		case config.WebhookMaxRetriesFlag.Name:
+			k2.cfg.WebhookMaxRetries, err = strconv.ParseUint(flagValue, 10, 64)
+			if err != nil {
+				return fmt.Errorf("-%s: invalid webhook max retries %q", config.WebhookMaxRetriesFlag.Name, flagValue)
+			}
+		case config.WebhookDeadLetterFileFlag.Name:
+			k2.cfg.WebhookDeadLetterFile = flagValue
+		case config.AuditLogFileFlag.Name:
+			k2.cfg.AuditLogFile = flagValue
+		case config.ListPollIntervalFlag.Name:
+			k2.cfg.ListPollInterval, err = time.ParseDuration(flagValue)
+			if err != nil {
+				return fmt.Errorf("-%s: invalid list poll interval %q", config.ListPollIntervalFlag.Name, flagValue)
+			}
+			if k2.cfg.ListPollInterval <= 0 {
+				return fmt.Errorf("-%s: list poll interval must be greater than zero", config.ListPollIntervalFlag.Name)
+			}
+		case config.ListSignerAddressFlag.Name:
+			if !eth1Common.IsHexAddress(flagValue) {
+				return fmt.Errorf("-%s: invalid address %q", config.ListSignerAddressFlag.Name, flagValue)
+			}
+			k2.cfg.ListSignerAddress = eth1Common.HexToAddress(flagValue)
 		default:
 			return fmt.Errorf("unknown flag %q", flagName)
 		}
 
 	}
 
+	// prepare the derivation of representative wallets once the derivation path is known
+	if k2.cfg.WalletMnemonic != "" {
+		err = k2.configureHDWallet()
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(moduleFlags) > 0 {
 		k2.lock.Lock()
 		k2.configured = true
@@ -169,18 +338,23 @@ func (k2 *K2Service) checkConfig() error {
 	k2.configured = false
 
 	// check that the execution node url is set
-	if k2.cfg.ExecutionNodeUrl == nil {
+	if len(k2.cfg.ExecutionNodeUrls) == 0 {
 		return fmt.Errorf("-%s: execution node url is required", config.ExecutionNodeUrlFlag.Name)
 	}
 
 	// check that the beacon node url is set
-	if k2.cfg.BeaconNodeUrl == nil {
+	if len(k2.cfg.BeaconNodeUrls) == 0 {
 		return fmt.Errorf("-%s: beacon node url is required", config.BeaconNodeUrlFlag.Name)
 	}
 
 	// check that the wallet private key is set
-	if len(k2.cfg.ValidatorWallets) == 0 {
-		return fmt.Errorf("-%s: a validator wallet private key is required", config.WalletPrivateKeyFlag.Name)
+	if len(k2.validatorWallets()) == 0 {
+		return fmt.Errorf("-%s: a validator wallet private key or -%s is required", config.WalletPrivateKeyFlag.Name, config.WalletMnemonicFlag.Name)
+	}
+
+	// check that the wallets derived from the mnemonic can be recorded in the representative mapping
+	if k2.hdWallet != nil && (k2.cfg.RepresentativeMappingFile == "" || isRemoteList(k2.cfg.RepresentativeMappingFile)) {
+		return fmt.Errorf("-%s: a representative mapping file is required to record the representative wallets derived from the mnemonic", config.RepresentativeMappingFlag.Name)
 	}
 
 	// check that the web3 signer url is set
@@ -188,6 +362,24 @@ func (k2 *K2Service) checkConfig() error {
 		return fmt.Errorf("-%s: web3 signer url is required in order to use a custom payout recepient", config.Web3SignerUrlFlag.Name)
 	}
 
+	// check that the runway thresholds are in order
+	if k2.cfg.RunwayAlertThreshold > k2.cfg.RunwayWarningThreshold {
+		return fmt.Errorf("-%s: runway alert threshold must not be greater than the runway warning threshold", config.RunwayAlertThresholdFlag.Name)
+	}
+
+	// check that the webhook secret is set to sign notifications
+	if len(k2.cfg.WebhookUrls) > 0 && k2.cfg.WebhookSecret == "" {
+		return fmt.Errorf("-%s: webhook secret is required in order to post signed notifications", config.WebhookSecretFlag.Name)
+	}
+
+	// check if validator metadata file is set, before the lists whose rules match validators by its tags
+	if k2.cfg.ValidatorMetadataFile != "" {
+		err := k2.readValidatorMetadata(k2.cfg.ValidatorMetadataFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	// check if exclusion list file is set
 	if k2.cfg.ExclusionListFile != "" {
 		err := k2.readExclusionList(k2.cfg.ExclusionListFile)
@@ -212,43 +404,94 @@ func (k2 *K2Service) checkConfig() error {
 		}
 	}
 
+	// check that the representatives selected by a strategy can be persisted to stay stable
+	if k2.cfg.RepresentativeStrategy != config.RepresentativeStrategyPriority {
+		if k2.cfg.RepresentativeAssignmentFile == "" {
+			return fmt.Errorf("-%s: a representative assignment file is required to persist the representatives selected by the %s strategy", config.RepresentativeAssignmentFileFlag.Name, k2.cfg.RepresentativeStrategy)
+		}
+		err := k2.readRepresentativeAssignments(k2.cfg.RepresentativeAssignmentFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if delegation policy file is set
+	if k2.cfg.DelegationPolicyFile != "" {
+		err := k2.readDelegationPolicy(k2.cfg.DelegationPolicyFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	k2.configured = true
 
 	return nil
 }
 
-func (k2 *K2Service) readExclusionList(filePath string) error {
+func (k2 *K2Service) readExclusionList(source string) error {
+	return k2.readListSource("exclusion list", source, k2.loadExclusionList)
+}
+
+// loadExclusionList decodes and applies the exclusion list read from its file or url
+func (k2 *K2Service) loadExclusionList(source string, content []byte) error {
 
-	// Read the exclusion list file
-	file, err := os.Open(filePath)
+	var exclusionList []k2common.ValidatorFilter
+	err := k2common.DecodeList(source, content, &exclusionList)
 	if err != nil {
-		return fmt.Errorf("failed to open exclusion list file: %w", err)
+		return fmt.Errorf("failed to parse exclusion list: %w", err)
 	}
-	defer file.Close()
-	fileContent, err := io.ReadAll(file)
+
+	// Store the exclusion list
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
+
+	preparedExclusionList, err := k2.prepareExclusionList(exclusionList)
 	if err != nil {
-		return fmt.Errorf("failed to read exclusion list file: %w", err)
+		return err
 	}
 
-	var exclusionList []k2common.ValidatorFilter
-	err = json.Unmarshal(fileContent, &exclusionList)
-	if err != nil {
-		return fmt.Errorf("failed to parse exclusion list file: %w", err)
+	if err := k2.listValidationError(preparedExclusionList, k2.strictInclusionList, k2.representativeMapping); err != nil {
+		return err
 	}
 
-	preparedExclusionList := make(map[string]k2common.ValidatorFilter)
+	k2.exclusionList = preparedExclusionList
 
-	// Store the exclusion list
-	k2.lock.Lock()
-	defer k2.lock.Unlock()
+	if len(k2.exclusionList) > 0 {
+		k2.log.Infof("Exclusion list updated with %d filters", len(k2.exclusionList))
+	}
+
+	return nil
+}
+
+// prepareExclusionList validates the exclusion list entries against each other and the strict inclusion list.
+// k2.listLock must be held by the caller
+func (k2 *K2Service) prepareExclusionList(exclusionList []k2common.ValidatorFilter) (map[string]k2common.ValidatorFilter, error) {
+
+	preparedExclusionList := make(map[string]k2common.ValidatorFilter)
 	for _, entry := range exclusionList {
+		if err := k2.checkFilterRule("exclusion", entry); err != nil {
+			return nil, err
+		}
+
+		if entry.Rule != "" {
+			if entry.ProposerRegistration && entry.NativeDelegation {
+				return nil, fmt.Errorf("invalid exclusion list rule %s, cannot exclude validators by the rule, as it has been set to be allowed for both proposer registration and native delegation", entry.Rule)
+			}
+			// check if the rule is already in the exclusion list
+			if _, ok := preparedExclusionList[filterRuleKey(entry.Rule)]; ok {
+				return nil, fmt.Errorf("duplicate rule %s in exclusion list", entry.Rule)
+			}
+			preparedExclusionList[filterRuleKey(entry.Rule)] = entry
+			continue
+		}
+
 		// check if both a PublicKey and FeeRecipient are specified
 		if entry.PublicKey != (phase0.BLSPubKey{}) && entry.FeeRecipient != (eth1Common.Address{}) {
-			return fmt.Errorf("invalid exclusion list entry [%s, %s], cannot specify both PublicKey and FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
+			return nil, fmt.Errorf("invalid exclusion list entry [%s, %s], cannot specify both PublicKey and FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
 		}
 
 		if entry.PublicKey == (phase0.BLSPubKey{}) && entry.FeeRecipient == (eth1Common.Address{}) {
-			return fmt.Errorf("invalid exclusion list entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
+			return nil, fmt.Errorf("invalid exclusion list entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
 		}
 
 		if entry.ProposerRegistration && entry.NativeDelegation {
@@ -259,83 +502,109 @@ func (k2 *K2Service) readExclusionList(filePath string) error {
 				entryFor = entry.FeeRecipient.String()
 			}
 
-			return fmt.Errorf("invalid exclusion list entry for %s, cannot exclude %s, as it has been set to be allowed for both proposer registration and native delegation", entryForText, entryFor)
+			return nil, fmt.Errorf("invalid exclusion list entry for %s, cannot exclude %s, as it has been set to be allowed for both proposer registration and native delegation", entryForText, entryFor)
 		}
 
 		if entry.PublicKey != (phase0.BLSPubKey{}) {
 			// check if validator is already in the exclusion list
 			if _, ok := preparedExclusionList[strings.ToLower(entry.PublicKey.String())]; ok {
-				return fmt.Errorf("duplicate validator %s in exclusion list", entry.PublicKey.String())
+				return nil, fmt.Errorf("duplicate validator %s in exclusion list", entry.PublicKey.String())
 			}
 			// check if validator is in the inclusion list
 			if _, ok := k2.strictInclusionList[strings.ToLower(entry.PublicKey.String())]; ok {
-				return fmt.Errorf("validator %s is in both exclusion and inclusion list", entry.PublicKey.String())
+				return nil, fmt.Errorf("validator %s is in both exclusion and inclusion list", entry.PublicKey.String())
 			}
 			preparedExclusionList[strings.ToLower(entry.PublicKey.String())] = entry
 		} else if entry.FeeRecipient != (eth1Common.Address{}) {
 			// check if fee recipient is already in the exclusion list
 			if _, ok := preparedExclusionList[strings.ToLower(entry.FeeRecipient.String())]; ok {
-				return fmt.Errorf("duplicate fee recipient %s in exclusion list", entry.FeeRecipient.String())
+				return nil, fmt.Errorf("duplicate fee recipient %s in exclusion list", entry.FeeRecipient.String())
 			}
 			// check if fee recipient is in the inclusion list
 			if _, ok := k2.strictInclusionList[strings.ToLower(entry.FeeRecipient.String())]; ok {
-				return fmt.Errorf("fee recipient %s is in both exclusion and inclusion list", entry.FeeRecipient.String())
+				return nil, fmt.Errorf("fee recipient %s is in both exclusion and inclusion list", entry.FeeRecipient.String())
 			}
 			preparedExclusionList[strings.ToLower(entry.FeeRecipient.String())] = entry
 		} else {
 			// should never reach here but to ensure we do not proceed past an invalid entry in the file
-			return fmt.Errorf("invalid exclusion list entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
+			return nil, fmt.Errorf("invalid exclusion list entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
 		}
 	}
 
-	k2.exclusionList = preparedExclusionList
-
-	if len(k2.exclusionList) > 0 {
-		k2.log.Infof("Exclusion list updated with %d filters", len(k2.exclusionList))
-	}
-
-	return nil
+	return preparedExclusionList, nil
 }
 
 func (k2 *K2Service) clearExclusionList() error {
-	k2.lock.Lock()
-	defer k2.lock.Unlock()
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
 	k2.exclusionList = make(map[string]k2common.ValidatorFilter)
 	return nil
 }
 
-func (k2 *K2Service) readInclusionList(filePath string) error {
+func (k2 *K2Service) readInclusionList(source string) error {
+	return k2.readListSource("inclusion list", source, k2.loadInclusionList)
+}
+
+// loadInclusionList decodes and applies the inclusion list read from its file or url
+func (k2 *K2Service) loadInclusionList(source string, content []byte) error {
 
-	// Read the inclusion list file
-	file, err := os.Open(filePath)
+	var inclusionList []k2common.ValidatorFilter
+	err := k2common.DecodeList(source, content, &inclusionList)
 	if err != nil {
-		return fmt.Errorf("failed to open inclusion list file: %w", err)
+		return fmt.Errorf("failed to parse inclusion list: %w", err)
 	}
-	defer file.Close()
-	fileContent, err := io.ReadAll(file)
+
+	// Store the inclusion list
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
+
+	preparedInclusionList, err := k2.prepareInclusionList(inclusionList)
 	if err != nil {
-		return fmt.Errorf("failed to read inclusion list file: %w", err)
+		return err
 	}
 
-	var inclusionList []k2common.ValidatorFilter
-	err = json.Unmarshal(fileContent, &inclusionList)
-	if err != nil {
-		return fmt.Errorf("failed to parse inclusion list file: %w", err)
+	if err := k2.listValidationError(k2.exclusionList, preparedInclusionList, k2.representativeMapping); err != nil {
+		return err
 	}
 
-	preparedInclusionList := make(map[string]k2common.ValidatorFilter)
+	k2.strictInclusionList = preparedInclusionList
 
-	// Store the inclusion list
-	k2.lock.Lock()
-	defer k2.lock.Unlock()
+	if len(k2.strictInclusionList) > 0 {
+		k2.log.Infof("Strict inclusion list updated with %d filters", len(k2.strictInclusionList))
+	}
+
+	return nil
+}
+
+// prepareInclusionList validates the strict inclusion list entries against each other and the exclusion list.
+// k2.listLock must be held by the caller
+func (k2 *K2Service) prepareInclusionList(inclusionList []k2common.ValidatorFilter) (map[string]k2common.ValidatorFilter, error) {
+
+	preparedInclusionList := make(map[string]k2common.ValidatorFilter)
 	for _, entry := range inclusionList {
+		if err := k2.checkFilterRule("inclusion", entry); err != nil {
+			return nil, err
+		}
+
+		if entry.Rule != "" {
+			if !entry.ProposerRegistration && !entry.NativeDelegation {
+				return nil, fmt.Errorf("invalid inclusion list rule %s, cannot include validators by the rule, as it has been set to process neither proposer registration nor native delegation", entry.Rule)
+			}
+			// check if the rule is already in the inclusion list
+			if _, ok := preparedInclusionList[filterRuleKey(entry.Rule)]; ok {
+				return nil, fmt.Errorf("duplicate rule %s in inclusion list", entry.Rule)
+			}
+			preparedInclusionList[filterRuleKey(entry.Rule)] = entry
+			continue
+		}
+
 		// check if both a PublicKey and FeeRecipient are specified
 		if entry.PublicKey != (phase0.BLSPubKey{}) && entry.FeeRecipient != (eth1Common.Address{}) {
-			return fmt.Errorf("invalid inclusion list entry [%s, %s], cannot specify both PublicKey and FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
+			return nil, fmt.Errorf("invalid inclusion list entry [%s, %s], cannot specify both PublicKey and FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
 		}
 
 		if entry.PublicKey == (phase0.BLSPubKey{}) && entry.FeeRecipient == (eth1Common.Address{}) {
-			return fmt.Errorf("invalid inclusion list entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
+			return nil, fmt.Errorf("invalid inclusion list entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
 		}
 
 		if !entry.ProposerRegistration && !entry.NativeDelegation {
@@ -346,139 +615,156 @@ func (k2 *K2Service) readInclusionList(filePath string) error {
 				entryFor = entry.FeeRecipient.String()
 			}
 
-			return fmt.Errorf("invalid inclusion list entry for %s, cannot include %s, as it has been set to be to not process both proposer registration and native delegation", entryForText, entryFor)
+			return nil, fmt.Errorf("invalid inclusion list entry for %s, cannot include %s, as it has been set to be to not process both proposer registration and native delegation", entryForText, entryFor)
 		}
 
 		if entry.PublicKey != (phase0.BLSPubKey{}) {
 			// check if validator is already in the inclusion list
 			if _, ok := preparedInclusionList[strings.ToLower(entry.PublicKey.String())]; ok {
-				return fmt.Errorf("duplicate validator %s in inclusion list", entry.PublicKey.String())
+				return nil, fmt.Errorf("duplicate validator %s in inclusion list", entry.PublicKey.String())
 			}
 			// check if validator is in the exclusion list
 			if _, ok := k2.exclusionList[strings.ToLower(entry.PublicKey.String())]; ok {
-				return fmt.Errorf("validator %s is in both exclusion and inclusion list", entry.PublicKey.String())
+				return nil, fmt.Errorf("validator %s is in both exclusion and inclusion list", entry.PublicKey.String())
 			}
 			preparedInclusionList[strings.ToLower(entry.PublicKey.String())] = entry
 		} else if entry.FeeRecipient != (eth1Common.Address{}) {
 			// check if fee recipient is already in the inclusion list
 			if _, ok := preparedInclusionList[strings.ToLower(entry.FeeRecipient.String())]; ok {
-				return fmt.Errorf("duplicate fee recipient %s in inclusion list", entry.FeeRecipient.String())
+				return nil, fmt.Errorf("duplicate fee recipient %s in inclusion list", entry.FeeRecipient.String())
 			}
 			// check if fee recipient is in the exclusion list
 			if _, ok := k2.exclusionList[strings.ToLower(entry.FeeRecipient.String())]; ok {
-				return fmt.Errorf("fee recipient %s is in both exclusion and inclusion list", entry.FeeRecipient.String())
+				return nil, fmt.Errorf("fee recipient %s is in both exclusion and inclusion list", entry.FeeRecipient.String())
 			}
 			preparedInclusionList[strings.ToLower(entry.FeeRecipient.String())] = entry
 		} else {
 			// should never reach here but to ensure we do not proceed past an invalid entry in the file
-			return fmt.Errorf("invalid exclusion list entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
+			return nil, fmt.Errorf("invalid exclusion list entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", entry.PublicKey.String(), entry.FeeRecipient.String())
 		}
 	}
 
-	k2.strictInclusionList = preparedInclusionList
-
-	if len(k2.strictInclusionList) > 0 {
-		k2.log.Infof("Strict inclusion list updated with %d filters", len(k2.strictInclusionList))
-	}
-
-	return nil
+	return preparedInclusionList, nil
 }
 
 func (k2 *K2Service) clearInclusionList() error {
-	k2.lock.Lock()
-	defer k2.lock.Unlock()
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
 	k2.strictInclusionList = make(map[string]k2common.ValidatorFilter)
 	return nil
 }
 
-func (k2 *K2Service) readRepresentativeMapping(filePath string) error {
-	// Read the representative mapping file
-	file, err := os.Open(filePath)
+func (k2 *K2Service) readRepresentativeMapping(source string) error {
+	return k2.readListSource("representative mapping", source, k2.loadRepresentativeMapping)
+}
+
+// loadRepresentativeMapping decodes and applies the representative mapping read from its file or url
+func (k2 *K2Service) loadRepresentativeMapping(source string, content []byte) error {
+
+	var representativeMappingList []k2common.CustomPayoutRepresentative
+	err := k2common.DecodeList(source, content, &representativeMappingList)
 	if err != nil {
-		return fmt.Errorf("failed to open representative mapping file: %w", err)
+		return fmt.Errorf("failed to parse representative mapping: %w", err)
 	}
-	defer file.Close()
-	fileContent, err := io.ReadAll(file)
+
+	derived, err := k2.deriveMappedWallets(representativeMappingList)
 	if err != nil {
-		return fmt.Errorf("failed to read representative mapping file: %w", err)
+		return err
+	}
+	if len(derived) > 0 {
+		// only a mapping with derivation indices waits on in-flight processing, to add its wallets
+		k2.lock.Lock()
+		defer k2.lock.Unlock()
 	}
 
-	var representativeMappingList []k2common.CustomPayoutRepresentative
-	err = json.Unmarshal(fileContent, &representativeMappingList)
+	// Store the representative mapping
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
+
+	preparedRepresentativeMapping, err := k2.prepareRepresentativeMapping(representativeMappingList, derived...)
 	if err != nil {
-		return fmt.Errorf("failed to parse exclusion list file: %w", err)
+		return err
+	}
+
+	if err := k2.listValidationError(k2.exclusionList, k2.strictInclusionList, preparedRepresentativeMapping, derived...); err != nil {
+		return err
+	}
+
+	k2.addDerivedWallets(derived)
+	k2.representativeMapping = preparedRepresentativeMapping
+
+	if len(k2.representativeMapping) > 0 {
+		k2.log.Infof("Representative mapping updated with %d filters", len(k2.representativeMapping))
 	}
 
+	return nil
+}
+
+// prepareRepresentativeMapping validates the representative mapping entries against each other and the configured wallets,
+// counting the wallets derived for the mapping that are added once it is accepted as configured.
+// k2.listLock must be held by the caller
+func (k2 *K2Service) prepareRepresentativeMapping(representativeMappingList []k2common.CustomPayoutRepresentative, derived ...derivedWallet) (map[string]eth1Common.Address, error) {
+
+	configuredWallets := walletAddresses(k2.validatorWallets(), derived)
+
 	preparedRepresentativeMapping := make(map[string]eth1Common.Address)
 	trackRepresentativeMapping := make(map[string]eth1Common.Address)
-
-	// Store the representative mapping
-	k2.lock.Lock()
-	defer k2.lock.Unlock()
 	for _, representativeMapping := range representativeMappingList {
 		if representativeMapping.RepresentativeAddress == (eth1Common.Address{}) {
-			return fmt.Errorf("invalid representative address %s in representative mapping", representativeMapping.RepresentativeAddress.String())
+			return nil, fmt.Errorf("invalid representative address %s in representative mapping", representativeMapping.RepresentativeAddress.String())
 		} else {
 			// check if representative address is in configured wallets
-			found := false
-			for _, wallet := range k2.cfg.ValidatorWallets {
-				if wallet.Address == representativeMapping.RepresentativeAddress {
-					found = true
-					break
-				}
-			}
-			if !found {
-				return fmt.Errorf("representative address %s in representative mapping is not a configured wallet", representativeMapping.RepresentativeAddress.String())
+			if !configuredWallets[representativeMapping.RepresentativeAddress] {
+				return nil, fmt.Errorf("representative address %s in representative mapping is not a configured wallet", representativeMapping.RepresentativeAddress.String())
 			}
 		}
 
 		if representativeMapping.FeeRecipientAddress == (eth1Common.Address{}) && representativeMapping.PublicKey == (phase0.BLSPubKey{}) {
-			return fmt.Errorf("invalid representative mapping entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", representativeMapping.PublicKey.String(), representativeMapping.FeeRecipientAddress.String())
+			return nil, fmt.Errorf("invalid representative mapping entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", representativeMapping.PublicKey.String(), representativeMapping.FeeRecipientAddress.String())
 		}
 
 		if representativeMapping.FeeRecipientAddress != (eth1Common.Address{}) && representativeMapping.PublicKey != (phase0.BLSPubKey{}) {
-			return fmt.Errorf("invalid representative mapping entry [%s, %s], cannot specify both PublicKey and FeeRecipient in a single entry", representativeMapping.PublicKey.String(), representativeMapping.FeeRecipientAddress.String())
+			return nil, fmt.Errorf("invalid representative mapping entry [%s, %s], cannot specify both PublicKey and FeeRecipient in a single entry", representativeMapping.PublicKey.String(), representativeMapping.FeeRecipientAddress.String())
 		}
 
 		if representativeMapping.FeeRecipientAddress != (eth1Common.Address{}) {
 			if _, ok := preparedRepresentativeMapping[strings.ToLower(representativeMapping.FeeRecipientAddress.String())]; ok {
-				return fmt.Errorf("duplicate fee recipient %s in representative mapping", representativeMapping.FeeRecipientAddress.String())
+				return nil, fmt.Errorf("duplicate fee recipient %s in representative mapping", representativeMapping.FeeRecipientAddress.String())
 			}
 			if feeRecipient, ok := trackRepresentativeMapping[strings.ToLower(representativeMapping.RepresentativeAddress.String())]; ok {
-				return fmt.Errorf("this representative address %s is already specified for fee recipient %s, cannot use it for another fee recipient %s", representativeMapping.RepresentativeAddress.String(), feeRecipient.String(), representativeMapping.FeeRecipientAddress.String())
+				return nil, fmt.Errorf("this representative address %s is already specified for fee recipient %s, cannot use it for another fee recipient %s", representativeMapping.RepresentativeAddress.String(), feeRecipient.String(), representativeMapping.FeeRecipientAddress.String())
 			}
 			preparedRepresentativeMapping[strings.ToLower(representativeMapping.FeeRecipientAddress.String())] = representativeMapping.RepresentativeAddress
 			trackRepresentativeMapping[strings.ToLower(representativeMapping.RepresentativeAddress.String())] = representativeMapping.FeeRecipientAddress
 		} else if representativeMapping.PublicKey != (phase0.BLSPubKey{}) {
 			if _, ok := preparedRepresentativeMapping[strings.ToLower(representativeMapping.PublicKey.String())]; ok {
-				return fmt.Errorf("duplicate validator %s in representative mapping", representativeMapping.PublicKey.String())
+				return nil, fmt.Errorf("duplicate validator %s in representative mapping", representativeMapping.PublicKey.String())
 			}
 			preparedRepresentativeMapping[strings.ToLower(representativeMapping.PublicKey.String())] = representativeMapping.RepresentativeAddress
 		} else {
 			// should never reach here but to ensure we do not proceed past an invalid entry in the file
-			return fmt.Errorf("invalid representative mapping entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", representativeMapping.PublicKey.String(), representativeMapping.FeeRecipientAddress.String())
+			return nil, fmt.Errorf("invalid representative mapping entry [%s, %s], must specify either PublicKey or FeeRecipient in a single entry", representativeMapping.PublicKey.String(), representativeMapping.FeeRecipientAddress.String())
 		}
 
 	}
 
-	k2.representativeMapping = preparedRepresentativeMapping
-
-	if len(k2.representativeMapping) > 0 {
-		k2.log.Infof("Representative mapping updated with %d filters", len(k2.representativeMapping))
-	}
-
-	return nil
+	return preparedRepresentativeMapping, nil
 }
 
 func (k2 *K2Service) clearRepresentativeMapping() error {
-	k2.lock.Lock()
-	defer k2.lock.Unlock()
+	k2.listLock.Lock()
+	defer k2.listLock.Unlock()
 	k2.representativeMapping = make(map[string]eth1Common.Address)
 	return nil
 }
 
 func (k2 *K2Service) watchFile(label string, filePath string, readCallback func(string) error, clearCallback func() error) error {
 
+	if isRemoteList(filePath) {
+		k2.pollRemoteList(label, filePath, readCallback)
+		return nil
+	}
+
 	// Watch the file for changes use the k2.done channel to stop the watcher
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -519,6 +805,18 @@ func (k2 *K2Service) watchFile(label string, filePath string, readCallback func(
 					err := readCallback(filePath)
 					if err != nil {
 						k2.log.WithError(err).Warnf("Failed to read %s with provided callback", label)
+						k2.publish(stream.TopicLists, stream.EventListReloaded, nil, map[string]any{
+							"list":   label,
+							"file":   filePath,
+							"status": "failed",
+							"error":  err.Error(),
+						})
+					} else {
+						k2.publish(stream.TopicLists, stream.EventListReloaded, nil, map[string]any{
+							"list":   label,
+							"file":   filePath,
+							"status": "reloaded",
+						})
 					}
 				} else if (event.Op.Has(fsnotify.Remove) || event.Op.Has(fsnotify.Rename)) && event.Name == filePath {
 					// check if the file was removed
@@ -530,6 +828,12 @@ func (k2 *K2Service) watchFile(label string, filePath string, readCallback func(
 					err := clearCallback()
 					if err != nil {
 						k2.log.WithError(err).Warnf("Failed to clear %s with provided callback", label)
+					} else {
+						k2.publish(stream.TopicLists, stream.EventListReloaded, nil, map[string]any{
+							"list":   label,
+							"file":   filePath,
+							"status": "cleared",
+						})
 					}
 				}
 			case err, ok := <-watcher.Errors:
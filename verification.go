@@ -0,0 +1,122 @@
+package k2
+
+import (
+	"fmt"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/audit"
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+)
+
+// verifyEffectiveBalanceReports checks the effective balance reports signed by the balance verifier with the node
+// operator contract before they are submitted, so a wrong or stale signature is rejected before any gas is spent.
+// Returns the validators whose report is missing or invalid
+func (k2 *K2Service) verifyEffectiveBalanceReports(effectiveBalances map[phase0.BLSPubKey]uint64, signatures map[phase0.BLSPubKey]k2common.EcdsaSignature) ([]phase0.BLSPubKey, error) {
+
+	valid, err := k2.eth1.K2ValidEffectiveBalanceReports(effectiveBalances, signatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the effective balance reports: %w", err)
+	}
+
+	var invalid []phase0.BLSPubKey
+	for validator, effectiveBalance := range effectiveBalances {
+		if valid[validator] {
+			continue
+		}
+		invalid = append(invalid, validator)
+		metrics.InvalidSignatures.WithLabelValues(metrics.DependencyBalanceVerifier).Inc()
+		k2.log.WithFields(logrus.Fields{
+			"validatorPubKey":  validator.String(),
+			"effectiveBalance": effectiveBalance,
+		}).Error("balance verifier returned an invalid effective balance report signature, rejecting the report")
+	}
+
+	return invalid, nil
+}
+
+// verifyRegistrationSignatures checks the ECDSA signatures returned by the signature swapper with the proposer
+// registry, and that the signer recovered from each is a signature swapper allowed by the registry, rejecting any
+// registration the swapper did not sign or signed with a signer that is not allowed.
+// Returns the validators whose signature is missing or invalid
+func (k2 *K2Service) verifyRegistrationSignatures(registrations map[string]k2common.K2ValidatorRegistration) ([]string, error) {
+
+	var invalid []string
+	var toVerify []k2common.K2ValidatorRegistration
+	for validatorPubKey, reg := range registrations {
+		if reg.ECDSASignature == (k2common.EcdsaSignature{}) {
+			invalid = append(invalid, validatorPubKey)
+			continue
+		}
+		toVerify = append(toVerify, reg)
+	}
+
+	valid, signers, err := k2.eth1.ValidRegistrationSignatures(toVerify)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the registration signatures: %w", err)
+	}
+
+	for _, reg := range toVerify {
+		if !valid[reg.SignedValidatorRegistration.Message.Pubkey] {
+			invalid = append(invalid, reg.SignedValidatorRegistration.Message.Pubkey.String())
+		}
+	}
+
+	for _, validatorPubKey := range invalid {
+		metrics.InvalidSignatures.WithLabelValues(metrics.DependencySignatureSwapper).Inc()
+		fields := logrus.Fields{
+			"validatorPubKey":       validatorPubKey,
+			"representativeAddress": registrations[validatorPubKey].RepresentativeAddress.String(),
+		}
+		if signer, ok := signers[registrations[validatorPubKey].SignedValidatorRegistration.Message.Pubkey]; ok {
+			fields["signer"] = signer.String()
+		}
+		k2.log.WithFields(fields).Error("signature swapper returned a missing or invalid registration signature, rejecting the registration")
+	}
+
+	return invalid, nil
+}
+
+// verifyValidatorRegistrations checks the BLS signature of each incoming registration against the application
+// builder domain of the connected chain, so that a malformed or forged registration is not sent to the signature
+// swapper or on-chain. Each invalid registration is logged and audited, the valid registrations are returned along
+// with the reason each invalid registration was rejected by validator public key
+func (k2 *K2Service) verifyValidatorRegistrations(payload []apiv1.SignedValidatorRegistration) ([]apiv1.SignedValidatorRegistration, map[string]string, error) {
+
+	domain, err := k2common.ComputeBuilderDomain(k2.beacon.GenesisForkVersion())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute the builder domain: %w", err)
+	}
+
+	valid := make([]apiv1.SignedValidatorRegistration, 0, len(payload))
+	invalid := make(map[string]string)
+	for _, reg := range payload {
+
+		ok, err := k2common.VerifyRegistrationSignature(reg, domain)
+		if ok {
+			valid = append(valid, reg)
+			continue
+		}
+
+		reason := "invalid registration signature"
+		if err != nil {
+			reason = fmt.Sprintf("invalid registration signature: %v", err)
+		}
+
+		validatorPubKey := "unknown"
+		if reg.Message != nil {
+			validatorPubKey = reg.Message.Pubkey.String()
+		}
+		invalid[validatorPubKey] = reason
+
+		metrics.InvalidSignatures.WithLabelValues(metrics.SignatureSourceRegistration).Inc()
+		k2.log.WithField("validatorPubKey", validatorPubKey).Warn(reason + ", excluding the registration")
+		k2.auditValidators([]string{validatorPubKey}, ethcommon.Address{}, audit.DecisionBLSSignature, audit.OutcomeSkipped, reason, nil)
+	}
+
+	return valid, invalid, nil
+}
@@ -0,0 +1,39 @@
+package k2
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	k2common "github.com/restaking-cloud/native-delegation-for-plus/common"
+)
+
+func TestVerifyEffectiveBalanceReports_MissingSignature(t *testing.T) {
+
+	k2 := NewK2Service()
+
+	// without a signature there is nothing to check with the contract and the report is rejected
+	invalid, err := k2.verifyEffectiveBalanceReports(map[phase0.BLSPubKey]uint64{{0xa1}: 32e9}, map[phase0.BLSPubKey]k2common.EcdsaSignature{})
+	if err != nil {
+		t.Fatalf("verifyEffectiveBalanceReports() error = %v", err)
+	}
+	if len(invalid) != 1 || invalid[0] != (phase0.BLSPubKey{0xa1}) {
+		t.Errorf("invalid reports = %v, want %s", invalid, phase0.BLSPubKey{0xa1})
+	}
+}
+
+func TestVerifyRegistrationSignatures_MissingSignature(t *testing.T) {
+
+	k2 := NewK2Service()
+
+	validator := phase0.BLSPubKey{0xa1}.String()
+	invalid, err := k2.verifyRegistrationSignatures(map[string]k2common.K2ValidatorRegistration{
+		validator: testK2Registration(phase0.BLSPubKey{0xa1}),
+	})
+	if err != nil {
+		t.Fatalf("verifyRegistrationSignatures() error = %v", err)
+	}
+	if len(invalid) != 1 || invalid[0] != validator {
+		t.Errorf("invalid signatures = %v, want %s", invalid, validator)
+	}
+}
@@ -0,0 +1,76 @@
+package k2
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/ethservice"
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
+)
+
+// monitorWalletBalances periodically checks the balance of every representative wallet and estimates
+// how many batches it can still fund, so that operators are warned before transactions start failing
+func (k2 *K2Service) monitorWalletBalances() {
+
+	ticker := time.NewTicker(k2.cfg.BalanceCheckInterval)
+	defer ticker.Stop()
+
+	k2.checkWalletBalances()
+
+	for {
+		select {
+		case <-k2.exit:
+			return
+		case <-ticker.C:
+			k2.checkWalletBalances()
+		}
+	}
+}
+
+func (k2 *K2Service) checkWalletBalances() {
+
+	// only estimate the runway of the operations the module is configured to send
+	operations := []string{metrics.OperationProposerRegistry}
+	if k2.k2Enabled() {
+		operations = append(operations, metrics.OperationNativeDelegation, metrics.OperationClaim)
+	}
+
+	for _, wallet := range k2.validatorWallets() {
+		logger := k2.log.WithField("representativeAddress", wallet.Address.String())
+
+		balance, runway, err := k2.eth1.BatchRunway(wallet.Address, operations, ethservice.FullBatchSize)
+		if err != nil {
+			logger.WithError(err).Debug("Failed to estimate representative wallet runway")
+			continue
+		}
+
+		balanceEth, _ := new(big.Float).Quo(new(big.Float).SetInt(balance), big.NewFloat(1e18)).Float64()
+		metrics.WalletBalance.WithLabelValues(wallet.Address.String()).Set(balanceEth)
+		for operation, batches := range runway {
+			metrics.WalletRunway.WithLabelValues(wallet.Address.String(), operation).Set(float64(batches))
+		}
+
+		k2.statusLock.Lock()
+		k2.walletRunway[wallet.Address] = runway
+		k2.statusLock.Unlock()
+
+		for _, operation := range operations {
+			batches, ok := runway[operation]
+			if !ok {
+				continue
+			}
+			fields := logrus.Fields{
+				"balance":   balanceEth,
+				"operation": operation,
+				"batches":   batches,
+			}
+			if batches < k2.cfg.RunwayAlertThreshold {
+				logger.WithFields(fields).Errorf("Representative wallet can fund less than %v %s batches at the current gas price, top up the wallet to avoid failed transactions", k2.cfg.RunwayAlertThreshold, operation)
+			} else if batches < k2.cfg.RunwayWarningThreshold {
+				logger.WithFields(fields).Warnf("Representative wallet can fund less than %v %s batches at the current gas price", k2.cfg.RunwayWarningThreshold, operation)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package k2
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	mevcommon "github.com/pon-network/mev-plus/common"
+	"github.com/restaking-cloud/native-delegation-for-plus/config"
+)
+
+// testModuleFlags returns the flags required to configure the module with the flags added
+func testModuleFlags(flags mevcommon.ModuleFlags) mevcommon.ModuleFlags {
+	moduleFlags := mevcommon.ModuleFlags{
+		config.ExecutionNodeUrlFlag.Name: "http://localhost:8545",
+		config.BeaconNodeUrlFlag.Name:    "http://localhost:5052",
+		config.WalletPrivateKeyFlag.Name: "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+	}
+	for name, value := range flags {
+		moduleFlags[name] = value
+	}
+	return moduleFlags
+}
+
+func TestParseConfig_WalletMonitoring(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		flags        mevcommon.ModuleFlags
+		wantErr      string
+		wantInterval time.Duration
+		wantWarning  uint64
+		wantAlert    uint64
+	}{
+		{
+			name:         "defaults",
+			flags:        mevcommon.ModuleFlags{},
+			wantInterval: 5 * time.Minute,
+			wantWarning:  10,
+			wantAlert:    3,
+		},
+		{
+			name: "set",
+			flags: mevcommon.ModuleFlags{
+				config.BalanceCheckIntervalFlag.Name:   "30s",
+				config.RunwayWarningThresholdFlag.Name: "20",
+				config.RunwayAlertThresholdFlag.Name:   "5",
+			},
+			wantInterval: 30 * time.Second,
+			wantWarning:  20,
+			wantAlert:    5,
+		},
+		{
+			name:    "invalid interval",
+			flags:   mevcommon.ModuleFlags{config.BalanceCheckIntervalFlag.Name: "often"},
+			wantErr: "invalid balance check interval",
+		},
+		{
+			name:    "interval not positive",
+			flags:   mevcommon.ModuleFlags{config.BalanceCheckIntervalFlag.Name: "0s"},
+			wantErr: "balance check interval must be greater than zero",
+		},
+		{
+			name:    "invalid warning threshold",
+			flags:   mevcommon.ModuleFlags{config.RunwayWarningThresholdFlag.Name: "-1"},
+			wantErr: "invalid runway warning threshold",
+		},
+		{
+			name:    "invalid alert threshold",
+			flags:   mevcommon.ModuleFlags{config.RunwayAlertThresholdFlag.Name: "few"},
+			wantErr: "invalid runway alert threshold",
+		},
+		{
+			name: "alert above warning",
+			flags: mevcommon.ModuleFlags{
+				config.RunwayWarningThresholdFlag.Name: "2",
+				config.RunwayAlertThresholdFlag.Name:   "5",
+			},
+			wantErr: "runway alert threshold must not be greater than the runway warning threshold",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k2 := NewK2Service()
+
+			err := k2.parseConfig(testModuleFlags(tt.flags))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseConfig() error = %v", err)
+			}
+			if k2.cfg.BalanceCheckInterval != tt.wantInterval {
+				t.Errorf("balance check interval = %v, want %v", k2.cfg.BalanceCheckInterval, tt.wantInterval)
+			}
+			if k2.cfg.RunwayWarningThreshold != tt.wantWarning || k2.cfg.RunwayAlertThreshold != tt.wantAlert {
+				t.Errorf("runway thresholds = warning %d alert %d, want warning %d alert %d", k2.cfg.RunwayWarningThreshold, k2.cfg.RunwayAlertThreshold, tt.wantWarning, tt.wantAlert)
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@ import (
 	bellatrix "github.com/attestantio/go-eth2-client/spec/bellatrix"
 	phase0 "github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/restaking-cloud/native-delegation-for-plus/metrics"
 )
 
 type Web3SignerService struct {
@@ -23,7 +25,10 @@ type Web3SignerService struct {
 
 func NewWeb3SignerService() *Web3SignerService {
 	return &Web3SignerService{
-		client: &http.Client{Timeout: 192 * time.Second},
+		client: &http.Client{
+			Timeout:   192 * time.Second,
+			Transport: metrics.NewTransport(metrics.DependencyWeb3Signer),
+		},
 	}
 }
 
@@ -83,6 +88,7 @@ func (s *Web3SignerService) SignRegistration(
 	if err != nil {
 		return apiv1.SignedValidatorRegistration{}, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		return apiv1.SignedValidatorRegistration{}, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, resp.Body)
@@ -131,6 +137,7 @@ func (s *Web3SignerService) GetPubkeyList() (map[string]bool, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("invalid response (%d): %v", resp.StatusCode, resp)
@@ -159,6 +166,7 @@ func (s *Web3SignerService) UpCheck() error {
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		return fmt.Errorf("invalid response (%d): %v", resp.StatusCode, resp)